@@ -2,6 +2,7 @@
 pragma solidity ^0.8.20;
 
 import "@openzeppelin/contracts/token/ERC20/IERC20.sol";
+import "@openzeppelin/contracts/token/ERC20/extensions/IERC20Permit.sol";
 import "@openzeppelin/contracts/access/Ownable.sol";
 import "@openzeppelin/contracts/utils/ReentrancyGuard.sol";
 
@@ -12,6 +13,7 @@ import "@openzeppelin/contracts/utils/ReentrancyGuard.sol";
  * Features:
  * - Lock XZT tokens for tasks
  * - Milestone-based payments (30%, 80%, 100%)
+ * - Single-transaction task creation with EIP-2612 permit
  * - Task cancellation with refunds
  * - Admin-controlled (MVP version)
  */
@@ -80,6 +82,46 @@ contract TaskEscrow is Ownable, ReentrancyGuard {
         address executor,
         uint256 amount
     ) external onlyOwner nonReentrant returns (uint256) {
+        return _createTask(creator, executor, amount);
+    }
+    
+    /**
+     * @dev Create a new task using the creator's EIP-2612 permit, so no
+     *      separate approve transaction is needed
+     * @param creator Address of task creator (permit signer)
+     * @param executor Address of executor (can be 0x0 if not selected yet)
+     * @param amount Amount of XZT to lock (in wei), also the permit value
+     * @param deadline Permit deadline (unix seconds)
+     * @param v Permit signature v
+     * @param r Permit signature r
+     * @param s Permit signature s
+     * @return taskId The ID of created task
+     */
+    function createTaskWithPermit(
+        address creator,
+        address executor,
+        uint256 amount,
+        uint256 deadline,
+        uint8 v,
+        bytes32 r,
+        bytes32 s
+    ) external onlyOwner nonReentrant returns (uint256) {
+        // A front-run permit makes this call revert, but the allowance is
+        // already set, so fall through and let transferFrom decide
+        try IERC20Permit(address(token)).permit(creator, address(this), amount, deadline, v, r, s) {
+        } catch {}
+        
+        return _createTask(creator, executor, amount);
+    }
+    
+    /**
+     * @dev Lock XZT from creator and record the task
+     */
+    function _createTask(
+        address creator,
+        address executor,
+        uint256 amount
+    ) internal returns (uint256) {
         require(creator != address(0), "Invalid creator");
         require(amount > 0, "Amount must be positive");
         
@@ -0,0 +1,1014 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "@openzeppelin/contracts/token/ERC20/IERC20.sol";
+import "@openzeppelin/contracts/token/ERC20/extensions/IERC20Permit.sol";
+import "@openzeppelin/contracts/access/Ownable.sol";
+import "@openzeppelin/contracts/utils/ReentrancyGuard.sol";
+import "@openzeppelin/contracts/utils/cryptography/ECDSA.sol";
+import "@openzeppelin/contracts/utils/cryptography/EIP712.sol";
+
+/**
+ * @title TaskEscrowV2
+ * @dev Escrow contract for XZ Wallet task management system
+ *
+ * Same task model, events and views as TaskEscrow, but the admin can no
+ * longer move locked funds on its own:
+ * - Each task carries a milestone schedule (basis points) fixed at creation;
+ *   releaseMilestone pays exactly one scheduled slice, once, and needs an
+ *   EIP-712 signature from the task creator
+ * - cancelTask needs the creator's signature, plus the executor's when
+ *   part of the remainder goes to the executor
+ * - Both signed structs name the executor being paid and, for a team, the
+ *   hash of its members and shares, so a signature cannot be redirected by
+ *   swapping the payees
+ * - setExecutor only assigns a task that has no executor; changeExecutor
+ *   replaces one and needs the creator's signature, and resignExecutor
+ *   clears one before anything is paid with the executor's signature
+ * - The admin only relays signed requests (and pays the gas)
+ * - Before an executor is set, refundPartial can lower a task's amount;
+ *   the difference only ever goes back to the creator, and topUp can raise
+ *   it with more XZT from the creator, with the creator's TopUp signature
+ *   or token permit
+ * - createTasks locks the total for many tasks with one transferFrom
+ * - A task can be worked by a team: setExecutorTeam fixes each member's
+ *   share in basis points and every payment to the executor is split
+ *   among the members; the first member (the lead) signs as executor.
+ *   Like setExecutor, it only assigns a task that has no executor yet.
+ *
+ * Each task has a nonce that every signed action consumes, so a signature
+ * can be used once.
+ *
+ * Emergency withdrawals are two-step and timelocked, limited to tokens in
+ * excess of what open tasks still hold, and capped per period.
+ */
+contract TaskEscrowV2 is Ownable, ReentrancyGuard, EIP712 {
+
+    IERC20 public immutable token;
+
+    bytes32 public constant RELEASE_MILESTONE_TYPEHASH = keccak256(
+        "ReleaseMilestone(uint256 taskId,uint256 index,address executor,bytes32 team,uint256 nonce,uint256 deadline)"
+    );
+
+    bytes32 public constant CANCEL_TASK_TYPEHASH = keccak256(
+        "CancelTask(uint256 taskId,uint256 executorAmount,address executor,bytes32 team,uint256 nonce,uint256 deadline)"
+    );
+
+    bytes32 public constant CHANGE_EXECUTOR_TYPEHASH = keccak256(
+        "ChangeExecutor(uint256 taskId,address executor,uint256 nonce,uint256 deadline)"
+    );
+
+    bytes32 public constant RESIGN_EXECUTOR_TYPEHASH = keccak256(
+        "ResignExecutor(uint256 taskId,address executor,uint256 nonce,uint256 deadline)"
+    );
+
+    bytes32 public constant TOP_UP_TYPEHASH = keccak256(
+        "TopUp(uint256 taskId,uint256 amount,uint256 nonce,uint256 deadline)"
+    );
+
+    // Basis points in a full task reward
+    uint256 public constant BPS_DENOMINATOR = 10000;
+
+    // Most milestones a schedule may have
+    uint256 public constant MAX_MILESTONES = 10;
+
+    // Most tasks createTasks may create at once
+    uint256 public constant MAX_BATCH_SIZE = 25;
+
+    // Most members an executor team may have
+    uint256 public constant MAX_TEAM_SIZE = 10;
+
+    // Delay between proposing and executing an emergency withdrawal
+    uint256 public constant WITHDRAW_DELAY = 2 days;
+
+    // Window for the per-period withdrawal limit
+    uint256 public constant WITHDRAW_PERIOD = 7 days;
+
+    struct Task {
+        address creator;      // Task creator
+        address executor;     // Task executor (can be 0x0 initially)
+        uint256 totalAmount;  // Total XZT locked
+        uint256 paidAmount;   // Amount already paid to executor
+        bool cancelled;       // Whether task is cancelled
+    }
+
+    // taskId => Task
+    mapping(uint256 => Task) public tasks;
+
+    // taskId => milestone shares in basis points (sum to BPS_DENOMINATOR)
+    mapping(uint256 => uint16[]) internal milestoneSchedules;
+
+    // taskId => bitmask of released milestone indexes
+    mapping(uint256 => uint256) public releasedMilestones;
+
+    // taskId => nonce of the next signed action
+    mapping(uint256 => uint256) public taskNonces;
+
+    struct TeamMember {
+        address account;   // Payout address of the member
+        uint16 shareBps;   // Share of each executor payment in basis points
+    }
+
+    // taskId => executor team (empty when a single executor is paid)
+    mapping(uint256 => TeamMember[]) internal teams;
+
+    // Next task ID (auto-increment)
+    uint256 public nextTaskId;
+
+    // Sum of remaining balances of all tasks (funds owed to users)
+    uint256 public totalOutstanding;
+
+    struct Withdrawal {
+        address to;
+        uint256 amount;
+        uint256 executableAt;  // Earliest execution time
+        bool executed;
+        bool cancelled;
+    }
+
+    // withdrawalId => Withdrawal
+    mapping(uint256 => Withdrawal) public withdrawals;
+
+    // Next withdrawal ID (auto-increment)
+    uint256 public nextWithdrawalId;
+
+    // Most the owner can withdraw per WITHDRAW_PERIOD
+    uint256 public immutable withdrawPeriodLimit;
+
+    // Start of the current withdrawal period and amount withdrawn in it
+    uint256 public withdrawPeriodStart;
+    uint256 public withdrawnInPeriod;
+
+    // Events
+    event TaskCreated(
+        uint256 indexed taskId,
+        address indexed creator,
+        address indexed executor,
+        uint256 amount
+    );
+
+    event ExecutorSet(
+        uint256 indexed taskId,
+        address indexed executor
+    );
+
+    event MilestonePaid(
+        uint256 indexed taskId,
+        address indexed executor,
+        uint256 amount,
+        uint256 totalPaid
+    );
+
+    event TaskCancelled(
+        uint256 indexed taskId,
+        uint256 executorAmount,
+        uint256 creatorRefund
+    );
+
+    event TaskRefunded(
+        uint256 indexed taskId,
+        address indexed creator,
+        uint256 amount,
+        uint256 newTotal
+    );
+
+    event TaskToppedUp(
+        uint256 indexed taskId,
+        address indexed creator,
+        uint256 amount,
+        uint256 newTotal
+    );
+
+    event MilestoneReleased(
+        uint256 indexed taskId,
+        uint256 indexed index,
+        uint256 amount
+    );
+
+    event TeamSet(
+        uint256 indexed taskId,
+        address[] members,
+        uint16[] shareBps
+    );
+
+    event TeamMemberPaid(
+        uint256 indexed taskId,
+        address indexed member,
+        uint256 amount
+    );
+
+    event WithdrawalProposed(
+        uint256 indexed withdrawalId,
+        address indexed to,
+        uint256 amount,
+        uint256 executableAt
+    );
+
+    event WithdrawalExecuted(
+        uint256 indexed withdrawalId,
+        address indexed to,
+        uint256 amount
+    );
+
+    event WithdrawalCancelled(
+        uint256 indexed withdrawalId
+    );
+
+    /**
+     * @dev Constructor
+     * @param _token Address of XZT token contract
+     * @param _withdrawPeriodLimit Most XZT (in wei) emergency withdrawals may move per WITHDRAW_PERIOD
+     */
+    constructor(address _token, uint256 _withdrawPeriodLimit) Ownable(msg.sender) EIP712("TaskEscrow", "2") {
+        require(_token != address(0), "Invalid token address");
+        token = IERC20(_token);
+        withdrawPeriodLimit = _withdrawPeriodLimit;
+    }
+
+    /**
+     * @dev EIP-712 domain separator for signed task actions
+     */
+    function DOMAIN_SEPARATOR() external view returns (bytes32) {
+        return _domainSeparatorV4();
+    }
+
+    /**
+     * @dev Create a new task and lock XZT
+     * @param creator Address of task creator
+     * @param executor Address of executor (can be 0x0 if not selected yet)
+     * @param amount Amount of XZT to lock (in wei)
+     * @param milestoneBps Milestone shares in basis points, summing to 10000
+     * @return taskId The ID of created task
+     */
+    function createTask(
+        address creator,
+        address executor,
+        uint256 amount,
+        uint16[] calldata milestoneBps
+    ) external onlyOwner nonReentrant returns (uint256) {
+        return _createTask(creator, executor, amount, milestoneBps);
+    }
+
+    /**
+     * @dev Create a new task using the creator's EIP-2612 permit
+     * @param creator Address of task creator (permit signer)
+     * @param executor Address of executor (can be 0x0 if not selected yet)
+     * @param amount Amount of XZT to lock (in wei), also the permit value
+     * @param milestoneBps Milestone shares in basis points, summing to 10000
+     * @param deadline Permit deadline (unix seconds)
+     * @param v Permit signature v
+     * @param r Permit signature r
+     * @param s Permit signature s
+     * @return taskId The ID of created task
+     */
+    function createTaskWithPermit(
+        address creator,
+        address executor,
+        uint256 amount,
+        uint16[] calldata milestoneBps,
+        uint256 deadline,
+        uint8 v,
+        bytes32 r,
+        bytes32 s
+    ) external onlyOwner nonReentrant returns (uint256) {
+        try IERC20Permit(address(token)).permit(creator, address(this), amount, deadline, v, r, s) {
+        } catch {}
+
+        return _createTask(creator, executor, amount, milestoneBps);
+    }
+
+    /**
+     * @dev Create several tasks for one creator, locking their total with a
+     *      single transfer. Emits TaskCreated for each task, in order.
+     * @param creator Address of task creator
+     * @param amounts Amount of XZT to lock per task (in wei)
+     * @param milestoneBps Milestone schedule per task, each summing to 10000
+     * @return taskIds IDs of the created tasks, in order
+     */
+    function createTasks(
+        address creator,
+        uint256[] calldata amounts,
+        uint16[][] calldata milestoneBps
+    ) external onlyOwner nonReentrant returns (uint256[] memory) {
+        return _createTasks(creator, amounts, milestoneBps);
+    }
+
+    /**
+     * @dev Create several tasks using the creator's EIP-2612 permit for their total
+     * @param creator Address of task creator (permit signer)
+     * @param amounts Amount of XZT to lock per task (in wei); the permit value is their sum
+     * @param milestoneBps Milestone schedule per task, each summing to 10000
+     * @param deadline Permit deadline (unix seconds)
+     * @param v Permit signature v
+     * @param r Permit signature r
+     * @param s Permit signature s
+     * @return taskIds IDs of the created tasks, in order
+     */
+    function createTasksWithPermit(
+        address creator,
+        uint256[] calldata amounts,
+        uint16[][] calldata milestoneBps,
+        uint256 deadline,
+        uint8 v,
+        bytes32 r,
+        bytes32 s
+    ) external onlyOwner nonReentrant returns (uint256[] memory) {
+        uint256 total;
+        for (uint256 i = 0; i < amounts.length; i++) {
+            total += amounts[i];
+        }
+        try IERC20Permit(address(token)).permit(creator, address(this), total, deadline, v, r, s) {
+        } catch {}
+
+        return _createTasks(creator, amounts, milestoneBps);
+    }
+
+    /**
+     * @dev Lock XZT from creator and record the task with its milestone schedule
+     */
+    function _createTask(
+        address creator,
+        address executor,
+        uint256 amount,
+        uint16[] calldata milestoneBps
+    ) internal returns (uint256) {
+        require(creator != address(0), "Invalid creator");
+
+        uint256 taskId = _recordTask(creator, executor, amount, milestoneBps);
+
+        require(
+            token.transferFrom(creator, address(this), amount),
+            "Transfer failed"
+        );
+
+        return taskId;
+    }
+
+    /**
+     * @dev Record a batch of tasks, then lock their total from creator
+     */
+    function _createTasks(
+        address creator,
+        uint256[] calldata amounts,
+        uint16[][] calldata milestoneBps
+    ) internal returns (uint256[] memory taskIds) {
+        require(creator != address(0), "Invalid creator");
+        require(
+            amounts.length > 0 && amounts.length <= MAX_BATCH_SIZE,
+            "Invalid batch size"
+        );
+        require(amounts.length == milestoneBps.length, "Length mismatch");
+
+        taskIds = new uint256[](amounts.length);
+        uint256 total;
+        for (uint256 i = 0; i < amounts.length; i++) {
+            taskIds[i] = _recordTask(creator, address(0), amounts[i], milestoneBps[i]);
+            total += amounts[i];
+        }
+
+        require(
+            token.transferFrom(creator, address(this), total),
+            "Transfer failed"
+        );
+    }
+
+    /**
+     * @dev Validate the amount and milestone schedule and record the task
+     */
+    function _recordTask(
+        address creator,
+        address executor,
+        uint256 amount,
+        uint16[] calldata milestoneBps
+    ) internal returns (uint256) {
+        require(amount > 0, "Amount must be positive");
+        require(
+            milestoneBps.length > 0 && milestoneBps.length <= MAX_MILESTONES,
+            "Invalid milestone count"
+        );
+
+        uint256 totalBps;
+        for (uint256 i = 0; i < milestoneBps.length; i++) {
+            require(milestoneBps[i] > 0, "Empty milestone");
+            totalBps += milestoneBps[i];
+        }
+        require(totalBps == BPS_DENOMINATOR, "Milestones must sum to 10000 bps");
+
+        uint256 taskId = nextTaskId++;
+        totalOutstanding += amount;
+
+        tasks[taskId] = Task({
+            creator: creator,
+            executor: executor,
+            totalAmount: amount,
+            paidAmount: 0,
+            cancelled: false
+        });
+        milestoneSchedules[taskId] = milestoneBps;
+
+        emit TaskCreated(taskId, creator, executor, amount);
+
+        return taskId;
+    }
+
+    /**
+     * @dev Set the executor of a task that has none yet
+     * @param taskId ID of the task
+     * @param executor Address of the executor
+     */
+    function setExecutor(
+        uint256 taskId,
+        address executor
+    ) external onlyOwner {
+        require(taskId < nextTaskId, "Task does not exist");
+        require(executor != address(0), "Invalid executor");
+
+        Task storage task = tasks[taskId];
+        require(!task.cancelled, "Task is cancelled");
+        require(task.executor == address(0), "Executor already set");
+
+        task.executor = executor;
+
+        emit ExecutorSet(taskId, executor);
+    }
+
+    /**
+     * @dev Replace a task's executor, authorized by the creator. Replaces
+     *      any executor team.
+     * @param taskId ID of the task
+     * @param executor Address of the new executor
+     * @param deadline Signature deadline (unix seconds)
+     * @param creatorSignature Creator's EIP-712 ChangeExecutor signature
+     */
+    function changeExecutor(
+        uint256 taskId,
+        address executor,
+        uint256 deadline,
+        bytes calldata creatorSignature
+    ) external onlyOwner {
+        require(taskId < nextTaskId, "Task does not exist");
+        require(block.timestamp <= deadline, "Signature expired");
+        require(executor != address(0), "Invalid executor");
+
+        Task storage task = tasks[taskId];
+        require(!task.cancelled, "Task is cancelled");
+
+        bytes32 digest = _hashTypedDataV4(keccak256(abi.encode(
+            CHANGE_EXECUTOR_TYPEHASH,
+            taskId,
+            executor,
+            taskNonces[taskId]++,
+            deadline
+        )));
+        require(ECDSA.recover(digest, creatorSignature) == task.creator, "Invalid creator signature");
+
+        task.executor = executor;
+        delete teams[taskId];
+
+        emit ExecutorSet(taskId, executor);
+    }
+
+    /**
+     * @dev Clear a task's executor (and team) at the executor's request, e.g.
+     *      when a directly assigned executor declines. Only before anything is
+     *      paid; the task can then be assigned again.
+     * @param taskId ID of the task
+     * @param deadline Signature deadline (unix seconds)
+     * @param executorSignature Executor's EIP-712 ResignExecutor signature
+     */
+    function resignExecutor(
+        uint256 taskId,
+        uint256 deadline,
+        bytes calldata executorSignature
+    ) external onlyOwner {
+        require(taskId < nextTaskId, "Task does not exist");
+        require(block.timestamp <= deadline, "Signature expired");
+
+        Task storage task = tasks[taskId];
+        require(!task.cancelled, "Task is cancelled");
+        require(task.executor != address(0), "No executor set");
+        require(task.paidAmount == 0, "Payments already made");
+
+        bytes32 digest = _hashTypedDataV4(keccak256(abi.encode(
+            RESIGN_EXECUTOR_TYPEHASH,
+            taskId,
+            task.executor,
+            taskNonces[taskId]++,
+            deadline
+        )));
+        require(ECDSA.recover(digest, executorSignature) == task.executor, "Invalid executor signature");
+
+        task.executor = address(0);
+        delete teams[taskId];
+
+        emit ExecutorSet(taskId, address(0));
+    }
+
+    /**
+     * @dev Set a team of executors that split every executor payment. The
+     *      first member becomes the task executor and signs for the team.
+     *      Only on a task without an executor, so the payees are never
+     *      replaced without the creator's signature.
+     * @param taskId ID of the task
+     * @param members Payout addresses of the members, lead first
+     * @param shareBps Each member's share in basis points, summing to 10000
+     */
+    function setExecutorTeam(
+        uint256 taskId,
+        address[] calldata members,
+        uint16[] calldata shareBps
+    ) external onlyOwner {
+        require(taskId < nextTaskId, "Task does not exist");
+        require(
+            members.length > 1 && members.length <= MAX_TEAM_SIZE,
+            "Invalid team size"
+        );
+        require(members.length == shareBps.length, "Length mismatch");
+
+        Task storage task = tasks[taskId];
+        require(!task.cancelled, "Task is cancelled");
+        require(task.executor == address(0), "Executor already set");
+
+        uint256 totalBps;
+        for (uint256 i = 0; i < members.length; i++) {
+            require(members[i] != address(0), "Invalid member");
+            require(shareBps[i] > 0, "Empty share");
+            for (uint256 j = 0; j < i; j++) {
+                require(members[j] != members[i], "Duplicate member");
+            }
+            totalBps += shareBps[i];
+            teams[taskId].push(TeamMember({account: members[i], shareBps: shareBps[i]}));
+        }
+        require(totalBps == BPS_DENOMINATOR, "Shares must sum to 10000 bps");
+
+        task.executor = members[0];
+
+        emit ExecutorSet(taskId, members[0]);
+        emit TeamSet(taskId, members, shareBps);
+    }
+
+    /**
+     * @dev Get a task's executor team (empty for a single executor)
+     * @param taskId ID of the task
+     */
+    function getTeam(uint256 taskId) external view returns (
+        address[] memory members,
+        uint16[] memory shareBps
+    ) {
+        require(taskId < nextTaskId, "Task does not exist");
+        TeamMember[] storage team = teams[taskId];
+        members = new address[](team.length);
+        shareBps = new uint16[](team.length);
+        for (uint256 i = 0; i < team.length; i++) {
+            members[i] = team[i].account;
+            shareBps[i] = team[i].shareBps;
+        }
+    }
+
+    /**
+     * @dev Hash of a task's team as signed in releases and cancels:
+     *      keccak256(abi.encode(members, shareBps)), or zero without a team
+     * @param taskId ID of the task
+     */
+    function teamHash(uint256 taskId) public view returns (bytes32) {
+        TeamMember[] storage team = teams[taskId];
+        if (team.length == 0) {
+            return bytes32(0);
+        }
+        address[] memory members = new address[](team.length);
+        uint16[] memory shareBps = new uint16[](team.length);
+        for (uint256 i = 0; i < team.length; i++) {
+            members[i] = team[i].account;
+            shareBps[i] = team[i].shareBps;
+        }
+        return keccak256(abi.encode(members, shareBps));
+    }
+
+    /**
+     * @dev Pay the executor, or split the amount among the team like
+     *      milestones: shares round down and the last member takes the rest
+     */
+    function _payExecutor(uint256 taskId, uint256 amount) internal {
+        TeamMember[] storage team = teams[taskId];
+        if (team.length == 0) {
+            require(
+                token.transfer(tasks[taskId].executor, amount),
+                "Transfer failed"
+            );
+            return;
+        }
+
+        uint256 paid;
+        for (uint256 i = 0; i < team.length; i++) {
+            uint256 share = i < team.length - 1
+                ? amount * team[i].shareBps / BPS_DENOMINATOR
+                : amount - paid;
+            paid += share;
+            if (share > 0) {
+                require(
+                    token.transfer(team[i].account, share),
+                    "Transfer failed"
+                );
+            }
+            emit TeamMemberPaid(taskId, team[i].account, share);
+        }
+    }
+
+    /**
+     * @dev Refund part of a task's locked amount to its creator, e.g. when a
+     *      bid is accepted below the reward. Only before an executor is set,
+     *      so no executor can be owed the difference.
+     * @param taskId ID of the task
+     * @param amount Amount to refund (in wei); less than the task's total
+     */
+    function refundPartial(
+        uint256 taskId,
+        uint256 amount
+    ) external onlyOwner nonReentrant {
+        require(taskId < nextTaskId, "Task does not exist");
+
+        Task storage task = tasks[taskId];
+        require(!task.cancelled, "Task is cancelled");
+        require(task.executor == address(0), "Executor already set");
+        require(amount > 0, "Amount must be greater than 0");
+        require(amount < task.totalAmount - task.paidAmount, "Exceeds remaining amount");
+
+        task.totalAmount -= amount;
+        totalOutstanding -= amount;
+
+        require(
+            token.transfer(task.creator, amount),
+            "Creator refund failed"
+        );
+
+        emit TaskRefunded(taskId, task.creator, amount, task.totalAmount);
+    }
+
+    /**
+     * @dev Lock more XZT from the creator for a task, e.g. to raise its
+     *      reward while bidding, authorized by the creator. Only before an
+     *      executor is set, so milestone slices are all computed from the
+     *      final amount. The creator's allowance must cover the amount.
+     * @param taskId ID of the task
+     * @param amount Amount to add (in wei)
+     * @param deadline Signature deadline (unix seconds)
+     * @param creatorSignature Creator's EIP-712 TopUp signature
+     */
+    function topUp(
+        uint256 taskId,
+        uint256 amount,
+        uint256 deadline,
+        bytes calldata creatorSignature
+    ) external onlyOwner nonReentrant {
+        require(taskId < nextTaskId, "Task does not exist");
+        require(block.timestamp <= deadline, "Signature expired");
+
+        bytes32 digest = _hashTypedDataV4(keccak256(abi.encode(
+            TOP_UP_TYPEHASH,
+            taskId,
+            amount,
+            taskNonces[taskId]++,
+            deadline
+        )));
+        require(ECDSA.recover(digest, creatorSignature) == tasks[taskId].creator, "Invalid creator signature");
+
+        _topUp(taskId, amount);
+    }
+
+    /**
+     * @dev Lock more XZT for a task using the creator's EIP-2612 permit. The
+     *      permit is the creator's authorization, so unlike createTaskWithPermit
+     *      a failed permit is not skipped in favour of an existing allowance.
+     * @param taskId ID of the task
+     * @param amount Amount to add (in wei), also the permit value
+     * @param deadline Permit deadline (unix seconds)
+     * @param v Permit signature v
+     * @param r Permit signature r
+     * @param s Permit signature s
+     */
+    function topUpWithPermit(
+        uint256 taskId,
+        uint256 amount,
+        uint256 deadline,
+        uint8 v,
+        bytes32 r,
+        bytes32 s
+    ) external onlyOwner nonReentrant {
+        require(taskId < nextTaskId, "Task does not exist");
+        IERC20Permit(address(token)).permit(tasks[taskId].creator, address(this), amount, deadline, v, r, s);
+
+        _topUp(taskId, amount);
+    }
+
+    /**
+     * @dev Add amount from the creator to an unassigned task
+     */
+    function _topUp(uint256 taskId, uint256 amount) internal {
+        require(taskId < nextTaskId, "Task does not exist");
+
+        Task storage task = tasks[taskId];
+        require(!task.cancelled, "Task is cancelled");
+        require(task.executor == address(0), "Executor already set");
+        require(amount > 0, "Amount must be greater than 0");
+
+        task.totalAmount += amount;
+        totalOutstanding += amount;
+
+        require(
+            token.transferFrom(task.creator, address(this), amount),
+            "Transfer failed"
+        );
+
+        emit TaskToppedUp(taskId, task.creator, amount, task.totalAmount);
+    }
+
+    /**
+     * @dev Pay one scheduled milestone to the executor, authorized by the
+     *      creator for that executor
+     * @param taskId ID of the task
+     * @param index Milestone index in the task's schedule
+     * @param deadline Signature deadline (unix seconds)
+     * @param creatorSignature Creator's EIP-712 ReleaseMilestone signature
+     */
+    function releaseMilestone(
+        uint256 taskId,
+        uint256 index,
+        uint256 deadline,
+        bytes calldata creatorSignature
+    ) external onlyOwner nonReentrant {
+        require(taskId < nextTaskId, "Task does not exist");
+        require(block.timestamp <= deadline, "Signature expired");
+
+        Task storage task = tasks[taskId];
+        require(!task.cancelled, "Task is cancelled");
+        require(task.executor != address(0), "No executor set");
+        require(index < milestoneSchedules[taskId].length, "Invalid milestone");
+        require(releasedMilestones[taskId] & (1 << index) == 0, "Milestone already released");
+
+        bytes32 digest = _releaseDigest(taskId, index, deadline);
+        require(ECDSA.recover(digest, creatorSignature) == task.creator, "Invalid creator signature");
+
+        uint256 amount = _milestoneAmount(taskId, index);
+        releasedMilestones[taskId] |= 1 << index;
+        task.paidAmount += amount;
+        totalOutstanding -= amount;
+
+        _payExecutor(taskId, amount);
+
+        emit MilestoneReleased(taskId, index, amount);
+        emit MilestonePaid(taskId, task.executor, amount, task.paidAmount);
+    }
+
+    /**
+     * @dev Slice of the task reward for a milestone. The last milestone takes
+     *      whatever rounding left over, so the slices always add up to the total.
+     */
+    function _milestoneAmount(uint256 taskId, uint256 index) internal view returns (uint256) {
+        uint16[] storage schedule = milestoneSchedules[taskId];
+        uint256 total = tasks[taskId].totalAmount;
+        if (index < schedule.length - 1) {
+            return total * schedule[index] / BPS_DENOMINATOR;
+        }
+        uint256 others;
+        for (uint256 i = 0; i < schedule.length - 1; i++) {
+            others += total * schedule[i] / BPS_DENOMINATOR;
+        }
+        return total - others;
+    }
+
+    /**
+     * @dev Get a task's milestone schedule and which milestones are released
+     * @param taskId ID of the task
+     */
+    function getMilestones(uint256 taskId) external view returns (
+        uint16[] memory milestoneBps,
+        uint256 releasedMask
+    ) {
+        require(taskId < nextTaskId, "Task does not exist");
+        return (milestoneSchedules[taskId], releasedMilestones[taskId]);
+    }
+
+    /**
+     * @dev Get the amount a milestone pays
+     * @param taskId ID of the task
+     * @param index Milestone index in the task's schedule
+     */
+    function milestoneAmount(uint256 taskId, uint256 index) external view returns (uint256) {
+        require(taskId < nextTaskId, "Task does not exist");
+        require(index < milestoneSchedules[taskId].length, "Invalid milestone");
+        return _milestoneAmount(taskId, index);
+    }
+
+    /**
+     * @dev Cancel task with refund distribution. The creator always signs;
+     *      the executor also signs when executorAmount is not zero. The
+     *      signed struct names the current executor (zero if none).
+     * @param taskId ID of the task
+     * @param executorAmount Amount to pay executor (in wei)
+     * @param deadline Signature deadline (unix seconds)
+     * @param creatorSignature Creator's EIP-712 CancelTask signature
+     * @param executorSignature Executor's EIP-712 CancelTask signature (empty if executorAmount is 0)
+     */
+    function cancelTask(
+        uint256 taskId,
+        uint256 executorAmount,
+        uint256 deadline,
+        bytes calldata creatorSignature,
+        bytes calldata executorSignature
+    ) external onlyOwner nonReentrant {
+        require(taskId < nextTaskId, "Task does not exist");
+        require(block.timestamp <= deadline, "Signature expired");
+
+        Task storage task = tasks[taskId];
+        require(!task.cancelled, "Already cancelled");
+
+        uint256 remaining = task.totalAmount - task.paidAmount;
+        require(executorAmount <= remaining, "Exceeds remaining amount");
+
+        bytes32 digest = _cancelDigest(taskId, executorAmount, deadline);
+        require(ECDSA.recover(digest, creatorSignature) == task.creator, "Invalid creator signature");
+        if (executorAmount > 0) {
+            require(task.executor != address(0), "No executor set");
+            require(ECDSA.recover(digest, executorSignature) == task.executor, "Invalid executor signature");
+        }
+
+        task.cancelled = true;
+        task.paidAmount = task.totalAmount; // Mark as fully paid
+        totalOutstanding -= remaining;
+
+        if (executorAmount > 0) {
+            _payExecutor(taskId, executorAmount);
+        }
+
+        uint256 creatorRefund = remaining - executorAmount;
+        if (creatorRefund > 0) {
+            require(
+                token.transfer(task.creator, creatorRefund),
+                "Creator refund failed"
+            );
+        }
+
+        emit TaskCancelled(taskId, executorAmount, creatorRefund);
+    }
+
+    /**
+     * @dev EIP-712 digest of a ReleaseMilestone for the task's current
+     *      executor and team; consumes the task nonce
+     */
+    function _releaseDigest(
+        uint256 taskId,
+        uint256 index,
+        uint256 deadline
+    ) internal returns (bytes32) {
+        return _hashTypedDataV4(keccak256(abi.encode(
+            RELEASE_MILESTONE_TYPEHASH,
+            taskId,
+            index,
+            tasks[taskId].executor,
+            teamHash(taskId),
+            taskNonces[taskId]++,
+            deadline
+        )));
+    }
+
+    /**
+     * @dev EIP-712 digest of a CancelTask for the task's current executor
+     *      and team; consumes the task nonce
+     */
+    function _cancelDigest(
+        uint256 taskId,
+        uint256 executorAmount,
+        uint256 deadline
+    ) internal returns (bytes32) {
+        return _hashTypedDataV4(keccak256(abi.encode(
+            CANCEL_TASK_TYPEHASH,
+            taskId,
+            executorAmount,
+            tasks[taskId].executor,
+            teamHash(taskId),
+            taskNonces[taskId]++,
+            deadline
+        )));
+    }
+
+    /**
+     * @dev Get task details
+     * @param taskId ID of the task
+     */
+    function getTask(uint256 taskId) external view returns (
+        address creator,
+        address executor,
+        uint256 totalAmount,
+        uint256 paidAmount,
+        bool cancelled
+    ) {
+        require(taskId < nextTaskId, "Task does not exist");
+        Task memory task = tasks[taskId];
+        return (
+            task.creator,
+            task.executor,
+            task.totalAmount,
+            task.paidAmount,
+            task.cancelled
+        );
+    }
+
+    /**
+     * @dev Get remaining amount for a task
+     * @param taskId ID of the task
+     */
+    function getRemainingAmount(uint256 taskId) external view returns (uint256) {
+        require(taskId < nextTaskId, "Task does not exist");
+        Task memory task = tasks[taskId];
+        return task.totalAmount - task.paidAmount;
+    }
+
+    /**
+     * @dev Tokens held beyond the remaining balances of open tasks
+     */
+    function withdrawableExcess() public view returns (uint256) {
+        uint256 balance = token.balanceOf(address(this));
+        return balance > totalOutstanding ? balance - totalOutstanding : 0;
+    }
+
+    /**
+     * @dev Propose an emergency withdrawal of excess tokens, executable after WITHDRAW_DELAY
+     * @param to Address to send tokens
+     * @param amount Amount to withdraw
+     * @return withdrawalId The ID of the proposal
+     */
+    function proposeEmergencyWithdraw(
+        address to,
+        uint256 amount
+    ) external onlyOwner returns (uint256) {
+        require(to != address(0), "Invalid address");
+        require(amount > 0, "Amount must be positive");
+        require(amount <= withdrawableExcess(), "Exceeds excess balance");
+
+        uint256 withdrawalId = nextWithdrawalId++;
+        uint256 executableAt = block.timestamp + WITHDRAW_DELAY;
+
+        withdrawals[withdrawalId] = Withdrawal({
+            to: to,
+            amount: amount,
+            executableAt: executableAt,
+            executed: false,
+            cancelled: false
+        });
+
+        emit WithdrawalProposed(withdrawalId, to, amount, executableAt);
+
+        return withdrawalId;
+    }
+
+    /**
+     * @dev Execute a proposed withdrawal once its timelock has passed. The
+     *      excess is checked again, so task funds stay untouchable.
+     * @param withdrawalId ID of the proposal
+     */
+    function executeEmergencyWithdraw(
+        uint256 withdrawalId
+    ) external onlyOwner nonReentrant {
+        require(withdrawalId < nextWithdrawalId, "Withdrawal does not exist");
+
+        Withdrawal storage withdrawal = withdrawals[withdrawalId];
+        require(!withdrawal.executed, "Already executed");
+        require(!withdrawal.cancelled, "Withdrawal is cancelled");
+        require(block.timestamp >= withdrawal.executableAt, "Timelock not expired");
+        require(withdrawal.amount <= withdrawableExcess(), "Exceeds excess balance");
+
+        if (block.timestamp >= withdrawPeriodStart + WITHDRAW_PERIOD) {
+            withdrawPeriodStart = block.timestamp;
+            withdrawnInPeriod = 0;
+        }
+        require(
+            withdrawnInPeriod + withdrawal.amount <= withdrawPeriodLimit,
+            "Exceeds period limit"
+        );
+
+        withdrawnInPeriod += withdrawal.amount;
+        withdrawal.executed = true;
+
+        require(token.transfer(withdrawal.to, withdrawal.amount), "Transfer failed");
+
+        emit WithdrawalExecuted(withdrawalId, withdrawal.to, withdrawal.amount);
+    }
+
+    /**
+     * @dev Cancel a proposed withdrawal
+     * @param withdrawalId ID of the proposal
+     */
+    function cancelEmergencyWithdraw(
+        uint256 withdrawalId
+    ) external onlyOwner {
+        require(withdrawalId < nextWithdrawalId, "Withdrawal does not exist");
+
+        Withdrawal storage withdrawal = withdrawals[withdrawalId];
+        require(!withdrawal.executed, "Already executed");
+        require(!withdrawal.cancelled, "Already cancelled");
+
+        withdrawal.cancelled = true;
+
+        emit WithdrawalCancelled(withdrawalId);
+    }
+}
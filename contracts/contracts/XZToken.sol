@@ -2,6 +2,7 @@
 pragma solidity ^0.8.20;
 
 import "@openzeppelin/contracts/token/ERC20/ERC20.sol";
+import "@openzeppelin/contracts/token/ERC20/extensions/ERC20Permit.sol";
 import "@openzeppelin/contracts/access/Ownable.sol";
 
 /**
@@ -11,13 +12,14 @@ import "@openzeppelin/contracts/access/Ownable.sol";
  * Initial Supply: 10,000 XZT
  * Decimals: 18
  * Network: Sepolia Testnet
+ * Permit: EIP-2612 (users approve the escrow by signature, no gas needed)
  */
-contract XZToken is ERC20, Ownable {
+contract XZToken is ERC20, ERC20Permit, Ownable {
     
     /**
      * @dev Constructor mints initial supply to deployer
      */
-    constructor() ERC20("XZ Token", "XZT") Ownable(msg.sender) {
+    constructor() ERC20("XZ Token", "XZT") ERC20Permit("XZ Token") Ownable(msg.sender) {
         // Mint 10,000 XZT to deployer (system wallet)
         _mint(msg.sender, 10000 * 10**decimals());
     }
@@ -6,6 +6,8 @@ module.exports = {
   solidity: {
     version: "0.8.20",
     settings: {
+      // No PUSH0, so the Go tests' simulated chain (pre-Shanghai) can run the artifacts
+      evmVersion: "paris",
       optimizer: {
         enabled: true,
         runs: 200
@@ -25,13 +25,17 @@ async function main() {
   const deployerBalance = await token.balanceOf(deployer.address);
   console.log("   Deployer balance:", hre.ethers.formatEther(deployerBalance), "XZT\n");
 
-  // Deploy TaskEscrow
-  console.log("📦 Deploying TaskEscrow...");
-  const TaskEscrow = await hre.ethers.getContractFactory("TaskEscrow");
-  const escrow = await TaskEscrow.deploy(tokenAddress);
+  // Deploy TaskEscrowV2. v1 is not deployed any more: its emergencyWithdraw
+  // moves any amount at once, without a timelock
+  const escrowContract = "TaskEscrowV2";
+  console.log(`📦 Deploying ${escrowContract}...`);
+  const TaskEscrow = await hre.ethers.getContractFactory(escrowContract);
+  // Caps emergency withdrawals per period (ESCROW_WITHDRAW_PERIOD_LIMIT, in XZT)
+  const withdrawPeriodLimit = hre.ethers.parseEther(process.env.ESCROW_WITHDRAW_PERIOD_LIMIT || "1000");
+  const escrow = await TaskEscrow.deploy(tokenAddress, withdrawPeriodLimit);
   await escrow.waitForDeployment();
   const escrowAddress = await escrow.getAddress();
-  console.log("✅ TaskEscrow deployed to:", escrowAddress);
+  console.log(`✅ ${escrowContract} deployed to:`, escrowAddress);
   console.log("   Token address:", await escrow.token());
   console.log("   Owner:", await escrow.owner(), "\n");
 
@@ -47,6 +51,7 @@ async function main() {
         initialSupply: hre.ethers.formatEther(totalSupply)
       },
       TaskEscrow: {
+        contract: escrowContract,
         address: escrowAddress,
         tokenAddress: tokenAddress
       }
@@ -71,7 +76,7 @@ async function main() {
   console.log("📝 Next steps:");
   console.log("1. Verify contracts on Etherscan:");
   console.log(`   npx hardhat verify --network sepolia ${tokenAddress}`);
-  console.log(`   npx hardhat verify --network sepolia ${escrowAddress} ${tokenAddress}`);
+  console.log(`   npx hardhat verify --network sepolia ${escrowAddress} ${tokenAddress} ${withdrawPeriodLimit}`);
   console.log("\n2. Update .env file with contract addresses");
   console.log("\n3. Generate Go bindings:");
   console.log("   cd ../lambda");
@@ -0,0 +1,21 @@
+-- Add auth_nonces table for Sign-In with Ethereum (EIP-4361)
+-- Date: 2026-10-19
+
+CREATE TABLE IF NOT EXISTS auth_nonces (
+    nonce VARCHAR(64) PRIMARY KEY,
+
+    -- Lower-case address the nonce was issued to
+    eth_address VARCHAR(42) NOT NULL,
+
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_nonces_expires_at ON auth_nonces(expires_at);
+
+-- Optional cleanup (run periodically)
+-- DELETE FROM auth_nonces WHERE expires_at < NOW();
+
+SELECT 'Migration completed successfully. auth_nonces table created.' AS status;
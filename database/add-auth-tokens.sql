@@ -0,0 +1,40 @@
+-- Add refresh_tokens and revoked_tokens tables for JWT sessions
+-- Date: 2026-10-19
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    token_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+
+    -- SHA-256 of the opaque token; the token itself is never stored
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+
+    -- Rotation chain: reusing a spent token revokes the whole family
+    family_id UUID NOT NULL,
+    user_did VARCHAR(66) NOT NULL REFERENCES users(did),
+    username VARCHAR(255) NOT NULL,
+
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON refresh_tokens(user_did);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti VARCHAR(64) PRIMARY KEY,
+    user_did VARCHAR(66) NOT NULL,
+
+    -- Row can be purged after the token's own expiry
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+-- Optional cleanup (run periodically)
+-- DELETE FROM revoked_tokens WHERE expires_at < NOW();
+-- DELETE FROM refresh_tokens WHERE expires_at < NOW();
+
+SELECT 'Migration completed successfully. refresh_tokens and revoked_tokens tables created.' AS status;
@@ -0,0 +1,17 @@
+-- Add proposal details (price, delivery date, milestone plan, attachments) to bids
+-- Date: 2026-10-19
+
+-- Step 1: Add columns
+ALTER TABLE task_bids ADD COLUMN IF NOT EXISTS proposed_reward DECIMAL(20, 8);
+ALTER TABLE task_bids ADD COLUMN IF NOT EXISTS estimated_delivery_date DATE;
+ALTER TABLE task_bids ADD COLUMN IF NOT EXISTS proposed_milestone_bps INT[];
+ALTER TABLE task_bids ADD COLUMN IF NOT EXISTS attachment_urls TEXT[];
+
+-- Step 2: A counter-offer must be positive
+ALTER TABLE task_bids DROP CONSTRAINT IF EXISTS task_bids_proposed_reward_check;
+ALTER TABLE task_bids ADD CONSTRAINT task_bids_proposed_reward_check CHECK (proposed_reward > 0);
+
+COMMENT ON COLUMN task_bids.proposed_reward IS 'Counter-offer in XZT; NULL means the task reward';
+COMMENT ON COLUMN task_bids.proposed_milestone_bps IS 'Proposed design/implementation/final shares in basis points (informational)';
+
+SELECT 'Migration completed successfully. Bids now carry proposal details.' AS status;
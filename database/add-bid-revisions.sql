@@ -0,0 +1,48 @@
+-- Add bid withdrawal, bid revision history and per-bidder open bid lookups
+-- Date: 2026-10-19
+
+-- Step 1: Allow withdrawn bids
+ALTER TABLE task_bids DROP CONSTRAINT IF EXISTS task_bids_status_check;
+ALTER TABLE task_bids ADD CONSTRAINT task_bids_status_check CHECK (status IN (
+    'pending',
+    'accepted',
+    'rejected',
+    'withdrawn'
+));
+
+-- Step 2: Every version of a bid, including withdrawals
+CREATE TABLE IF NOT EXISTS task_bid_revisions (
+    revision_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bid_id UUID NOT NULL REFERENCES task_bids(bid_id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN (
+        'created',
+        'updated',
+        'withdrawn',
+        'resubmitted'
+    )),
+
+    -- Bid as it stood after this action
+    bid_message TEXT,
+    proposed_reward DECIMAL(20, 8),
+    estimated_delivery_date DATE,
+    proposed_milestone_bps INT[],
+    attachment_urls TEXT[],
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(bid_id, revision)
+);
+
+-- Step 3: Existing bids start their history at revision 1
+INSERT INTO task_bid_revisions (bid_id, revision, action, bid_message, proposed_reward,
+    estimated_delivery_date, proposed_milestone_bps, attachment_urls, created_at)
+SELECT bid_id, 1, 'created', bid_message, proposed_reward,
+    estimated_delivery_date, proposed_milestone_bps, attachment_urls, created_at
+FROM task_bids
+ON CONFLICT (bid_id, revision) DO NOTHING;
+
+-- Step 4: Open bid counts per bidder
+CREATE INDEX IF NOT EXISTS idx_bids_bidder_status ON task_bids(bidder_did, status);
+
+SELECT 'Migration completed successfully. Bids can now be withdrawn and keep a revision history.' AS status;
@@ -0,0 +1,12 @@
+-- Store the executor team a bidder proposes with their bid
+-- Date: 2026-10-19
+
+-- Step 1: Team the bidder leads, lead first: [{"did": "...", "share_bps": 6000}, ...]
+ALTER TABLE task_bids ADD COLUMN IF NOT EXISTS proposed_team JSONB;
+
+-- Step 2: Keep the team in every bid revision
+ALTER TABLE task_bid_revisions ADD COLUMN IF NOT EXISTS proposed_team JSONB;
+
+COMMENT ON COLUMN task_bids.proposed_team IS 'Executor team proposed by the bidder (the lead); select-bidder sets exactly this team on the escrow';
+
+SELECT 'Migration completed successfully. Bids can now carry the executor team the bidder leads.' AS status;
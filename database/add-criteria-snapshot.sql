@@ -0,0 +1,10 @@
+-- Snapshot a task's acceptance criteria when its executor is assigned
+-- Date: 2026-10-19
+
+-- Step 1: Criteria as split at assignment; reviews use these over the live text
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS accepted_criteria TEXT[];
+
+-- Tasks assigned before this migration keep accepted_criteria NULL and are
+-- reviewed against their current acceptance_criteria
+
+SELECT 'Migration completed successfully. Assigned tasks now keep the criteria they were accepted with.' AS status;
@@ -0,0 +1,38 @@
+-- Add idempotency_keys table for retried POST requests
+-- Date: 2026-10-19
+
+-- Step 1: Create table
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_did VARCHAR(66) NOT NULL REFERENCES users(did),
+    route VARCHAR(255) NOT NULL,
+    idem_key VARCHAR(255) NOT NULL,
+
+    -- SHA-256 of the request body, to reject a key reused for a different request
+    request_hash VARCHAR(64) NOT NULL,
+
+    -- Stored response
+    status VARCHAR(20) NOT NULL DEFAULT 'in_progress' CHECK (status IN (
+        'in_progress',
+        'completed'
+    )),
+    status_code INT,
+    response_body TEXT,
+
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (user_did, route, idem_key)
+);
+
+-- Step 2: Index for purging expired keys
+CREATE INDEX IF NOT EXISTS idx_idempotency_expires_at ON idempotency_keys(expires_at);
+
+-- Step 3: Add comment
+COMMENT ON TABLE idempotency_keys IS 'Idempotency-Key 请求记录，重试时返回已保存的响应';
+
+-- Optional cleanup (run periodically)
+-- DELETE FROM idempotency_keys WHERE expires_at < NOW();
+
+SELECT 'Migration completed successfully. idempotency_keys table created.' AS status;
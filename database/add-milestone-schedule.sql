@@ -0,0 +1,18 @@
+-- Add milestone_bps (per-task milestone schedule) to tasks table
+-- Date: 2026-10-19
+
+-- Shares in basis points for design, implementation and final, summing to 10000.
+-- On a v2 escrow the same schedule is stored on chain at creation.
+-- NULL means the default 3000/5000/2000 (tasks created before this migration).
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS milestone_bps INT[];
+
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_milestone_bps_check;
+ALTER TABLE tasks ADD CONSTRAINT tasks_milestone_bps_check CHECK (
+    milestone_bps IS NULL OR (
+        array_length(milestone_bps, 1) = 3
+        AND 0 < ALL (milestone_bps)
+        AND milestone_bps[1] + milestone_bps[2] + milestone_bps[3] = 10000
+    )
+);
+
+SELECT 'Migration completed successfully. Tasks table now has milestone_bps column.' AS status;
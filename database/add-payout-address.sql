@@ -0,0 +1,36 @@
+-- Add verified executor payout addresses with an audit log of changes
+-- Date: 2026-10-19
+
+-- Step 1: External wallet a user has proved control of for task payouts
+ALTER TABLE users ADD COLUMN IF NOT EXISTS payout_address VARCHAR(42);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS payout_address_verified_at TIMESTAMP;
+
+-- Step 2: Address the escrow pays for each task, fixed when the executor is set
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS executor_address VARCHAR(42);
+
+-- Step 3: Every payout address change, with the proof that authorized it
+CREATE TABLE IF NOT EXISTS payout_address_changes (
+    change_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_did VARCHAR(66) NOT NULL REFERENCES users(did),
+
+    -- NULL old_address: first set; NULL new_address: reverted to eth_address
+    old_address VARCHAR(42),
+    new_address VARCHAR(42),
+
+    -- Signed SIWE message proving control of new_address
+    message TEXT,
+    signature VARCHAR(132),
+
+    -- Request origin
+    source_ip VARCHAR(45),
+    user_agent TEXT,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_payout_changes_user ON payout_address_changes(user_did, created_at DESC);
+
+COMMENT ON COLUMN users.payout_address IS 'Verified external wallet for task payouts; NULL means eth_address';
+COMMENT ON COLUMN tasks.executor_address IS 'Executor address set on the escrow; receives milestone payments';
+
+SELECT 'Migration completed successfully. Users can now set a verified payout address.' AS status;
@@ -0,0 +1,23 @@
+-- Add project membership for project-visibility tasks
+-- Date: 2026-10-19
+
+-- Step 1: Users who may see and bid on a project's 'project' tasks
+CREATE TABLE IF NOT EXISTS project_members (
+    project_id UUID NOT NULL REFERENCES projects(project_id) ON DELETE CASCADE,
+    user_did VARCHAR(66) NOT NULL REFERENCES users(did) ON DELETE CASCADE,
+    joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (project_id, user_did)
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_members_user ON project_members(user_did);
+
+-- Step 2: Creators of existing project tasks are members of those projects
+INSERT INTO project_members (project_id, user_did)
+SELECT DISTINCT project_id, creator_did FROM tasks
+ON CONFLICT DO NOTHING;
+
+-- Step 3: Open-task lookups for the recommendation feed
+CREATE INDEX IF NOT EXISTS idx_tasks_open_created ON tasks(created_at DESC) WHERE status IN ('pending', 'bidding');
+
+SELECT 'Migration completed successfully. Project membership table created.' AS status;
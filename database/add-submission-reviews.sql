@@ -0,0 +1,35 @@
+-- Add submission revisions and per-criterion review feedback
+-- Date: 2026-10-19
+
+-- Step 1: Number attempts per milestone
+ALTER TABLE task_submissions ADD COLUMN IF NOT EXISTS revision INT NOT NULL DEFAULT 1 CHECK (revision > 0);
+
+UPDATE task_submissions s SET revision = numbered.revision
+FROM (
+    SELECT submission_id,
+           ROW_NUMBER() OVER (PARTITION BY task_id, submission_type ORDER BY submitted_at, submission_id) AS revision
+    FROM task_submissions
+) numbered
+WHERE s.submission_id = numbered.submission_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_submissions_revision ON task_submissions(task_id, submission_type, revision);
+
+-- Step 2: Pass/fail and comment per acceptance criterion
+CREATE TABLE IF NOT EXISTS submission_criteria (
+    submission_id UUID NOT NULL REFERENCES task_submissions(submission_id) ON DELETE CASCADE,
+    criterion_index INT NOT NULL CHECK (criterion_index >= 0),
+
+    -- Criterion text at review time; task criteria may be edited later
+    criterion TEXT NOT NULL,
+    result VARCHAR(10) NOT NULL CHECK (result IN ('pass', 'fail')),
+    comment TEXT,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (submission_id, criterion_index)
+);
+
+COMMENT ON COLUMN task_submissions.revision IS 'Attempt number within the milestone, starting at 1';
+COMMENT ON COLUMN submission_criteria.criterion_index IS 'Line of tasks.acceptance_criteria, counting non-empty lines from 0';
+
+SELECT 'Migration completed successfully. Submissions now have revisions and per-criterion reviews.' AS status;
@@ -0,0 +1,25 @@
+-- Add chain_id and escrow_address to tasks for the multi-chain registry
+-- Date: 2026-10-19
+
+-- Step 1: Add columns
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS chain_id BIGINT;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS escrow_address VARCHAR(42);
+
+-- Step 2: Existing tasks were all created on the Sepolia escrow
+-- (replace the address if your deployment used a different one)
+UPDATE tasks
+SET chain_id = 11155111,
+    escrow_address = '0x8e98B971884e14C5da6D528932bf96296311B8cb'
+WHERE contract_task_id IS NOT NULL AND chain_id IS NULL;
+
+-- Step 3: Contract task ids are only unique within one escrow contract
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_contract_task_id_key;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_escrow_task_key;
+ALTER TABLE tasks ADD CONSTRAINT tasks_escrow_task_key UNIQUE (chain_id, escrow_address, contract_task_id);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_chain_escrow ON tasks(chain_id, escrow_address);
+
+COMMENT ON COLUMN tasks.chain_id IS 'Chain the task escrow lives on';
+COMMENT ON COLUMN tasks.escrow_address IS 'Escrow contract holding the task funds; settlement always uses this contract';
+
+SELECT 'Migration completed successfully. Tasks table now has chain_id and escrow_address columns.' AS status;
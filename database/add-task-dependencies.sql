@@ -0,0 +1,40 @@
+-- Add subtasks and task dependencies
+-- Date: 2026-10-19
+
+-- Step 1: Parent task for subtasks
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_task_id UUID REFERENCES tasks(task_id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_parent ON tasks(parent_task_id);
+
+-- Step 2: Blocked status for tasks waiting on prerequisites
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_status_check;
+ALTER TABLE tasks ADD CONSTRAINT tasks_status_check CHECK (status IN (
+    'pending',
+    'blocked',
+    'bidding',
+    'accepted',
+    'design_submitted',
+    'design_approved',
+    'implementation_submitted',
+    'implementation_approved',
+    'final_submitted',
+    'completed',
+    'cancelled'
+));
+
+-- Step 3: Blocks / blocked-by relations
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    task_id UUID NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+    depends_on_task_id UUID NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (task_id, depends_on_task_id),
+    CHECK (task_id <> depends_on_task_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_depends_on ON task_dependencies(depends_on_task_id);
+
+COMMENT ON TABLE task_dependencies IS 'task_id cannot open for bidding until depends_on_task_id is completed';
+COMMENT ON COLUMN tasks.parent_task_id IS 'Parent task; get-task rolls up progress and escrow totals of subtasks';
+
+SELECT 'Migration completed successfully. Tasks can now have subtasks and dependencies.' AS status;
@@ -0,0 +1,51 @@
+-- Add task edit history, reward re-snapshots on bids and notifications
+-- Date: 2026-10-19
+
+-- Step 1: Every change to a task after creation
+CREATE TABLE IF NOT EXISTS task_edits (
+    edit_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+    editor_did VARCHAR(66) NOT NULL REFERENCES users(did),
+    changes JSONB NOT NULL,
+
+    -- Escrow transaction for reward changes
+    tx_hash VARCHAR(66),
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_edits_task ON task_edits(task_id, created_at);
+
+-- Step 2: Task reward each bid was placed against
+ALTER TABLE task_bids ADD COLUMN IF NOT EXISTS reward_snapshot DECIMAL(20, 8);
+ALTER TABLE task_bid_revisions ADD COLUMN IF NOT EXISTS reward_snapshot DECIMAL(20, 8);
+
+UPDATE task_bids b SET reward_snapshot = t.reward_amount
+FROM tasks t
+WHERE b.task_id = t.task_id AND b.reward_snapshot IS NULL;
+
+-- Step 3: Record reward changes as bid revisions
+ALTER TABLE task_bid_revisions DROP CONSTRAINT IF EXISTS task_bid_revisions_action_check;
+ALTER TABLE task_bid_revisions ADD CONSTRAINT task_bid_revisions_action_check CHECK (action IN (
+    'created',
+    'updated',
+    'withdrawn',
+    'resubmitted',
+    'reward_changed'
+));
+
+-- Step 4: Notifications for users about tasks they take part in
+CREATE TABLE IF NOT EXISTS notifications (
+    notification_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_did VARCHAR(66) NOT NULL REFERENCES users(did) ON DELETE CASCADE,
+    task_id UUID REFERENCES tasks(task_id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_did, created_at DESC);
+
+COMMENT ON COLUMN task_edits.changes IS 'Changed fields as {"field": {"old": ..., "new": ...}}';
+
+SELECT 'Migration completed successfully. Tasks can now be edited and rewards changed while bidding.' AS status;
@@ -0,0 +1,36 @@
+-- Add invite-only and direct-assignment tasks with invitations
+-- Date: 2026-10-19
+
+-- Step 1: Allow invite visibility
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_visibility_check;
+ALTER TABLE tasks ADD CONSTRAINT tasks_visibility_check CHECK (visibility IN ('project', 'global', 'invite'));
+
+-- Step 2: How the executor is chosen
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS assignment_mode VARCHAR(20) NOT NULL DEFAULT 'bidding';
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_assignment_mode_check;
+ALTER TABLE tasks ADD CONSTRAINT tasks_assignment_mode_check CHECK (assignment_mode IN ('bidding', 'direct'));
+
+-- Step 3: Users named by the creator of an invite-only task
+CREATE TABLE IF NOT EXISTS task_invitations (
+    invitation_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+    invitee_did VARCHAR(66) NOT NULL REFERENCES users(did),
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN (
+        'pending',
+        'accepted',
+        'declined'
+    )),
+
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    responded_at TIMESTAMP,
+
+    UNIQUE(task_id, invitee_did)
+);
+
+CREATE INDEX IF NOT EXISTS idx_invitations_invitee ON task_invitations(invitee_did, status);
+
+COMMENT ON COLUMN tasks.assignment_mode IS 'bidding: executor chosen from bids; direct: the single invitee is set as executor at creation';
+
+SELECT 'Migration completed successfully. Tasks can now be invite-only or directly assigned.' AS status;
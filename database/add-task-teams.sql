@@ -0,0 +1,25 @@
+-- Add executor teams that split task payouts
+-- Date: 2026-10-19
+
+-- Step 1: Members of a task's executor team, in escrow order (position 0 is the lead)
+CREATE TABLE IF NOT EXISTS task_team_members (
+    task_id UUID NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+    member_did VARCHAR(66) NOT NULL REFERENCES users(did),
+    payout_address VARCHAR(42) NOT NULL,
+    position INT NOT NULL,
+    share_bps INT NOT NULL CHECK (share_bps > 0 AND share_bps <= 10000),
+    paid_amount DECIMAL(20, 8) DEFAULT 0 CHECK (paid_amount >= 0),
+
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (task_id, member_did),
+    UNIQUE(task_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_members_member ON task_team_members(member_did);
+
+COMMENT ON COLUMN task_team_members.share_bps IS 'Share of each executor payment in basis points; shares of a task sum to 10000';
+COMMENT ON COLUMN task_team_members.paid_amount IS 'XZT paid to the member so far';
+
+SELECT 'Migration completed successfully. Tasks can now be worked by executor teams.' AS status;
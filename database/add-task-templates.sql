@@ -0,0 +1,30 @@
+-- Add per-project task templates
+-- Date: 2026-10-19
+
+-- Step 1: Reusable task details per project
+CREATE TABLE IF NOT EXISTS task_templates (
+    template_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(project_id) ON DELETE CASCADE,
+    creator_did VARCHAR(66) NOT NULL REFERENCES users(did),
+
+    -- Defaults for tasks created from the template
+    template_name VARCHAR(255) NOT NULL,
+    task_description TEXT NOT NULL DEFAULT '',
+    acceptance_criteria TEXT NOT NULL DEFAULT '',
+    profession_tags TEXT[] DEFAULT '{}',
+    -- Milestone shares in basis points (design, implementation, final); NULL = 3000/5000/2000
+    milestone_bps INT[],
+
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(project_id, template_name)
+);
+
+-- Step 2: Template a task was created from; tasks keep their copied details if it is deleted
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS template_id UUID REFERENCES task_templates(template_id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN task_templates.milestone_bps IS 'Design/implementation/final shares in basis points for tasks created from the template';
+
+SELECT 'Migration completed successfully. Projects can now keep task templates.' AS status;
@@ -0,0 +1,16 @@
+-- Add 'submitted' status for transfers sent on chain whose receipt has not arrived
+-- Date: 2026-10-19
+
+-- Step 1: Allow the new status
+ALTER TABLE xzt_transactions DROP CONSTRAINT IF EXISTS xzt_transactions_status_check;
+ALTER TABLE xzt_transactions ADD CONSTRAINT xzt_transactions_status_check CHECK (status IN (
+    'pending',
+    'submitted',
+    'confirmed',
+    'failed'
+));
+
+-- Step 2: Find submitted transfers to settle from their receipts
+CREATE INDEX IF NOT EXISTS idx_tx_submitted ON xzt_transactions(LOWER(from_address)) WHERE status = 'submitted';
+
+SELECT 'Migration completed successfully. Transfers awaiting a receipt are now recorded as submitted.' AS status;
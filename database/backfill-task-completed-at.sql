@@ -0,0 +1,23 @@
+-- Backfill completed_at for tasks completed before approve-work set it
+-- Date: 2026-10-19
+
+-- Step 1: Use the approval time of the final submission
+UPDATE tasks t
+SET completed_at = s.reviewed_at
+FROM (
+    SELECT task_id, MAX(reviewed_at) AS reviewed_at
+    FROM task_submissions
+    WHERE submission_type = 'final' AND status = 'approved'
+    GROUP BY task_id
+) s
+WHERE t.task_id = s.task_id
+  AND t.status = 'completed'
+  AND t.completed_at IS NULL
+  AND s.reviewed_at IS NOT NULL;
+
+-- Step 2: Fall back to the last update for tasks without a reviewed final submission
+UPDATE tasks
+SET completed_at = updated_at
+WHERE status = 'completed' AND completed_at IS NULL;
+
+SELECT 'Migration completed successfully. Completed tasks now have completed_at for on-time ranking.' AS status;
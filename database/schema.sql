@@ -8,7 +8,9 @@ ALTER TABLE users
     ADD COLUMN IF NOT EXISTS tasks_completed INT DEFAULT 0,
     ADD COLUMN IF NOT EXISTS tasks_cancelled INT DEFAULT 0,
     ADD COLUMN IF NOT EXISTS xzt_balance DECIMAL(20, 8) DEFAULT 0,
-    ADD COLUMN IF NOT EXISTS escrow_approved BOOLEAN DEFAULT FALSE;
+    ADD COLUMN IF NOT EXISTS escrow_approved BOOLEAN DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS payout_address VARCHAR(42),
+    ADD COLUMN IF NOT EXISTS payout_address_verified_at TIMESTAMP;
 
 -- Create index for credit score queries
 CREATE INDEX IF NOT EXISTS idx_users_credit_score ON users(credit_score);
@@ -18,29 +20,50 @@ CREATE INDEX IF NOT EXISTS idx_users_credit_score ON users(credit_score);
 -- ============================================
 CREATE TABLE IF NOT EXISTS tasks (
     task_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-    contract_task_id BIGINT UNIQUE,
+    contract_task_id BIGINT,
+    
+    -- Escrow location (contract task ids are unique per escrow)
+    chain_id BIGINT,
+    escrow_address VARCHAR(42),
     
     -- Relationships
     project_id UUID NOT NULL REFERENCES projects(project_id) ON DELETE CASCADE,
     creator_did VARCHAR(66) NOT NULL REFERENCES users(did),
     executor_did VARCHAR(66) REFERENCES users(did),
+    parent_task_id UUID REFERENCES tasks(task_id) ON DELETE SET NULL,
+    
+    -- Address set on the escrow as executor (payout address at selection)
+    executor_address VARCHAR(42),
     
     -- Task details
     task_name VARCHAR(255) NOT NULL,
     task_description TEXT NOT NULL,
     acceptance_criteria TEXT NOT NULL,
+    -- Criteria as split when the executor was assigned; reviews use these
+    accepted_criteria TEXT[],
     
     -- Financial
     reward_amount DECIMAL(20, 8) NOT NULL CHECK (reward_amount > 0),
     paid_amount DECIMAL(20, 8) DEFAULT 0 CHECK (paid_amount >= 0),
     
     -- Settings
-    visibility VARCHAR(20) NOT NULL CHECK (visibility IN ('project', 'global')),
+    visibility VARCHAR(20) NOT NULL CHECK (visibility IN ('project', 'global', 'invite')),
+    assignment_mode VARCHAR(20) NOT NULL DEFAULT 'bidding' CHECK (assignment_mode IN ('bidding', 'direct')),
     profession_tags TEXT[] DEFAULT '{}',
     
+    -- Milestone shares in basis points (design, implementation, final); NULL = 3000/5000/2000
+    milestone_bps INT[] CHECK (
+        milestone_bps IS NULL OR (
+            array_length(milestone_bps, 1) = 3
+            AND 0 < ALL (milestone_bps)
+            AND milestone_bps[1] + milestone_bps[2] + milestone_bps[3] = 10000
+        )
+    ),
+    
     -- Status tracking
     status VARCHAR(30) NOT NULL DEFAULT 'pending' CHECK (status IN (
         'pending',
+        'blocked',
         'bidding',
         'accepted',
         'design_submitted',
@@ -56,7 +79,9 @@ CREATE TABLE IF NOT EXISTS tasks (
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     completed_at TIMESTAMP,
-    cancelled_at TIMESTAMP
+    cancelled_at TIMESTAMP,
+    
+    CONSTRAINT tasks_escrow_task_key UNIQUE (chain_id, escrow_address, contract_task_id)
 );
 
 -- Indexes for tasks
@@ -67,6 +92,110 @@ CREATE INDEX IF NOT EXISTS idx_tasks_project ON tasks(project_id);
 CREATE INDEX IF NOT EXISTS idx_tasks_visibility ON tasks(visibility);
 CREATE INDEX IF NOT EXISTS idx_tasks_created_at ON tasks(created_at DESC);
 CREATE INDEX IF NOT EXISTS idx_tasks_profession_tags ON tasks USING GIN(profession_tags);
+CREATE INDEX IF NOT EXISTS idx_tasks_chain_escrow ON tasks(chain_id, escrow_address);
+CREATE INDEX IF NOT EXISTS idx_tasks_open_created ON tasks(created_at DESC) WHERE status IN ('pending', 'bidding');
+CREATE INDEX IF NOT EXISTS idx_tasks_parent ON tasks(parent_task_id);
+
+-- ============================================
+-- Task Dependencies Table
+-- ============================================
+-- A task cannot open for bidding until every task it depends on is completed
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    task_id UUID NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+    depends_on_task_id UUID NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    PRIMARY KEY (task_id, depends_on_task_id),
+    CHECK (task_id <> depends_on_task_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_depends_on ON task_dependencies(depends_on_task_id);
+
+-- ============================================
+-- Project Members Table
+-- ============================================
+-- Users who may see and bid on a project's 'project' tasks
+CREATE TABLE IF NOT EXISTS project_members (
+    project_id UUID NOT NULL REFERENCES projects(project_id) ON DELETE CASCADE,
+    user_did VARCHAR(66) NOT NULL REFERENCES users(did) ON DELETE CASCADE,
+    joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    PRIMARY KEY (project_id, user_did)
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_members_user ON project_members(user_did);
+
+-- ============================================
+-- Task Templates Table
+-- ============================================
+-- Reusable task details per project, filled in by POST /tasks/batch
+CREATE TABLE IF NOT EXISTS task_templates (
+    template_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(project_id) ON DELETE CASCADE,
+    creator_did VARCHAR(66) NOT NULL REFERENCES users(did),
+    
+    -- Defaults for tasks created from the template
+    template_name VARCHAR(255) NOT NULL,
+    task_description TEXT NOT NULL DEFAULT '',
+    acceptance_criteria TEXT NOT NULL DEFAULT '',
+    profession_tags TEXT[] DEFAULT '{}',
+    -- Milestone shares in basis points (design, implementation, final); NULL = 3000/5000/2000
+    milestone_bps INT[],
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    UNIQUE(project_id, template_name)
+);
+
+-- Template a task was created from; tasks keep their copied details if it is deleted
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS template_id UUID REFERENCES task_templates(template_id) ON DELETE SET NULL;
+
+-- ============================================
+-- Task Invitations Table
+-- ============================================
+-- Users named by the creator of an invite-only task
+CREATE TABLE IF NOT EXISTS task_invitations (
+    invitation_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+    invitee_did VARCHAR(66) NOT NULL REFERENCES users(did),
+    
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN (
+        'pending',
+        'accepted',
+        'declined'
+    )),
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    responded_at TIMESTAMP,
+    
+    UNIQUE(task_id, invitee_did)
+);
+
+CREATE INDEX IF NOT EXISTS idx_invitations_invitee ON task_invitations(invitee_did, status);
+
+-- ============================================
+-- Task Team Members Table
+-- ============================================
+-- Executors splitting a task's payouts; position 0 is the lead (tasks.executor_did)
+CREATE TABLE IF NOT EXISTS task_team_members (
+    task_id UUID NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+    member_did VARCHAR(66) NOT NULL REFERENCES users(did),
+    payout_address VARCHAR(42) NOT NULL,
+    position INT NOT NULL,
+    share_bps INT NOT NULL CHECK (share_bps > 0 AND share_bps <= 10000),
+    paid_amount DECIMAL(20, 8) DEFAULT 0 CHECK (paid_amount >= 0),
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    PRIMARY KEY (task_id, member_did),
+    UNIQUE(task_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_members_member ON task_team_members(member_did);
 
 -- ============================================
 -- Task Bids Table
@@ -80,11 +209,22 @@ CREATE TABLE IF NOT EXISTS task_bids (
     bid_message TEXT,
     credit_score_snapshot INT NOT NULL,
     
+    -- Proposal (all optional)
+    proposed_reward DECIMAL(20, 8) CHECK (proposed_reward > 0),
+    estimated_delivery_date DATE,
+    proposed_milestone_bps INT[],
+    attachment_urls TEXT[],
+    proposed_team JSONB, -- Executor team the bidder leads, lead first: [{"did", "share_bps"}]
+    
+    -- Task reward the bid was placed against, refreshed when the reward changes
+    reward_snapshot DECIMAL(20, 8),
+    
     -- Status
     status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN (
         'pending',
         'accepted',
-        'rejected'
+        'rejected',
+        'withdrawn'
     )),
     
     -- Timestamps
@@ -99,6 +239,67 @@ CREATE TABLE IF NOT EXISTS task_bids (
 CREATE INDEX IF NOT EXISTS idx_bids_task ON task_bids(task_id);
 CREATE INDEX IF NOT EXISTS idx_bids_bidder ON task_bids(bidder_did);
 CREATE INDEX IF NOT EXISTS idx_bids_status ON task_bids(status);
+CREATE INDEX IF NOT EXISTS idx_bids_bidder_status ON task_bids(bidder_did, status);
+
+-- Every version of a bid, including withdrawals
+CREATE TABLE IF NOT EXISTS task_bid_revisions (
+    revision_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bid_id UUID NOT NULL REFERENCES task_bids(bid_id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN (
+        'created',
+        'updated',
+        'withdrawn',
+        'resubmitted',
+        'reward_changed'
+    )),
+    
+    -- Bid as it stood after this action
+    bid_message TEXT,
+    proposed_reward DECIMAL(20, 8),
+    estimated_delivery_date DATE,
+    proposed_milestone_bps INT[],
+    attachment_urls TEXT[],
+    reward_snapshot DECIMAL(20, 8),
+    proposed_team JSONB,
+    
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    UNIQUE(bid_id, revision)
+);
+
+-- ============================================
+-- Task Edits Table
+-- ============================================
+-- Every change to a task after creation: {"field": {"old": ..., "new": ...}}
+CREATE TABLE IF NOT EXISTS task_edits (
+    edit_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+    editor_did VARCHAR(66) NOT NULL REFERENCES users(did),
+    changes JSONB NOT NULL,
+    
+    -- Escrow transaction for reward changes
+    tx_hash VARCHAR(66),
+    
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_edits_task ON task_edits(task_id, created_at);
+
+-- ============================================
+-- Notifications Table
+-- ============================================
+-- Messages for users about tasks they take part in, e.g. a reward change on a task they bid on
+CREATE TABLE IF NOT EXISTS notifications (
+    notification_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_did VARCHAR(66) NOT NULL REFERENCES users(did) ON DELETE CASCADE,
+    task_id UUID REFERENCES tasks(task_id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_did, created_at DESC);
 
 -- ============================================
 -- Task Submissions Table
@@ -113,6 +314,8 @@ CREATE TABLE IF NOT EXISTS task_submissions (
         'implementation',
         'final'
     )),
+    -- Attempt number within the milestone, starting at 1
+    revision INT NOT NULL DEFAULT 1 CHECK (revision > 0),
     content TEXT NOT NULL,
     file_urls TEXT[],
     
@@ -133,6 +336,25 @@ CREATE TABLE IF NOT EXISTS task_submissions (
 CREATE INDEX IF NOT EXISTS idx_submissions_task ON task_submissions(task_id);
 CREATE INDEX IF NOT EXISTS idx_submissions_type ON task_submissions(submission_type);
 CREATE INDEX IF NOT EXISTS idx_submissions_status ON task_submissions(status);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_submissions_revision ON task_submissions(task_id, submission_type, revision);
+
+-- ============================================
+-- Submission Criteria Table
+-- ============================================
+-- Pass/fail and comment per acceptance criterion when a submission is reviewed
+CREATE TABLE IF NOT EXISTS submission_criteria (
+    submission_id UUID NOT NULL REFERENCES task_submissions(submission_id) ON DELETE CASCADE,
+    criterion_index INT NOT NULL CHECK (criterion_index >= 0),
+    
+    -- Criterion text at review time; task criteria may be edited later
+    criterion TEXT NOT NULL,
+    result VARCHAR(10) NOT NULL CHECK (result IN ('pass', 'fail')),
+    comment TEXT,
+    
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    PRIMARY KEY (submission_id, criterion_index)
+);
 
 -- ============================================
 -- Credit History Table
@@ -190,8 +412,10 @@ CREATE TABLE IF NOT EXISTS xzt_transactions (
     task_id UUID REFERENCES tasks(task_id),
     
     -- Status
+    -- submitted: sent on chain, receipt not seen yet
     status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN (
         'pending',
+        'submitted',
         'confirmed',
         'failed'
     )),
@@ -208,6 +432,111 @@ CREATE INDEX IF NOT EXISTS idx_tx_to ON xzt_transactions(to_address);
 CREATE INDEX IF NOT EXISTS idx_tx_task ON xzt_transactions(task_id);
 CREATE INDEX IF NOT EXISTS idx_tx_type ON xzt_transactions(tx_type);
 CREATE INDEX IF NOT EXISTS idx_tx_created_at ON xzt_transactions(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_tx_submitted ON xzt_transactions(LOWER(from_address)) WHERE status = 'submitted';
+
+-- ============================================
+-- Idempotency Keys Table
+-- ============================================
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_did VARCHAR(66) NOT NULL REFERENCES users(did),
+    route VARCHAR(255) NOT NULL,
+    idem_key VARCHAR(255) NOT NULL,
+    
+    -- SHA-256 of the request body
+    request_hash VARCHAR(64) NOT NULL,
+    
+    -- Stored response
+    status VARCHAR(20) NOT NULL DEFAULT 'in_progress' CHECK (status IN (
+        'in_progress',
+        'completed'
+    )),
+    status_code INT,
+    response_body TEXT,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    
+    PRIMARY KEY (user_did, route, idem_key)
+);
+
+-- Index for purging expired keys
+CREATE INDEX IF NOT EXISTS idx_idempotency_expires_at ON idempotency_keys(expires_at);
+
+-- ============================================
+-- Auth Token Tables
+-- ============================================
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    token_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    
+    -- SHA-256 of the opaque token; the token itself is never stored
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    
+    -- Rotation chain: reusing a spent token revokes the whole family
+    family_id UUID NOT NULL,
+    user_did VARCHAR(66) NOT NULL REFERENCES users(did),
+    username VARCHAR(255) NOT NULL,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON refresh_tokens(user_did);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti VARCHAR(64) PRIMARY KEY,
+    user_did VARCHAR(66) NOT NULL,
+    
+    -- Row can be purged after the token's own expiry
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+-- Sign-In with Ethereum nonces (single-use)
+CREATE TABLE IF NOT EXISTS auth_nonces (
+    nonce VARCHAR(64) PRIMARY KEY,
+    
+    -- Lower-case address the nonce was issued to
+    eth_address VARCHAR(42) NOT NULL,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_nonces_expires_at ON auth_nonces(expires_at);
+
+-- ============================================
+-- Payout Address Audit Log
+-- ============================================
+CREATE TABLE IF NOT EXISTS payout_address_changes (
+    change_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_did VARCHAR(66) NOT NULL REFERENCES users(did),
+    
+    -- NULL old_address: first set; NULL new_address: reverted to eth_address
+    old_address VARCHAR(42),
+    new_address VARCHAR(42),
+    
+    -- Signed SIWE message proving control of new_address
+    message TEXT,
+    signature VARCHAR(132),
+    
+    -- Request origin
+    source_ip VARCHAR(45),
+    user_agent TEXT,
+    
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_payout_changes_user ON payout_address_changes(user_did, created_at DESC);
 
 -- ============================================
 -- Update Triggers
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/jackc/pgx/v5"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/idempotency"
+	"github.com/x-zero/xz-wallet/pkg/models"
+	"github.com/x-zero/xz-wallet/pkg/response"
+	"github.com/x-zero/xz-wallet/pkg/taskgraph"
+)
+
+type AddDependencyRequest struct {
+	DependsOnTaskID string `json:"depends_on_task_id"` // Task that must complete first
+}
+
+type DependencyResponse struct {
+	TaskID          string `json:"task_id"`
+	DependsOnTaskID string `json:"depends_on_task_id"`
+	Status          string `json:"status"` // Task status after the change
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	taskID := request.PathParameters["id"]
+	if taskID == "" {
+		return response.Error(400, "Missing task ID")
+	}
+
+	var req AddDependencyRequest
+	if err := json.Unmarshal([]byte(request.Body), &req); err != nil {
+		return response.Error(400, "Invalid request body")
+	}
+	if req.DependsOnTaskID == "" {
+		return response.Error(400, "depends_on_task_id is required")
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return response.RetryableError(500, "Failed to start transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	var projectID, creatorDID, status string
+	err = tx.QueryRow(ctx, `
+		SELECT project_id, creator_did, status FROM tasks WHERE task_id = $1
+	`, taskID).Scan(&projectID, &creatorDID, &status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return response.Error(404, "Task not found")
+	}
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Failed to load task: %v", err))
+	}
+
+	if creatorDID != claims.DID {
+		return response.Error(403, "Only creator can change dependencies")
+	}
+
+	// Lock the project's graph, then re-read the task under the lock
+	if err := taskgraph.LockProject(ctx, tx, projectID); err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Failed to lock project: %v", err))
+	}
+	err = tx.QueryRow(ctx, "SELECT status FROM tasks WHERE task_id = $1 FOR UPDATE", taskID).Scan(&status)
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Failed to load task: %v", err))
+	}
+	if !taskgraph.Editable(status) {
+		return response.Error(400, "Dependencies can only change before an executor is assigned")
+	}
+
+	var prerequisiteProjectID, prerequisiteStatus string
+	err = tx.QueryRow(ctx, `
+		SELECT project_id, status FROM tasks WHERE task_id = $1
+	`, req.DependsOnTaskID).Scan(&prerequisiteProjectID, &prerequisiteStatus)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return response.Error(404, "Dependency task not found")
+	}
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Failed to load dependency task: %v", err))
+	}
+	if prerequisiteProjectID != projectID || prerequisiteStatus == models.TaskStatusCancelled {
+		return response.Error(400, "Dependency must be an open task in the same project")
+	}
+
+	cycle, err := taskgraph.DependencyCycle(ctx, tx, taskID, req.DependsOnTaskID)
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Failed to check dependencies: %v", err))
+	}
+	if cycle {
+		return response.Error(400, "Dependency would create a cycle")
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO task_dependencies (task_id, depends_on_task_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, taskID, req.DependsOnTaskID)
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Failed to save dependency: %v", err))
+	}
+	if tag.RowsAffected() == 0 {
+		return response.Error(409, "Dependency already exists")
+	}
+
+	// An open task closes to bids until the new prerequisite completes;
+	// pending bids stay and can be selected once it reopens
+	open := status == models.TaskStatusBidding || status == models.TaskStatusPending
+	if open && prerequisiteStatus != models.TaskStatusCompleted {
+		_, err = tx.Exec(ctx, `
+			UPDATE tasks SET status = 'blocked', updated_at = CURRENT_TIMESTAMP WHERE task_id = $1
+		`, taskID)
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Failed to update task: %v", err))
+		}
+		status = models.TaskStatusBlocked
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return response.Error(500, "Failed to commit transaction")
+	}
+
+	return response.Success(DependencyResponse{
+		TaskID:          taskID,
+		DependsOnTaskID: req.DependsOnTaskID,
+		Status:          status,
+	})
+}
+
+func main() {
+	lambda.Start(idempotency.Wrap(handler))
+}
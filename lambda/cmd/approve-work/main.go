@@ -8,22 +8,30 @@ import (
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/jackc/pgx/v5"
 	"github.com/x-zero/xz-wallet/pkg/auth"
 	"github.com/x-zero/xz-wallet/pkg/blockchain"
 	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/idempotency"
 	"github.com/x-zero/xz-wallet/pkg/models"
 	"github.com/x-zero/xz-wallet/pkg/response"
+	"github.com/x-zero/xz-wallet/pkg/taskgraph"
 )
 
 type ApproveWorkRequest struct {
 	Milestone string `json:"milestone"` // "design", "implementation", "final"
 	Approve   bool   `json:"approve"`   // true for approve, false for reject
 	RejectionReason string `json:"rejection_reason,omitempty"` // optional reason for rejection
+	Signature string `json:"signature,omitempty"` // creator's EIP-712 PayMilestone signature (v2 escrow)
+	Deadline  int64  `json:"deadline,omitempty"`  // deadline the signature was made with
+	Criteria  []models.CriterionReview `json:"criteria,omitempty"` // result per acceptance criterion; all pass if omitted on approval
 }
 
 type ApproveWorkResponse struct {
-	Status  string        `json:"status"`
-	Payment PaymentDetail `json:"payment"`
+	Status      string                   `json:"status"`
+	Payment     PaymentDetail            `json:"payment"`
+	Review      []models.CriterionReview `json:"review"`
+	OpenedTasks []string                 `json:"opened_tasks,omitempty"` // Dependent tasks whose bidding opened
 }
 
 type PaymentDetail struct {
@@ -37,8 +45,8 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 			StatusCode: 200,
 			Headers: map[string]string{
 				"Access-Control-Allow-Origin":  "*",
-				"Access-Control-Allow-Headers": "Content-Type,Authorization",
-				"Access-Control-Allow-Methods": "GET,POST,PUT,DELETE,OPTIONS",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
 			},
 		}, nil
 	}
@@ -65,31 +73,40 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 
 	// Initialize
 	if err := db.InitDB(); err != nil {
-		return response.Error(500, fmt.Sprintf("Database error: %v", err))
-	}
-	client, err := blockchain.InitClient()
-	if err != nil {
-		return response.Error(500, fmt.Sprintf("Blockchain error: %v", err))
+		return response.RetryableError(500, fmt.Sprintf("Database error: %v", err))
 	}
 
 	pool := db.GetPool()
 
 	// Get task
 	var task struct {
-		ContractTaskID int64
-		CreatorDID     string
-		Status         string
-		RewardAmount   string
-		PaidAmount     string
+		ContractTaskID     int64
+		CreatorDID         string
+		Status             string
+		RewardAmount       string
+		PaidAmount         string
+		ChainID            *int64
+		EscrowAddress      *string
+		MilestoneBps       []int32
+		AcceptanceCriteria string
+		AcceptedCriteria   []string
 	}
 	err = pool.QueryRow(ctx, `
-		SELECT contract_task_id, creator_did, status, reward_amount, paid_amount
+		SELECT contract_task_id, creator_did, status, reward_amount, paid_amount, chain_id, escrow_address, milestone_bps,
+		       acceptance_criteria, accepted_criteria
 		FROM tasks WHERE task_id = $1
-	`, taskID).Scan(&task.ContractTaskID, &task.CreatorDID, &task.Status, &task.RewardAmount, &task.PaidAmount)
+	`, taskID).Scan(&task.ContractTaskID, &task.CreatorDID, &task.Status, &task.RewardAmount, &task.PaidAmount,
+		&task.ChainID, &task.EscrowAddress, &task.MilestoneBps, &task.AcceptanceCriteria, &task.AcceptedCriteria)
 	if err != nil {
 		return response.Error(404, "Task not found")
 	}
 
+	// Settle on the escrow that holds the task
+	client, err := blockchain.ClientForTask(task.ChainID, task.EscrowAddress)
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Blockchain error: %v", err))
+	}
+
 	// Verify user is creator
 	if task.CreatorDID != claims.DID {
 		return response.Error(403, "Only creator can approve work")
@@ -97,24 +114,20 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 
 	// Determine current and target status based on milestone
 	var currentStatus, approvedStatus, rejectedStatus string
-	var paymentPercent int
 	
 	switch req.Milestone {
 	case "design":
 		currentStatus = models.TaskStatusDesignSubmitted
 		approvedStatus = models.TaskStatusDesignApproved
 		rejectedStatus = models.TaskStatusAccepted // Reject back to accepted
-		paymentPercent = models.MilestoneDesign // 30%
 	case "implementation":
 		currentStatus = models.TaskStatusImplementationSubmitted
 		approvedStatus = models.TaskStatusImplementationApproved
 		rejectedStatus = models.TaskStatusDesignApproved // Reject back to design approved
-		paymentPercent = models.MilestoneImplementation // 50%
 	case "final":
 		currentStatus = models.TaskStatusFinalSubmitted
 		approvedStatus = models.TaskStatusCompleted
 		rejectedStatus = models.TaskStatusImplementationApproved // Reject back to implementation approved
-		paymentPercent = models.MilestoneFinal // 20%
 	default:
 		return response.Error(400, "Invalid milestone")
 	}
@@ -124,53 +137,116 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		return response.Error(400, fmt.Sprintf("Invalid task status for %s approval", req.Milestone))
 	}
 
+	// The review covers the latest revision of the milestone
+	var submissionID string
+	err = pool.QueryRow(ctx, `
+		SELECT submission_id FROM task_submissions
+		WHERE task_id = $1 AND submission_type = $2 AND status = 'pending'
+		ORDER BY revision DESC LIMIT 1
+	`, taskID, req.Milestone).Scan(&submissionID)
+	if err != nil {
+		return response.Error(400, fmt.Sprintf("No pending %s submission to review", req.Milestone))
+	}
+
+	review, err := models.CompleteReview(models.ReviewCriteria(task.AcceptedCriteria, task.AcceptanceCriteria), req.Criteria, req.Approve)
+	if err != nil {
+		return response.Error(400, fmt.Sprintf("Invalid criteria: %v", err))
+	}
+
 	// Handle rejection
 	if !req.Approve {
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return response.RetryableError(500, "Failed to start transaction")
+		}
+		defer tx.Rollback(ctx)
+
 		// Update submission status to rejected
-		_, err = pool.Exec(ctx, `
+		_, err = tx.Exec(ctx, `
 			UPDATE task_submissions 
 			SET status = 'rejected',
-			    rejection_reason = $1,
+			    rejection_reason = NULLIF($1, ''),
 			    reviewed_at = NOW()
-			WHERE task_id = $2 AND submission_type = $3 AND status = 'pending'
-		`, req.RejectionReason, taskID, req.Milestone)
+			WHERE submission_id = $2
+		`, req.RejectionReason, submissionID)
 		if err != nil {
-			return response.Error(500, fmt.Sprintf("Failed to update submission: %v", err))
+			return response.RetryableError(500, fmt.Sprintf("Failed to update submission: %v", err))
+		}
+		if err := recordReview(ctx, tx, submissionID, review); err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Failed to record review: %v", err))
 		}
 
 		// Update task status back to previous state
-		_, err = pool.Exec(ctx, `
+		_, err = tx.Exec(ctx, `
 			UPDATE tasks 
 			SET status = $1, updated_at = NOW()
 			WHERE task_id = $2
 		`, rejectedStatus, taskID)
 		if err != nil {
-			return response.Error(500, fmt.Sprintf("Failed to update task: %v", err))
+			return response.RetryableError(500, fmt.Sprintf("Failed to update task: %v", err))
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return response.Error(500, "Failed to commit transaction")
 		}
 
 		return response.Success(map[string]interface{}{
-			"message": "Work rejected",
-			"status":  rejectedStatus,
+			"message":       "Work rejected",
+			"status":        rejectedStatus,
+			"submission_id": submissionID,
+			"review":        review,
 		})
 	}
 
 	// Handle approval - continue with payment
 	newStatus := approvedStatus
 
-	// Calculate payment amount
-	rewardFloat := new(big.Float)
-	rewardFloat.SetString(task.RewardAmount)
 	multiplier := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
-	rewardFloat.Mul(rewardFloat, multiplier)
-	rewardWei, _ := rewardFloat.Int(nil)
+	milestoneIndex := models.MilestoneIndex(req.Milestone)
 
-	paymentWei := new(big.Int).Mul(rewardWei, big.NewInt(int64(paymentPercent)))
-	paymentWei.Div(paymentWei, big.NewInt(10000))
+	// Pay milestone on blockchain. A v2 escrow releases the slice of its
+	// on-chain schedule, only with the creator's signature, which the admin
+	// verifies and relays. On v1 the backend computes the slice.
+	var txHash string
+	var paymentWei *big.Int
+	if client.IsEscrowV2() {
+		if req.Signature == "" || req.Deadline == 0 {
+			return response.Error(400, "signature and deadline are required: sign the authorization from GET /tasks/{id}/escrow-authorization")
+		}
+		signature, err := blockchain.ParseSignature(req.Signature)
+		if err != nil {
+			return response.Error(400, err.Error())
+		}
+		authorization, err := client.NewReleaseAuthorization(ctx, uint64(task.ContractTaskID), milestoneIndex, big.NewInt(req.Deadline))
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Failed to build authorization: %v", err))
+		}
+		if err := client.VerifyEscrowSignatures(ctx, authorization, signature, nil); err != nil {
+			return response.Error(400, fmt.Sprintf("Invalid signature: %v", err))
+		}
+		txHash, err = client.ReleaseMilestoneSigned(ctx, authorization, signature)
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Failed to pay milestone: %v", err))
+		}
+		paymentWei = authorization.Amount
+	} else {
+		// Slice what the escrow holds, which top-ups and refunds may have
+		// moved away from reward_amount; the final milestone pays the rest
+		_, _, totalWei, paidWei, _, err := client.GetTask(uint64(task.ContractTaskID))
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Failed to read task from escrow: %v", err))
+		}
+		schedule := models.MilestoneSchedule(task.MilestoneBps)
+		if milestoneIndex == len(schedule)-1 {
+			paymentWei = new(big.Int).Sub(totalWei, paidWei)
+		} else {
+			paymentWei = blockchain.MilestoneSlice(totalWei, schedule, milestoneIndex)
+		}
 
-	// Pay milestone on blockchain
-	txHash, err := client.PayMilestone(uint64(task.ContractTaskID), paymentWei)
-	if err != nil {
-		return response.Error(500, fmt.Sprintf("Failed to pay milestone: %v", err))
+		txHash, err = client.PayMilestone(uint64(task.ContractTaskID), paymentWei)
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Failed to pay milestone: %v", err))
+		}
 	}
 
 	// Calculate new paid amount
@@ -181,38 +257,68 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	paidFloat.Add(paidFloat, paymentFloat)
 	newPaidAmount := paidFloat.Text('f', 8)
 
-	// Update submission status to approved
-	_, err = pool.Exec(ctx, `
+	// Record the payment in one transaction, so a failure leaves no half
+	// that a retry would have to repair
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Milestone paid on blockchain but database update failed. TX: %s. Please contact support.", txHash))
+	}
+	defer tx.Rollback(ctx)
+
+	// Update submission status to approved with its review
+	_, err = tx.Exec(ctx, `
 		UPDATE task_submissions 
 		SET status = 'approved',
 		    reviewed_at = NOW()
-		WHERE task_id = $1 AND submission_type = $2 AND status = 'pending'
-	`, taskID, req.Milestone)
+		WHERE submission_id = $1
+	`, submissionID)
 	if err != nil {
 		return response.Error(500, fmt.Sprintf("Failed to update submission: %v", err))
 	}
+	if err := recordReview(ctx, tx, submissionID, review); err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to record review: %v", err))
+	}
 
-	// Update task status and paid amount
-	_, err = pool.Exec(ctx, `
+	// Update task status and paid amount; completed_at dates the delivery for
+	// on-time ranking in list-bids
+	_, err = tx.Exec(ctx, `
 		UPDATE tasks 
-		SET status = $1, paid_amount = $2, updated_at = CURRENT_TIMESTAMP
+		SET status = $1, paid_amount = $2, updated_at = CURRENT_TIMESTAMP,
+		    completed_at = CASE WHEN $1 = 'completed' THEN CURRENT_TIMESTAMP ELSE completed_at END
 		WHERE task_id = $3
 	`, newStatus, newPaidAmount, taskID)
 	if err != nil {
 		return response.Error(500, fmt.Sprintf("Failed to update task: %v", err))
 	}
 
-	// If completed, update user stats
+	if err := recordTeamPayout(ctx, tx, taskID, paymentWei); err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to update team payouts: %v", err))
+	}
+
+	// If completed, update user stats and open bidding on tasks that were
+	// only waiting on this one
+	var openedTasks []string
 	if newStatus == models.TaskStatusCompleted {
-		_, err = pool.Exec(ctx, `
+		_, err = tx.Exec(ctx, `
 			UPDATE users 
 			SET tasks_completed = tasks_completed + 1,
 			    credit_score = credit_score + 100
 			WHERE did = (SELECT executor_did FROM tasks WHERE task_id = $1)
+			   OR did IN (SELECT member_did FROM task_team_members WHERE task_id = $1)
 		`, taskID)
 		if err != nil {
 			return response.Error(500, fmt.Sprintf("Failed to update user stats: %v", err))
 		}
+
+		openedTasks, err = taskgraph.OpenDependents(ctx, tx, taskID)
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Failed to open dependent tasks: %v", err))
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		fmt.Printf("CRITICAL: Milestone paid for task %s (tx=%s) but failed to record it: %v\n", taskID, txHash, err)
+		return response.Error(500, fmt.Sprintf("Milestone paid on blockchain but database update failed. TX: %s. Please contact support.", txHash))
 	}
 
 	return response.Success(ApproveWorkResponse{
@@ -221,9 +327,63 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 			Amount: paymentFloat.Text('f', 8),
 			TxHash: txHash,
 		},
+		Review:      review,
+		OpenedTasks: openedTasks,
 	})
 }
 
+// recordReview stores the per-criterion results of a submission review
+func recordReview(ctx context.Context, tx pgx.Tx, submissionID string, review []models.CriterionReview) error {
+	for _, result := range review {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO submission_criteria (submission_id, criterion_index, criterion, result, comment)
+			VALUES ($1, $2, $3, $4, $5)
+		`, submissionID, result.CriterionIndex, result.Criterion, result.Result, result.Comment)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordTeamPayout adds each team member's split of an executor payment to
+// their paid amount, splitting like the escrow; tasks without a team have no rows
+func recordTeamPayout(ctx context.Context, tx pgx.Tx, taskID string, amount *big.Int) error {
+	rows, err := tx.Query(ctx, `
+		SELECT member_did, share_bps FROM task_team_members WHERE task_id = $1 ORDER BY position
+	`, taskID)
+	if err != nil {
+		return err
+	}
+	var members []string
+	var shares []uint16
+	for rows.Next() {
+		var did string
+		var share int32
+		if err := rows.Scan(&did, &share); err != nil {
+			rows.Close()
+			return err
+		}
+		members = append(members, did)
+		shares = append(shares, uint16(share))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i, paid := range blockchain.TeamSplit(amount, shares) {
+		_, err = tx.Exec(ctx, `
+			UPDATE task_team_members SET paid_amount = paid_amount + $1
+			WHERE task_id = $2 AND member_did = $3
+		`, blockchain.FromWei(paid, 8), taskID, members[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func main() {
-	lambda.Start(handler)
+	lambda.Start(idempotency.Wrap(handler))
 }
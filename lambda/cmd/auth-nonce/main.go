@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/blockchain"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+type NonceRequest struct {
+	Address string `json:"address"`
+}
+
+type NonceResponse struct {
+	Nonce     string    `json:"nonce"`
+	Domain    string    `json:"domain,omitempty"`
+	ChainID   string    `json:"chain_id,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+var (
+	// siweDomain is SIWE_DOMAIN, checked at startup
+	siweDomain string
+	// siweChainID is the chain registry's default chain
+	siweChainID int64
+)
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	var req NonceRequest
+	if err := json.Unmarshal([]byte(request.Body), &req); err != nil {
+		return response.Error(400, "Invalid request body")
+	}
+
+	if !common.IsHexAddress(req.Address) {
+		return response.Error(400, "Invalid address")
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	nonce, expiresAt, err := auth.CreateNonce(ctx, pool, common.HexToAddress(req.Address))
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to create nonce: %v", err))
+	}
+
+	return response.Success(NonceResponse{
+		Nonce:     nonce,
+		Domain:    siweDomain,
+		ChainID:   strconv.FormatInt(siweChainID, 10),
+		ExpiresAt: expiresAt,
+	})
+}
+
+func main() {
+	// Refuse to start without a domain to check SIWE messages against
+	domain, err := auth.SIWEDomain()
+	if err != nil {
+		log.Fatal(err)
+	}
+	siweDomain = domain
+
+	registry, err := blockchain.LoadRegistry()
+	if err != nil {
+		log.Fatal(err)
+	}
+	siweChainID = registry.DefaultChainID
+
+	lambda.Start(handler)
+}
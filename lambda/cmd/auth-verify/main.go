@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/blockchain"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+type VerifyRequest struct {
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+}
+
+type VerifyResponse struct {
+	*auth.TokenPair
+	DID        string `json:"did"`
+	Username   string `json:"username"`
+	EthAddress string `json:"eth_address"`
+}
+
+var (
+	// siweDomain is SIWE_DOMAIN, checked at startup
+	siweDomain string
+	// siweChainID is the chain registry's default chain
+	siweChainID int64
+)
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	var req VerifyRequest
+	if err := json.Unmarshal([]byte(request.Body), &req); err != nil {
+		return response.Error(400, "Invalid request body")
+	}
+
+	if req.Message == "" || req.Signature == "" {
+		return response.Error(400, "Missing message or signature")
+	}
+
+	// Parse and check the EIP-4361 message
+	msg, err := auth.ParseSIWEMessage(req.Message)
+	if err != nil {
+		return response.Error(400, fmt.Sprintf("Invalid message: %v", err))
+	}
+
+	// A payout address proof must not double as a login
+	if auth.IsPayoutStatement(msg.Statement) {
+		return response.Error(400, "Invalid message: payout address proofs cannot be used to sign in")
+	}
+
+	if err := msg.Validate(siweDomain, siweChainID, time.Now()); err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid message: %v", err))
+	}
+
+	if err := auth.VerifySignature(req.Message, req.Signature, msg.Address); err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid signature: %v", err))
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	// Nonce is consumed only after the signature checks out
+	err = auth.ConsumeNonce(ctx, pool, msg.Nonce, msg.Address)
+	if errors.Is(err, auth.ErrInvalidNonce) {
+		return response.Error(401, "Invalid or expired nonce")
+	}
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to verify nonce: %v", err))
+	}
+
+	// Find the user who owns this address
+	var did, username string
+	err = pool.QueryRow(ctx, `
+		SELECT did, username FROM users WHERE LOWER(eth_address) = $1
+	`, strings.ToLower(msg.Address.Hex())).Scan(&did, &username)
+	if err != nil {
+		return response.Error(404, "No user is registered with this address")
+	}
+
+	pair, err := auth.IssueTokenPair(ctx, pool, did, username)
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to issue token: %v", err))
+	}
+
+	return response.Success(VerifyResponse{
+		TokenPair:  pair,
+		DID:        did,
+		Username:   username,
+		EthAddress: msg.Address.Hex(),
+	})
+}
+
+func main() {
+	// Refuse to start without a domain to check SIWE messages against
+	domain, err := auth.SIWEDomain()
+	if err != nil {
+		log.Fatal(err)
+	}
+	siweDomain = domain
+
+	registry, err := blockchain.LoadRegistry()
+	if err != nil {
+		log.Fatal(err)
+	}
+	siweChainID = registry.DefaultChainID
+
+	lambda.Start(handler)
+}
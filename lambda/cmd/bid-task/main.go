@@ -3,22 +3,39 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/jackc/pgx/v5"
 	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/bids"
+	"github.com/x-zero/xz-wallet/pkg/blockchain"
 	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/idempotency"
+	"github.com/x-zero/xz-wallet/pkg/models"
 	"github.com/x-zero/xz-wallet/pkg/response"
 )
 
+// Most attachment URLs a bid may carry
+const maxAttachments = 10
+
 type BidTaskRequest struct {
-	Message string `json:"message"`
+	Message               string                 `json:"message"`
+	ProposedReward        string                 `json:"proposed_reward,omitempty"`         // Counter-offer in XZT, at most the task reward
+	EstimatedDeliveryDate string                 `json:"estimated_delivery_date,omitempty"` // YYYY-MM-DD
+	MilestoneBps          []uint16               `json:"milestone_bps,omitempty"`           // Proposed design/implementation/final shares
+	AttachmentURLs        []string               `json:"attachment_urls,omitempty"`
+	Team                  []models.BidTeamMember `json:"team,omitempty"` // Executor team you lead, with each member's share (v2 escrow)
 }
 
 type BidTaskResponse struct {
 	BidID  string `json:"bid_id"`
 	Status string `json:"status"`
+	Action string `json:"action"` // created, updated or resubmitted
 }
 
 func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
@@ -27,8 +44,8 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 			StatusCode: 200,
 			Headers: map[string]string{
 				"Access-Control-Allow-Origin":  "*",
-				"Access-Control-Allow-Headers": "Content-Type,Authorization",
-				"Access-Control-Allow-Methods": "GET,POST,PUT,DELETE,OPTIONS",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
 			},
 		}, nil
 	}
@@ -53,15 +70,76 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		return response.Error(400, "Invalid request body")
 	}
 
+	// Validate the proposal
+	var deliveryDate *time.Time
+	if req.EstimatedDeliveryDate != "" {
+		date, err := time.Parse("2006-01-02", req.EstimatedDeliveryDate)
+		if err != nil {
+			return response.Error(400, "estimated_delivery_date must be YYYY-MM-DD")
+		}
+		if date.Before(time.Now().UTC().Truncate(24 * time.Hour)) {
+			return response.Error(400, "estimated_delivery_date is in the past")
+		}
+		deliveryDate = &date
+	}
+
+	var milestoneBps []int32
+	if req.MilestoneBps != nil {
+		if len(req.MilestoneBps) != len(models.Milestones) {
+			return response.Error(400, fmt.Sprintf("milestone_bps needs %d entries (design, implementation, final)", len(models.Milestones)))
+		}
+		if err := blockchain.ValidateMilestoneBps(req.MilestoneBps); err != nil {
+			return response.Error(400, fmt.Sprintf("Invalid milestone_bps: %v", err))
+		}
+		milestoneBps = bpsColumn(req.MilestoneBps)
+	}
+
+	if len(req.AttachmentURLs) > maxAttachments {
+		return response.Error(400, fmt.Sprintf("At most %d attachment_urls allowed", maxAttachments))
+	}
+	for _, attachment := range req.AttachmentURLs {
+		u, err := url.Parse(attachment)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return response.Error(400, fmt.Sprintf("Invalid attachment URL: %s", attachment))
+		}
+	}
+
+	// The bidder proposes the team and its split; select-bidder can only take it as is
+	var team []byte
+	if len(req.Team) > 0 {
+		members, err := models.LeadFirst(claims.DID, req.Team)
+		if err != nil {
+			return response.Error(400, err.Error())
+		}
+		shares := make([]uint16, len(members))
+		for i, member := range members {
+			shares[i] = member.ShareBps
+		}
+		if err := blockchain.ValidateTeamShares(shares); err != nil {
+			return response.Error(400, err.Error())
+		}
+		req.Team = members
+		team, err = json.Marshal(members)
+		if err != nil {
+			return response.RetryableError(500, "Failed to encode team")
+		}
+	}
+
 	if err := db.InitDB(); err != nil {
-		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+		return response.RetryableError(500, fmt.Sprintf("Database error: %v", err))
 	}
 
 	pool := db.GetPool()
 
-	// Get user's credit score
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return response.RetryableError(500, "Failed to start transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	// Get user's credit score; the row lock serializes the open bid count per user
 	var creditScore int
-	err = pool.QueryRow(ctx, "SELECT credit_score FROM users WHERE did = $1", claims.DID).Scan(&creditScore)
+	err = tx.QueryRow(ctx, "SELECT credit_score FROM users WHERE did = $1 FOR UPDATE", claims.DID).Scan(&creditScore)
 	if err != nil {
 		return response.Error(404, "User not found")
 	}
@@ -72,8 +150,10 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	}
 
 	// Check task exists and is biddable
-	var taskStatus, creatorDID string
-	err = pool.QueryRow(ctx, "SELECT status, creator_did FROM tasks WHERE task_id = $1", taskID).Scan(&taskStatus, &creatorDID)
+	var taskStatus, creatorDID, rewardAmount, visibility string
+	err = tx.QueryRow(ctx, `
+		SELECT status, creator_did, reward_amount::text, visibility FROM tasks WHERE task_id = $1
+	`, taskID).Scan(&taskStatus, &creatorDID, &rewardAmount, &visibility)
 	if err != nil {
 		return response.Error(404, "Task not found")
 	}
@@ -86,33 +166,157 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		return response.Error(400, "Task is not accepting bids")
 	}
 
-	// Insert bid
-	var bidID string
-	err = pool.QueryRow(ctx, `
-		INSERT INTO task_bids (task_id, bidder_did, bid_message, credit_score_snapshot, status)
-		VALUES ($1, $2, $3, $4, 'pending')
+	// Invite-only tasks take bids from invitees who have not declined
+	if visibility == models.VisibilityInvite {
+		var invitationStatus string
+		err = tx.QueryRow(ctx, `
+			SELECT status FROM task_invitations WHERE task_id = $1 AND invitee_did = $2
+		`, taskID, claims.DID).Scan(&invitationStatus)
+		if err != nil || invitationStatus == models.InvitationStatusDeclined {
+			return response.Error(403, "Task is invite-only")
+		}
+	}
+
+	// Team members must be users other than the creator
+	if len(req.Team) > 0 {
+		dids := make([]string, len(req.Team))
+		for i, member := range req.Team {
+			if member.DID == creatorDID {
+				return response.Error(400, "Creator cannot be a team member")
+			}
+			dids[i] = member.DID
+		}
+		var found int
+		err = tx.QueryRow(ctx, "SELECT COUNT(*) FROM users WHERE did = ANY($1)", dids).Scan(&found)
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Failed to load team members: %v", err))
+		}
+		if found != len(dids) {
+			return response.Error(404, "Team member not found")
+		}
+	}
+
+	// The locked reward caps a counter-offer; the creator can raise it with POST /tasks/{id}/reward
+	var proposedReward *string
+	if req.ProposedReward != "" {
+		proposedWei, err := blockchain.ToWei(req.ProposedReward)
+		if err != nil || proposedWei.Sign() <= 0 {
+			return response.Error(400, "proposed_reward must be a positive amount")
+		}
+		rewardWei, err := blockchain.ToWei(rewardAmount)
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Invalid task reward: %v", err))
+		}
+		if proposedWei.Cmp(rewardWei) > 0 {
+			return response.Error(400, fmt.Sprintf("proposed_reward cannot exceed the task reward of %s XZT", rewardAmount))
+		}
+		proposedReward = &req.ProposedReward
+	}
+
+	// An existing bid can be edited while pending, or resubmitted once withdrawn
+	var bidID, bidStatus string
+	err = tx.QueryRow(ctx, `
+		SELECT bid_id, status FROM task_bids WHERE task_id = $1 AND bidder_did = $2 FOR UPDATE
+	`, taskID, claims.DID).Scan(&bidID, &bidStatus)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return response.RetryableError(500, fmt.Sprintf("Failed to load bid: %v", err))
+	}
+
+	action := models.BidRevisionCreated
+	switch {
+	case bidID == "":
+	case bidStatus == models.BidStatusPending:
+		action = models.BidRevisionUpdated
+	case bidStatus == models.BidStatusWithdrawn:
+		action = models.BidRevisionResubmitted
+	default:
+		return response.Error(400, fmt.Sprintf("Bid is already %s", bidStatus))
+	}
+
+	// New and resubmitted bids count against the credit-scaled open bid cap
+	if action != models.BidRevisionUpdated {
+		var openBids int
+		err = tx.QueryRow(ctx, `
+			SELECT COUNT(*) FROM task_bids WHERE bidder_did = $1 AND status = 'pending'
+		`, claims.DID).Scan(&openBids)
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Failed to count open bids: %v", err))
+		}
+		if limit := models.OpenBidLimit(creditScore); openBids >= limit {
+			return response.Error(403, fmt.Sprintf("Open bid limit reached (%d for credit score %d); withdraw a bid first", limit, creditScore))
+		}
+	}
+
+	// Insert or update bid
+	err = tx.QueryRow(ctx, `
+		INSERT INTO task_bids (task_id, bidder_did, bid_message, credit_score_snapshot, status,
+			proposed_reward, estimated_delivery_date, proposed_milestone_bps, attachment_urls, reward_snapshot, proposed_team)
+		VALUES ($1, $2, $3, $4, 'pending', $5, $6, $7, $8, $9, $10::jsonb)
 		ON CONFLICT (task_id, bidder_did) DO UPDATE
-		SET bid_message = $3, updated_at = CURRENT_TIMESTAMP
+		SET bid_message = $3, credit_score_snapshot = $4, status = 'pending',
+		    proposed_reward = $5, estimated_delivery_date = $6,
+		    proposed_milestone_bps = $7, attachment_urls = $8, reward_snapshot = $9,
+		    proposed_team = $10::jsonb, updated_at = CURRENT_TIMESTAMP
 		RETURNING bid_id
-	`, taskID, claims.DID, req.Message, creditScore).Scan(&bidID)
+	`, taskID, claims.DID, req.Message, creditScore,
+		proposedReward, deliveryDate, milestoneBps, req.AttachmentURLs, rewardAmount, nullableJSON(team)).Scan(&bidID)
 	if err != nil {
-		return response.Error(500, fmt.Sprintf("Failed to create bid: %v", err))
+		return response.RetryableError(500, fmt.Sprintf("Failed to create bid: %v", err))
+	}
+
+	// Keep every version of the bid
+	if err := bids.RecordRevision(ctx, tx, bidID, action); err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Failed to record bid revision: %v", err))
+	}
+
+	// Bidding on an invite-only task accepts the invitation
+	if visibility == models.VisibilityInvite {
+		_, err = tx.Exec(ctx, `
+			UPDATE task_invitations SET status = 'accepted', responded_at = CURRENT_TIMESTAMP
+			WHERE task_id = $1 AND invitee_did = $2 AND status = 'pending'
+		`, taskID, claims.DID)
+		if err != nil {
+			return response.RetryableError(500, "Failed to accept invitation")
+		}
 	}
 
 	// Update task status to bidding if it was pending
 	if taskStatus == "pending" {
-		_, err = pool.Exec(ctx, "UPDATE tasks SET status = 'bidding' WHERE task_id = $1", taskID)
+		_, err = tx.Exec(ctx, "UPDATE tasks SET status = 'bidding' WHERE task_id = $1", taskID)
 		if err != nil {
-			return response.Error(500, "Failed to update task status")
+			return response.RetryableError(500, "Failed to update task status")
 		}
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return response.Error(500, "Failed to commit transaction")
+	}
+
 	return response.Success(BidTaskResponse{
 		BidID:  bidID,
 		Status: "pending",
+		Action: action,
 	})
 }
 
+// bpsColumn converts a schedule for the proposed_milestone_bps INT[] column
+func bpsColumn(bps []uint16) []int32 {
+	column := make([]int32, len(bps))
+	for i, share := range bps {
+		column[i] = int32(share)
+	}
+	return column
+}
+
+// nullableJSON stores an absent value as SQL NULL
+func nullableJSON(value []byte) *string {
+	if value == nil {
+		return nil
+	}
+	text := string(value)
+	return &text
+}
+
 func main() {
-	lambda.Start(handler)
+	lambda.Start(idempotency.Wrap(handler))
 }
@@ -2,25 +2,37 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/big"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/x-zero/xz-wallet/pkg/auth"
 	"github.com/x-zero/xz-wallet/pkg/blockchain"
 	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/idempotency"
 	"github.com/x-zero/xz-wallet/pkg/response"
 )
 
+// CancelTaskRequest carries the signed authorization a v2 escrow needs.
+// The body is optional for tasks on a v1 escrow.
+type CancelTaskRequest struct {
+	ExecutorAmount    string `json:"executor_amount,omitempty"`    // XZT paid to the executor from the remainder (v2 only)
+	CreatorSignature  string `json:"creator_signature,omitempty"`  // creator's EIP-712 CancelTask signature
+	ExecutorSignature string `json:"executor_signature,omitempty"` // executor's signature, required when executor_amount > 0
+	Deadline          int64  `json:"deadline,omitempty"`
+}
+
 func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	if request.HTTPMethod == "OPTIONS" {
 		return events.APIGatewayProxyResponse{
 			StatusCode: 200,
 			Headers: map[string]string{
 				"Access-Control-Allow-Origin":  "*",
-				"Access-Control-Allow-Headers": "Content-Type,Authorization",
-				"Access-Control-Allow-Methods": "POST,OPTIONS",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
 			},
 		}, nil
 	}
@@ -40,8 +52,15 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		return response.Error(400, "Missing task ID")
 	}
 
+	var req CancelTaskRequest
+	if request.Body != "" {
+		if err := json.Unmarshal([]byte(request.Body), &req); err != nil {
+			return response.Error(400, "Invalid request body")
+		}
+	}
+
 	if err := db.InitDB(); err != nil {
-		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+		return response.RetryableError(500, fmt.Sprintf("Database error: %v", err))
 	}
 
 	pool := db.GetPool()
@@ -53,11 +72,14 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		ExecutorDID    *string
 		Status         string
 		PaidAmount     string
+		ChainID        *int64
+		EscrowAddress  *string
 	}
 	err = pool.QueryRow(ctx, `
-		SELECT contract_task_id, creator_did, executor_did, status, paid_amount 
+		SELECT contract_task_id, creator_did, executor_did, status, paid_amount, chain_id, escrow_address
 		FROM tasks WHERE task_id = $1
-	`, taskID).Scan(&task.ContractTaskID, &task.CreatorDID, &task.ExecutorDID, &task.Status, &task.PaidAmount)
+	`, taskID).Scan(&task.ContractTaskID, &task.CreatorDID, &task.ExecutorDID, &task.Status, &task.PaidAmount,
+		&task.ChainID, &task.EscrowAddress)
 	if err != nil {
 		return response.Error(404, "Task not found")
 	}
@@ -75,10 +97,10 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		return response.Error(400, fmt.Sprintf("Cannot cancel task in status: %s", task.Status))
 	}
 
-	// Initialize blockchain client
-	client, err := blockchain.InitClient()
+	// Settle on the escrow that holds the task
+	client, err := blockchain.ClientForTask(task.ChainID, task.EscrowAddress)
 	if err != nil {
-		return response.Error(500, fmt.Sprintf("Blockchain error: %v", err))
+		return response.RetryableError(500, fmt.Sprintf("Blockchain error: %v", err))
 	}
 
 	// Calculate executor amount for cancellation
@@ -86,11 +108,54 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	// Since executor has already been paid (task.PaidAmount), we don't pay them again
 	// They keep what they've already received
 	executorAmount := big.NewInt(0)
+	if req.ExecutorAmount != "" {
+		executorAmount, err = blockchain.ToWei(req.ExecutorAmount)
+		if err != nil {
+			return response.Error(400, fmt.Sprintf("Invalid executor_amount: %v", err))
+		}
+	}
 
-	// Cancel task on blockchain
-	txHash, err := client.CancelTask(uint64(task.ContractTaskID), executorAmount)
-	if err != nil {
-		return response.Error(500, fmt.Sprintf("Failed to cancel task on blockchain: %v", err))
+	// Cancel task on blockchain. A v2 escrow needs the creator's signature,
+	// and the executor's too when the executor gets part of the remainder.
+	var txHash string
+	if client.IsEscrowV2() {
+		if req.CreatorSignature == "" || req.Deadline == 0 {
+			return response.Error(400, "creator_signature and deadline are required: sign the authorization from GET /tasks/{id}/escrow-authorization")
+		}
+		creatorSig, err := blockchain.ParseSignature(req.CreatorSignature)
+		if err != nil {
+			return response.Error(400, fmt.Sprintf("creator_signature: %v", err))
+		}
+		var executorSig []byte
+		if executorAmount.Sign() > 0 {
+			if req.ExecutorSignature == "" {
+				return response.Error(400, "executor_signature is required when executor_amount is set")
+			}
+			executorSig, err = blockchain.ParseSignature(req.ExecutorSignature)
+			if err != nil {
+				return response.Error(400, fmt.Sprintf("executor_signature: %v", err))
+			}
+		}
+		authorization, err := client.NewCancelAuthorization(ctx, uint64(task.ContractTaskID), executorAmount, big.NewInt(req.Deadline))
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Failed to build authorization: %v", err))
+		}
+		if err := client.VerifyEscrowSignatures(ctx, authorization, creatorSig, executorSig); err != nil {
+			return response.Error(400, fmt.Sprintf("Invalid signature: %v", err))
+		}
+		txHash, err = client.CancelTaskSigned(ctx, authorization, creatorSig, executorSig)
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Failed to cancel task on blockchain: %v", err))
+		}
+	} else {
+		// Without signatures the admin alone must not split funds
+		if executorAmount.Sign() > 0 {
+			return response.Error(400, "executor_amount requires a v2 escrow")
+		}
+		txHash, err = client.CancelTask(uint64(task.ContractTaskID), executorAmount)
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Failed to cancel task on blockchain: %v", err))
+		}
 	}
 
 	// Update task status to cancelled
@@ -105,6 +170,13 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		return response.Error(500, fmt.Sprintf("Failed to cancel task: %v", err))
 	}
 
+	// The executor's share of the remainder was split among the team
+	if executorAmount.Sign() > 0 {
+		if err := recordTeamPayout(ctx, pool, taskID, executorAmount); err != nil {
+			return response.Error(500, fmt.Sprintf("Failed to update team payouts: %v", err))
+		}
+	}
+
 	// Apply credit score penalty if executor quits mid-task
 	if isExecutor && task.ExecutorDID != nil {
 		var creditPenalty int
@@ -142,6 +214,44 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	})
 }
 
+// recordTeamPayout adds each team member's split of an executor payment to
+// their paid amount, splitting like the escrow; tasks without a team have no rows
+func recordTeamPayout(ctx context.Context, pool *pgxpool.Pool, taskID string, amount *big.Int) error {
+	rows, err := pool.Query(ctx, `
+		SELECT member_did, share_bps FROM task_team_members WHERE task_id = $1 ORDER BY position
+	`, taskID)
+	if err != nil {
+		return err
+	}
+	var members []string
+	var shares []uint16
+	for rows.Next() {
+		var did string
+		var share int32
+		if err := rows.Scan(&did, &share); err != nil {
+			rows.Close()
+			return err
+		}
+		members = append(members, did)
+		shares = append(shares, uint16(share))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i, paid := range blockchain.TeamSplit(amount, shares) {
+		_, err = pool.Exec(ctx, `
+			UPDATE task_team_members SET paid_amount = paid_amount + $1
+			WHERE task_id = $2 AND member_did = $3
+		`, blockchain.FromWei(paid, 8), taskID, members[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func main() {
-	lambda.Start(handler)
+	lambda.Start(idempotency.Wrap(handler))
 }
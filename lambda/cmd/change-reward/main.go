@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v5"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/bids"
+	"github.com/x-zero/xz-wallet/pkg/blockchain"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/idempotency"
+	"github.com/x-zero/xz-wallet/pkg/models"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+// ChangeRewardRequest raises or lowers a task's reward. Raising it needs the
+// creator's consent to the top-up: a permit, or a TopUp signature from
+// GET /tasks/{id}/escrow-authorization?action=top_up.
+type ChangeRewardRequest struct {
+	RewardAmount     string                `json:"reward_amount"`               // New total reward in XZT
+	Permit           *models.PermitRequest `json:"permit,omitempty"`            // Covers the top-up when raising the reward
+	CreatorSignature string                `json:"creator_signature,omitempty"` // creator's EIP-712 TopUp signature, without a permit
+	Deadline         int64                 `json:"deadline,omitempty"`
+}
+
+type ChangeRewardResponse struct {
+	TaskID          string `json:"task_id"`
+	OldReward       string `json:"old_reward"`
+	NewReward       string `json:"new_reward"`
+	Change          string `json:"change"` // "top_up" or "refund"
+	Amount          string `json:"amount"`
+	TxHash          string `json:"tx_hash"`
+	BiddersNotified int64  `json:"bidders_notified"`
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	taskID := request.PathParameters["id"]
+	if taskID == "" {
+		return response.Error(400, "Missing task ID")
+	}
+
+	var req ChangeRewardRequest
+	if err := json.Unmarshal([]byte(request.Body), &req); err != nil {
+		return response.Error(400, "Invalid request body")
+	}
+	newWei, err := blockchain.ToWei(req.RewardAmount)
+	if err != nil || newWei.Sign() <= 0 {
+		return response.Error(400, "reward_amount must be a positive amount")
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	// The task row stays locked until the change is recorded, so two changes
+	// cannot both compute their delta from the same total
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return response.RetryableError(500, "Failed to start transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	// Get task
+	var task struct {
+		ContractTaskID int64
+		CreatorDID     string
+		ExecutorDID    *string
+		Status         string
+		TaskName       string
+		ChainID        *int64
+		EscrowAddress  *string
+	}
+	err = tx.QueryRow(ctx, `
+		SELECT contract_task_id, creator_did, executor_did, status, task_name, chain_id, escrow_address
+		FROM tasks WHERE task_id = $1 FOR UPDATE
+	`, taskID).Scan(&task.ContractTaskID, &task.CreatorDID, &task.ExecutorDID, &task.Status, &task.TaskName,
+		&task.ChainID, &task.EscrowAddress)
+	if err != nil {
+		return response.Error(404, "Task not found")
+	}
+
+	if task.CreatorDID != claims.DID {
+		return response.Error(403, "Only creator can change the reward")
+	}
+	waiting := task.Status == models.TaskStatusBidding || task.Status == models.TaskStatusBlocked
+	if !waiting || task.ExecutorDID != nil || task.ContractTaskID < 0 {
+		return response.Error(400, "Reward can only change while bidding")
+	}
+
+	// Settle on the escrow that holds the task
+	client, err := blockchain.ClientForTask(task.ChainID, task.EscrowAddress)
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Blockchain error: %v", err))
+	}
+	if !client.IsEscrowV2() {
+		return response.Error(400, "Task escrow cannot change a locked reward; cancel and recreate the task")
+	}
+
+	// The escrow only adjusts tasks without an executor, and the amount it
+	// holds is the old reward
+	_, onChainExecutor, oldWei, _, _, err := client.GetTask(uint64(task.ContractTaskID))
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Failed to read task from escrow: %v", err))
+	}
+	if common.HexToAddress(onChainExecutor) != (common.Address{}) {
+		return response.Error(400, "Task has an executor on chain; its reward can no longer change")
+	}
+	delta := new(big.Int).Sub(newWei, oldWei)
+	if delta.Sign() == 0 {
+		return response.Error(400, "reward_amount is unchanged")
+	}
+
+	var txHash, change string
+	amount := new(big.Int).Abs(delta)
+	if delta.Sign() > 0 {
+		change = "top_up"
+
+		var ethAddress string
+		err = tx.QueryRow(ctx, "SELECT eth_address FROM users WHERE did = $1", claims.DID).Scan(&ethAddress)
+		if err != nil {
+			return response.Error(404, "User not found")
+		}
+
+		// The creator consents with a permit for the amount, or signs the
+		// top-up and the escrow pulls it from their allowance
+		var permit *blockchain.PermitSignature
+		var authorization *blockchain.EscrowAuthorization
+		var creatorSig []byte
+		if req.Permit != nil {
+			permit, err = blockchain.ParsePermit(req.Permit.Deadline, req.Permit.V, req.Permit.R, req.Permit.S)
+			if err != nil {
+				return response.Error(400, fmt.Sprintf("Invalid permit: %v", err))
+			}
+			err = client.CheckBalance(ctx, common.HexToAddress(ethAddress), amount)
+		} else {
+			if req.CreatorSignature == "" || req.Deadline == 0 {
+				return response.Error(400, "permit, or creator_signature and deadline, are required to raise the reward: sign the top_up authorization from GET /tasks/{id}/escrow-authorization")
+			}
+			creatorSig, err = blockchain.ParseSignature(req.CreatorSignature)
+			if err != nil {
+				return response.Error(400, fmt.Sprintf("creator_signature: %v", err))
+			}
+			authorization, err = client.NewTopUpAuthorization(ctx, uint64(task.ContractTaskID), amount, big.NewInt(req.Deadline))
+			if err != nil {
+				return response.RetryableError(500, fmt.Sprintf("Failed to build authorization: %v", err))
+			}
+			if err := client.VerifyEscrowSignatures(ctx, authorization, creatorSig, nil); err != nil {
+				return response.Error(400, fmt.Sprintf("Invalid signature: %v", err))
+			}
+
+			approver, approverErr := blockchain.NewApproverFromEnv(client)
+			if approverErr != nil {
+				return response.RetryableError(500, fmt.Sprintf("Approver error: %v", approverErr))
+			}
+			err = client.EnsureAllowance(ctx, approver, blockchain.ApprovalRequest{
+				Owner:     common.HexToAddress(ethAddress),
+				Amount:    amount,
+				AuthToken: authHeader,
+			})
+		}
+		switch {
+		case errors.Is(err, blockchain.ErrInsufficientBalance):
+			return response.Error(400, fmt.Sprintf("Insufficient XZT balance. Required: %s XZT", blockchain.FromWei(amount, 8)))
+		case errors.Is(err, blockchain.ErrApprovalRequired):
+			return response.Error(400, "Escrow allowance insufficient. Please sign a permit for the top-up amount.")
+		case err != nil:
+			return response.RetryableError(500, fmt.Sprintf("Failed to approve escrow contract: %v. Please try again.", err))
+		}
+
+		if permit != nil {
+			txHash, err = client.TopUpWithPermit(uint64(task.ContractTaskID), amount, permit)
+		} else {
+			txHash, err = client.TopUpSigned(ctx, authorization, creatorSig)
+		}
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Failed to top up task on blockchain: %v", err))
+		}
+	} else {
+		change = "refund"
+		txHash, err = client.RefundPartial(uint64(task.ContractTaskID), amount)
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Failed to refund on blockchain: %v", err))
+		}
+	}
+
+	// Record the change, tell pending bidders and re-snapshot their bids
+	newReward := blockchain.FromWei(newWei, 8)
+	oldReward := blockchain.FromWei(oldWei, 8)
+	biddersNotified, err := recordRewardChange(ctx, tx, taskID, claims.DID, task.TaskName, oldReward, newReward, txHash)
+	if err == nil {
+		err = tx.Commit(ctx)
+	}
+	if err != nil {
+		// The escrow already holds the new amount; log for manual recovery
+		fmt.Printf("CRITICAL: Reward changed on blockchain (task_id=%s, %s -> %s, tx=%s) but failed to update database: %v\n",
+			taskID, oldReward, newReward, txHash, err)
+		return response.Error(500, fmt.Sprintf("Reward changed on blockchain but database update failed. TX: %s. Please contact support.", txHash))
+	}
+
+	return response.Success(ChangeRewardResponse{
+		TaskID:          taskID,
+		OldReward:       oldReward,
+		NewReward:       newReward,
+		Change:          change,
+		Amount:          blockchain.FromWei(amount, 8),
+		TxHash:          txHash,
+		BiddersNotified: biddersNotified,
+	})
+}
+
+// recordRewardChange stores the new reward and its edit, notifies pending
+// bidders and re-snapshots their bids in the caller's transaction.
+// Counter-offers above the new reward are cleared. Returns how many bidders
+// were notified.
+func recordRewardChange(ctx context.Context, tx pgx.Tx, taskID, editorDID, taskName, oldReward, newReward, txHash string) (int64, error) {
+	_, err := tx.Exec(ctx, `
+		UPDATE tasks SET reward_amount = $1, updated_at = CURRENT_TIMESTAMP WHERE task_id = $2
+	`, newReward, taskID)
+	if err != nil {
+		return 0, fmt.Errorf("update task: %w", err)
+	}
+
+	changes, err := json.Marshal(map[string]models.FieldChange{
+		"reward_amount": {Old: oldReward, New: newReward},
+	})
+	if err != nil {
+		return 0, err
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO task_edits (task_id, editor_did, changes, tx_hash) VALUES ($1, $2, $3::jsonb, $4)
+	`, taskID, editorDID, string(changes), txHash)
+	if err != nil {
+		return 0, fmt.Errorf("record edit: %w", err)
+	}
+
+	// Notify before re-snapshotting, while the bids still show the old counter-offers
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO notifications (user_did, task_id, type, payload)
+		SELECT b.bidder_did, b.task_id, $2,
+		       jsonb_build_object(
+		           'task_name', $3::text,
+		           'old_reward', $4::text,
+		           'new_reward', $5::text,
+		           'proposed_reward_cleared', COALESCE(b.proposed_reward > $5::numeric, false)
+		       )
+		FROM task_bids b
+		WHERE b.task_id = $1 AND b.status = 'pending'
+	`, taskID, models.NotificationTaskRewardChanged, taskName, oldReward, newReward)
+	if err != nil {
+		return 0, fmt.Errorf("notify bidders: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE task_bids b
+		SET reward_snapshot = $2::numeric,
+		    credit_score_snapshot = u.credit_score,
+		    proposed_reward = CASE WHEN b.proposed_reward > $2::numeric THEN NULL ELSE b.proposed_reward END,
+		    updated_at = CURRENT_TIMESTAMP
+		FROM users u
+		WHERE b.bidder_did = u.did AND b.task_id = $1 AND b.status = 'pending'
+	`, taskID, newReward)
+	if err != nil {
+		return 0, fmt.Errorf("re-snapshot bids: %w", err)
+	}
+
+	if err := bids.RecordTaskRevisions(ctx, tx, taskID, models.BidRevisionRewardChanged); err != nil {
+		return 0, fmt.Errorf("record bid revisions: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func main() {
+	lambda.Start(idempotency.Wrap(handler))
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+type ClearPayoutAddressResponse struct {
+	PayoutAddress   string  `json:"payout_address"` // Now the user's eth_address
+	PreviousAddress *string `json:"previous_address,omitempty"`
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return response.Error(500, "Failed to start transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	var ethAddress string
+	var previous *string
+	err = tx.QueryRow(ctx, `
+		SELECT eth_address, payout_address FROM users WHERE did = $1 FOR UPDATE
+	`, claims.DID).Scan(&ethAddress, &previous)
+	if err != nil {
+		return response.Error(404, "User not found")
+	}
+
+	// Nothing to change or log
+	if previous == nil {
+		return response.Success(ClearPayoutAddressResponse{PayoutAddress: ethAddress})
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE users SET payout_address = NULL, payout_address_verified_at = NULL
+		WHERE did = $1
+	`, claims.DID)
+	if err != nil {
+		return response.Error(500, "Failed to clear payout address")
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO payout_address_changes (user_did, old_address, new_address, source_ip, user_agent)
+		VALUES ($1, $2, NULL, $3, $4)
+	`, claims.DID, previous, request.RequestContext.Identity.SourceIP, request.RequestContext.Identity.UserAgent)
+	if err != nil {
+		return response.Error(500, "Failed to record payout address change")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return response.Error(500, "Failed to commit transaction")
+	}
+
+	return response.Success(ClearPayoutAddressResponse{
+		PayoutAddress:   ethAddress,
+		PreviousAddress: previous,
+	})
+}
+
+func main() {
+	lambda.Start(handler)
+}
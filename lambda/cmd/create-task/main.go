@@ -1,41 +1,56 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"math/big"
-	"net/http"
-	"os"
-	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/x-zero/xz-wallet/pkg/auth"
 	"github.com/x-zero/xz-wallet/pkg/blockchain"
 	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/idempotency"
+	"github.com/x-zero/xz-wallet/pkg/models"
 	"github.com/x-zero/xz-wallet/pkg/response"
+	"github.com/x-zero/xz-wallet/pkg/taskgraph"
 )
 
 type CreateTaskRequest struct {
-	ProjectID          string   `json:"project_id"`
-	TaskName           string   `json:"task_name"`
-	TaskDescription    string   `json:"task_description"`
-	AcceptanceCriteria string   `json:"acceptance_criteria"`
-	RewardAmount       string   `json:"reward_amount"`
-	Visibility         string   `json:"visibility"`
-	ProfessionTags     []string `json:"profession_tags"`
+	ProjectID          string                `json:"project_id"`
+	TaskName           string                `json:"task_name"`
+	TaskDescription    string                `json:"task_description"`
+	AcceptanceCriteria string                `json:"acceptance_criteria"`
+	RewardAmount       string                `json:"reward_amount"`
+	Visibility         string                `json:"visibility"`
+	ProfessionTags     []string              `json:"profession_tags"`
+	MilestoneBps       []uint16              `json:"milestone_bps,omitempty"` // design/implementation/final shares, default 3000/5000/2000
+	InvitedDIDs        []string              `json:"invited_dids,omitempty"`  // Required for invite visibility
+	DirectAssign       bool                  `json:"direct_assign,omitempty"` // Make the single invitee the executor right away
+	ParentTaskID       string                `json:"parent_task_id,omitempty"`
+	DependsOn          []string              `json:"depends_on,omitempty"` // Tasks that must complete before bidding opens
+	Permit             *models.PermitRequest `json:"permit,omitempty"`
 }
 
+// Most users an invite-only task may name
+const maxInvitees = 20
+
+// Most prerequisites a task may be created with
+const maxDependencies = 20
+
 type CreateTaskResponse struct {
 	TaskID         string `json:"task_id"`
 	ContractTaskID int64  `json:"contract_task_id"`
+	ChainID        int64  `json:"chain_id"`
+	EscrowAddress  string `json:"escrow_address"`
 	TxHash         string `json:"tx_hash"`
+	ExplorerURL    string `json:"explorer_url,omitempty"`
 	Status         string `json:"status"`
+	ExecutorDID    string `json:"executor_did,omitempty"`
+	AssignTxHash   string `json:"assign_tx_hash,omitempty"`
 }
 
 func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
@@ -44,8 +59,8 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 			StatusCode: 200,
 			Headers: map[string]string{
 				"Access-Control-Allow-Origin":  "*",
-				"Access-Control-Allow-Headers": "Content-Type,Authorization",
-				"Access-Control-Allow-Methods": "GET,POST,PUT,DELETE,OPTIONS",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
 			},
 		}, nil
 	}
@@ -70,17 +85,54 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	if req.ProjectID == "" || req.TaskName == "" || req.RewardAmount == "" {
 		return response.Error(400, "Missing required fields")
 	}
-	if req.Visibility != "project" && req.Visibility != "global" {
+	if req.Visibility != models.VisibilityProject && req.Visibility != models.VisibilityGlobal && req.Visibility != models.VisibilityInvite {
 		return response.Error(400, "Invalid visibility")
 	}
+	assignmentMode := models.AssignmentBidding
+	if req.Visibility == models.VisibilityInvite {
+		if len(req.InvitedDIDs) == 0 || len(req.InvitedDIDs) > maxInvitees {
+			return response.Error(400, fmt.Sprintf("invite visibility needs 1 to %d invited_dids", maxInvitees))
+		}
+		seen := make(map[string]bool)
+		for _, did := range req.InvitedDIDs {
+			if did == claims.DID {
+				return response.Error(400, "Cannot invite yourself")
+			}
+			if seen[did] {
+				return response.Error(400, fmt.Sprintf("Duplicate invited DID: %s", did))
+			}
+			seen[did] = true
+		}
+		if req.DirectAssign {
+			if len(req.InvitedDIDs) != 1 {
+				return response.Error(400, "direct_assign needs exactly one invited DID")
+			}
+			assignmentMode = models.AssignmentDirect
+		}
+	} else if len(req.InvitedDIDs) > 0 || req.DirectAssign {
+		return response.Error(400, "invited_dids and direct_assign need invite visibility")
+	}
+	milestoneBps := models.DefaultMilestoneBps
+	if req.MilestoneBps != nil {
+		if len(req.MilestoneBps) != len(models.Milestones) {
+			return response.Error(400, fmt.Sprintf("milestone_bps needs %d entries (design, implementation, final)", len(models.Milestones)))
+		}
+		if err := blockchain.ValidateMilestoneBps(req.MilestoneBps); err != nil {
+			return response.Error(400, fmt.Sprintf("Invalid milestone_bps: %v", err))
+		}
+		milestoneBps = req.MilestoneBps
+	}
+	if len(req.DependsOn) > maxDependencies {
+		return response.Error(400, fmt.Sprintf("depends_on allows at most %d tasks", maxDependencies))
+	}
 
 	// Initialize
 	if err := db.InitDB(); err != nil {
-		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+		return response.RetryableError(500, fmt.Sprintf("Database error: %v", err))
 	}
 	client, err := blockchain.InitClient()
 	if err != nil {
-		return response.Error(500, fmt.Sprintf("Blockchain error: %v", err))
+		return response.RetryableError(500, fmt.Sprintf("Blockchain error: %v", err))
 	}
 
 	pool := db.GetPool()
@@ -92,66 +144,152 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		return response.Error(404, "User not found")
 	}
 
-	// Convert amount to wei
-	amountFloat := new(big.Float)
-	amountFloat.SetString(req.RewardAmount)
-	multiplier := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
-	amountFloat.Mul(amountFloat, multiplier)
-	amountWei, _ := amountFloat.Int(nil)
+	// Every invitee must exist; a direct assignee is paid at their payout address
+	var executorAddress string
+	for _, did := range req.InvitedDIDs {
+		var payoutAddress string
+		err = pool.QueryRow(ctx, `
+			SELECT COALESCE(payout_address, eth_address) FROM users WHERE did = $1
+		`, did).Scan(&payoutAddress)
+		if err != nil {
+			return response.Error(404, fmt.Sprintf("Invited user not found: %s", did))
+		}
+		executorAddress = payoutAddress
+	}
 
-	// Check if user has approved escrow contract
-	allowance, err := client.Token.Allowance(&bind.CallOpts{}, common.HexToAddress(ethAddress), client.EscrowAddress)
-	if err != nil {
-		return response.Error(500, fmt.Sprintf("Failed to check allowance: %v", err))
+	// Parent and prerequisites must be live tasks of the same project; only
+	// the parent's creator adds subtasks
+	if req.ParentTaskID != "" {
+		var projectID, creatorDID, status string
+		err = pool.QueryRow(ctx, `
+			SELECT project_id, creator_did, status FROM tasks WHERE task_id = $1
+		`, req.ParentTaskID).Scan(&projectID, &creatorDID, &status)
+		if err != nil {
+			return response.Error(404, "Parent task not found")
+		}
+		if projectID != req.ProjectID || creatorDID != claims.DID || status == models.TaskStatusCancelled {
+			return response.Error(400, "Parent task must be an open task you created in the same project")
+		}
 	}
+	seenDependencies := make(map[string]bool)
+	for _, dependsOn := range req.DependsOn {
+		if seenDependencies[dependsOn] {
+			return response.Error(400, fmt.Sprintf("Duplicate dependency: %s", dependsOn))
+		}
+		seenDependencies[dependsOn] = true
 
-	// If allowance is insufficient, call approve-escrow Lambda
-	if allowance.Cmp(amountWei) < 0 {
-		// Check user balance first
-		userBalance, err := client.Token.BalanceOf(&bind.CallOpts{}, common.HexToAddress(ethAddress))
+		var projectID, status string
+		err = pool.QueryRow(ctx, "SELECT project_id, status FROM tasks WHERE task_id = $1", dependsOn).Scan(&projectID, &status)
 		if err != nil {
-			return response.Error(500, fmt.Sprintf("Failed to check user balance: %v", err))
+			return response.Error(404, fmt.Sprintf("Dependency not found: %s", dependsOn))
+		}
+		if projectID != req.ProjectID || status == models.TaskStatusCancelled {
+			return response.Error(400, fmt.Sprintf("Dependency %s must be an open task in the same project", dependsOn))
 		}
-		
-		if userBalance.Cmp(amountWei) < 0 {
-			return response.Error(400, fmt.Sprintf("Insufficient XZT balance. Required: %s XZT, Available: %s XZT", 
-				req.RewardAmount, 
-				new(big.Float).Quo(new(big.Float).SetInt(userBalance), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))).Text('f', 2)))
+		// A direct assignee starts at once, so every prerequisite must be done
+		if req.DirectAssign && status != models.TaskStatusCompleted {
+			return response.Error(400, fmt.Sprintf("direct_assign needs completed dependencies; %s is %s", dependsOn, status))
 		}
-		
-		// Call approve-escrow Lambda to automatically approve
-		fmt.Printf("Allowance insufficient, calling approve-escrow Lambda...\n")
-		err = callApproveEscrow(authHeader, client.TokenAddress.Hex(), client.EscrowAddress.Hex())
+	}
+
+	// Convert amount to wei
+	amountWei, err := blockchain.ToWei(req.RewardAmount)
+	if err != nil || amountWei.Sign() <= 0 {
+		return response.Error(400, "reward_amount must be a positive XZT amount with at most 18 decimals")
+	}
+
+	// With a permit the escrow pulls the reward in the same transaction as
+	// createTask; otherwise the allowance must be in place beforehand
+	var permit *blockchain.PermitSignature
+	if req.Permit != nil {
+		permit, err = blockchain.ParsePermit(req.Permit.Deadline, req.Permit.V, req.Permit.R, req.Permit.S)
 		if err != nil {
-			return response.Error(500, fmt.Sprintf("Failed to approve escrow contract: %v. Please try again.", err))
+			return response.Error(400, fmt.Sprintf("Invalid permit: %v", err))
+		}
+		err = client.CheckBalance(ctx, common.HexToAddress(ethAddress), amountWei)
+	} else {
+		approver, approverErr := blockchain.NewApproverFromEnv(client)
+		if approverErr != nil {
+			return response.RetryableError(500, fmt.Sprintf("Approver error: %v", approverErr))
 		}
-		fmt.Printf("Escrow approved successfully, waiting for network propagation...\n")
-		
-		// Wait a bit for the approval transaction to fully propagate
-		// This helps avoid "in-flight transaction limit" errors on Alchemy free tier
-		time.Sleep(3 * time.Second)
+		err = client.EnsureAllowance(ctx, approver, blockchain.ApprovalRequest{
+			Owner:     common.HexToAddress(ethAddress),
+			Amount:    amountWei,
+			AuthToken: authHeader,
+		})
+	}
+	switch {
+	case errors.Is(err, blockchain.ErrInsufficientBalance):
+		userBalance, balErr := client.GetBalance(ethAddress)
+		if balErr != nil {
+			return response.Error(400, fmt.Sprintf("Insufficient XZT balance. Required: %s XZT", req.RewardAmount))
+		}
+		return response.Error(400, fmt.Sprintf("Insufficient XZT balance. Required: %s XZT, Available: %s XZT",
+			req.RewardAmount,
+			new(big.Float).Quo(new(big.Float).SetInt(userBalance), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))).Text('f', 2)))
+	case errors.Is(err, blockchain.ErrApprovalRequired):
+		return response.Error(400, "Escrow allowance insufficient. Please sign a permit for the escrow contract.")
+	case err != nil:
+		return response.RetryableError(500, fmt.Sprintf("Failed to approve escrow contract: %v. Please try again.", err))
 	}
 
 	// Insert into database FIRST with pending status
 	// This way if blockchain fails, we can mark as cancelled
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return response.RetryableError(500, "Failed to start transaction")
+	}
+	defer tx.Rollback(ctx)
+
 	var taskID string
-	err = pool.QueryRow(ctx, `
+	err = tx.QueryRow(ctx, `
 		INSERT INTO tasks (
 			contract_task_id, project_id, creator_did, task_name, 
 			task_description, acceptance_criteria, reward_amount, 
-			visibility, status, profession_tags
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			visibility, status, profession_tags, chain_id, escrow_address, milestone_bps,
+			assignment_mode, parent_task_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NULLIF($15, '')::uuid)
 		RETURNING task_id
 	`, -1, req.ProjectID, claims.DID, req.TaskName,
 		req.TaskDescription, req.AcceptanceCriteria, req.RewardAmount,
-		req.Visibility, "pending", req.ProfessionTags).Scan(&taskID)
+		req.Visibility, "pending", req.ProfessionTags,
+		client.ChainID.Int64(), client.EscrowAddress.Hex(), models.MilestoneColumn(milestoneBps),
+		assignmentMode, req.ParentTaskID).Scan(&taskID)
 	if err != nil {
-		return response.Error(500, fmt.Sprintf("Failed to save task: %v", err))
+		return response.RetryableError(500, fmt.Sprintf("Failed to save task: %v", err))
+	}
+
+	for _, did := range req.InvitedDIDs {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO task_invitations (task_id, invitee_did) VALUES ($1, $2)
+		`, taskID, did)
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Failed to save invitation: %v", err))
+		}
+	}
+
+	for _, dependsOn := range req.DependsOn {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO task_dependencies (task_id, depends_on_task_id) VALUES ($1, $2)
+		`, taskID, dependsOn)
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Failed to save dependency: %v", err))
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return response.Error(500, "Failed to commit transaction")
 	}
 	fmt.Printf("Task saved to database with ID: %s, now creating on blockchain...\n", taskID)
 
 	// Create task on blockchain
-	contractTaskID, txHash, err := client.CreateTask(ethAddress, amountWei)
+	var contractTaskID uint64
+	var txHash string
+	if permit != nil {
+		contractTaskID, txHash, err = client.CreateTaskWithPermit(ethAddress, amountWei, milestoneBps, permit)
+	} else {
+		contractTaskID, txHash, err = client.CreateTask(ethAddress, amountWei, milestoneBps)
+	}
 	if err != nil {
 		// Blockchain failed, mark task as cancelled in database
 		_, updateErr := pool.Exec(ctx, `
@@ -167,94 +305,69 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		return response.Error(500, fmt.Sprintf("Failed to create task on blockchain: %v", err))
 	}
 
-	// Update task with contract_task_id and set status to bidding
+	// Update task with contract_task_id and open bidding, unless it still
+	// waits on prerequisites
+	openStatus := models.TaskStatusBidding
+	if len(req.DependsOn) > 0 {
+		unmet, unmetErr := taskgraph.UnmetDependencies(ctx, pool, taskID)
+		if unmetErr != nil || unmet > 0 {
+			openStatus = models.TaskStatusBlocked
+		}
+	}
 	_, err = pool.Exec(ctx, `
 		UPDATE tasks 
 		SET contract_task_id = $1,
-		    status = 'bidding',
+		    status = $2,
 		    updated_at = NOW()
-		WHERE task_id = $2
-	`, contractTaskID, taskID)
+		WHERE task_id = $3
+	`, contractTaskID, openStatus, taskID)
 	if err != nil {
 		// This is bad - blockchain succeeded but database update failed
 		// Log the orphaned task for manual recovery
-		fmt.Printf("CRITICAL: Task created on blockchain (contract_task_id=%d, tx=%s) but failed to update database (task_id=%s): %v\n", 
+		fmt.Printf("CRITICAL: Task created on blockchain (contract_task_id=%d, tx=%s) but failed to update database (task_id=%s): %v\n",
 			contractTaskID, txHash, taskID, err)
 		return response.Error(500, fmt.Sprintf("Task created on blockchain but database update failed. Contract Task ID: %d, TX: %s. Please contact support.", contractTaskID, txHash))
 	}
 
-	return response.Success(CreateTaskResponse{
+	resp := CreateTaskResponse{
 		TaskID:         taskID,
 		ContractTaskID: int64(contractTaskID),
+		ChainID:        client.ChainID.Int64(),
+		EscrowAddress:  client.EscrowAddress.Hex(),
 		TxHash:         txHash,
-		Status:         "bidding",
-	})
-}
-
-// callApproveEscrow calls the approve-escrow Lambda function in did-login-lambda
-func callApproveEscrow(authToken, tokenAddress, spenderAddress string) error {
-	// Get DID Login API URL from environment
-	didLoginAPIURL := os.Getenv("DID_LOGIN_API_URL")
-	if didLoginAPIURL == "" {
-		return fmt.Errorf("DID_LOGIN_API_URL not configured")
-	}
-
-	// Prepare request body
-	reqBody := map[string]string{
-		"token_address":   tokenAddress,
-		"spender_address": spenderAddress,
-	}
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
+		ExplorerURL:    client.Chain.TxURL(txHash),
+		Status:         openStatus,
 	}
 
-	// Create HTTP request
-	url := didLoginAPIURL + "/api/approve-escrow"
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", authToken)
-
-	// Send request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Read response
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
-	}
-
-	// Check status code
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("approve-escrow API returned status %d: %s", resp.StatusCode, string(body))
-	}
-
-	// Parse response
-	var result map[string]interface{}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return fmt.Errorf("failed to parse response: %w", err)
-	}
+	// Direct assignment skips bidding; if it fails the invitee can still bid.
+	// A task still blocked on prerequisites is never assigned.
+	if assignmentMode == models.AssignmentDirect && openStatus != models.TaskStatusBlocked {
+		executorDID := req.InvitedDIDs[0]
+		assignTxHash, err := client.SetExecutor(contractTaskID, executorAddress)
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Task created (task_id=%s) but assigning the executor failed: %v. The invitee can bid instead.", taskID, err))
+		}
 
-	if success, ok := result["success"].(bool); !ok || !success {
-		errMsg := "unknown error"
-		if msg, ok := result["error"].(string); ok {
-			errMsg = msg
+		_, err = pool.Exec(ctx, `
+			UPDATE tasks
+			SET executor_did = $1, executor_address = $2, status = 'accepted',
+			    accepted_criteria = $3, updated_at = NOW()
+			WHERE task_id = $4
+		`, executorDID, executorAddress, models.AcceptanceCriteriaList(req.AcceptanceCriteria), taskID)
+		if err != nil {
+			fmt.Printf("CRITICAL: Executor set on blockchain (task_id=%s, tx=%s) but failed to update database: %v\n",
+				taskID, assignTxHash, err)
+			return response.Error(500, fmt.Sprintf("Executor set on blockchain but database update failed. TX: %s. Please contact support.", assignTxHash))
 		}
-		return fmt.Errorf("approval failed: %s", errMsg)
+
+		resp.Status = models.TaskStatusAccepted
+		resp.ExecutorDID = executorDID
+		resp.AssignTxHash = assignTxHash
 	}
 
-	return nil
+	return response.Success(resp)
 }
 
 func main() {
-	lambda.Start(handler)
+	lambda.Start(idempotency.Wrap(handler))
 }
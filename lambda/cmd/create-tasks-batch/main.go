@@ -0,0 +1,355 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/blockchain"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/idempotency"
+	"github.com/x-zero/xz-wallet/pkg/models"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+type CreateTasksBatchRequest struct {
+	ProjectID  string                `json:"project_id"`
+	Visibility string                `json:"visibility"` // project or global, for every task
+	Tasks      []BatchTaskInput      `json:"tasks"`
+	Permit     *models.PermitRequest `json:"permit,omitempty"` // Covers the batch total
+}
+
+// BatchTaskInput is one task of a batch. Fields left empty are taken from
+// the template, if one is given.
+type BatchTaskInput struct {
+	TemplateID         string   `json:"template_id,omitempty"`
+	TaskName           string   `json:"task_name"`
+	TaskDescription    string   `json:"task_description,omitempty"`
+	AcceptanceCriteria string   `json:"acceptance_criteria,omitempty"`
+	RewardAmount       string   `json:"reward_amount"`
+	ProfessionTags     []string `json:"profession_tags,omitempty"`
+	MilestoneBps       []uint16 `json:"milestone_bps,omitempty"`
+}
+
+type CreateTasksBatchResponse struct {
+	Tasks         []BatchTaskResult `json:"tasks"`
+	TotalReward   string            `json:"total_reward"`
+	ChainID       int64             `json:"chain_id"`
+	EscrowAddress string            `json:"escrow_address"`
+	TxHash        string            `json:"tx_hash"`
+	ExplorerURL   string            `json:"explorer_url,omitempty"`
+}
+
+type BatchTaskResult struct {
+	TaskID         string `json:"task_id"`
+	ContractTaskID int64  `json:"contract_task_id"`
+	TaskName       string `json:"task_name"`
+	RewardAmount   string `json:"reward_amount"`
+	Status         string `json:"status"`
+}
+
+// batchTask is a task resolved against its template and ready to insert
+type batchTask struct {
+	TemplateID         *string
+	TaskName           string
+	TaskDescription    string
+	AcceptanceCriteria string
+	RewardAmount       string
+	RewardWei          *big.Int
+	ProfessionTags     []string
+	MilestoneBps       []uint16
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	var req CreateTasksBatchRequest
+	if err := json.Unmarshal([]byte(request.Body), &req); err != nil {
+		return response.Error(400, "Invalid request body")
+	}
+
+	if req.ProjectID == "" {
+		return response.Error(400, "Missing project_id")
+	}
+	if req.Visibility != models.VisibilityProject && req.Visibility != models.VisibilityGlobal {
+		return response.Error(400, "visibility must be project or global; create invite-only tasks with POST /tasks")
+	}
+	if len(req.Tasks) == 0 || len(req.Tasks) > blockchain.MaxBatchSize {
+		return response.Error(400, fmt.Sprintf("tasks needs 1 to %d entries", blockchain.MaxBatchSize))
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Database error: %v", err))
+	}
+	client, err := blockchain.InitClient()
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Blockchain error: %v", err))
+	}
+	if !client.IsEscrowV2() {
+		return response.Error(400, "Task escrow cannot create tasks in batch; use POST /tasks")
+	}
+
+	pool := db.GetPool()
+
+	// Load the batch's templates from the project
+	var templateIDs []string
+	for _, input := range req.Tasks {
+		if input.TemplateID != "" {
+			templateIDs = append(templateIDs, input.TemplateID)
+		}
+	}
+	templates := make(map[string]models.TaskTemplate)
+	if len(templateIDs) > 0 {
+		rows, err := pool.Query(ctx, `
+			SELECT template_id, task_description, acceptance_criteria, profession_tags, milestone_bps
+			FROM task_templates
+			WHERE template_id = ANY($1::uuid[]) AND project_id = $2
+		`, templateIDs, req.ProjectID)
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Failed to load templates: %v", err))
+		}
+		for rows.Next() {
+			var template models.TaskTemplate
+			if err := rows.Scan(&template.TemplateID, &template.TaskDescription, &template.AcceptanceCriteria,
+				&template.ProfessionTags, &template.MilestoneBps); err != nil {
+				rows.Close()
+				return response.RetryableError(500, fmt.Sprintf("Failed to load templates: %v", err))
+			}
+			templates[template.TemplateID] = template
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Failed to load templates: %v", err))
+		}
+	}
+
+	// Resolve and validate every task before anything is locked
+	tasks := make([]batchTask, len(req.Tasks))
+	total := new(big.Int)
+	for i, input := range req.Tasks {
+		task := batchTask{
+			TaskName:           input.TaskName,
+			TaskDescription:    input.TaskDescription,
+			AcceptanceCriteria: input.AcceptanceCriteria,
+			RewardAmount:       input.RewardAmount,
+			ProfessionTags:     input.ProfessionTags,
+			MilestoneBps:       input.MilestoneBps,
+		}
+		if input.TemplateID != "" {
+			template, ok := templates[input.TemplateID]
+			if !ok {
+				return response.Error(404, fmt.Sprintf("tasks[%d]: template not found in project: %s", i, input.TemplateID))
+			}
+			task.TemplateID = &template.TemplateID
+			if task.TaskDescription == "" {
+				task.TaskDescription = template.TaskDescription
+			}
+			if task.AcceptanceCriteria == "" {
+				task.AcceptanceCriteria = template.AcceptanceCriteria
+			}
+			if task.ProfessionTags == nil {
+				task.ProfessionTags = template.ProfessionTags
+			}
+			if task.MilestoneBps == nil && template.MilestoneBps != nil {
+				task.MilestoneBps = models.MilestoneSchedule(template.MilestoneBps)
+			}
+		}
+
+		if task.TaskName == "" || task.RewardAmount == "" {
+			return response.Error(400, fmt.Sprintf("tasks[%d]: task_name and reward_amount are required", i))
+		}
+		task.RewardWei, err = blockchain.ToWei(task.RewardAmount)
+		if err != nil || task.RewardWei.Sign() <= 0 {
+			return response.Error(400, fmt.Sprintf("tasks[%d]: invalid reward_amount", i))
+		}
+		if task.MilestoneBps == nil {
+			task.MilestoneBps = models.DefaultMilestoneBps
+		} else {
+			if len(task.MilestoneBps) != len(models.Milestones) {
+				return response.Error(400, fmt.Sprintf("tasks[%d]: milestone_bps needs %d entries (design, implementation, final)", i, len(models.Milestones)))
+			}
+			if err := blockchain.ValidateMilestoneBps(task.MilestoneBps); err != nil {
+				return response.Error(400, fmt.Sprintf("tasks[%d]: invalid milestone_bps: %v", i, err))
+			}
+		}
+		if task.ProfessionTags == nil {
+			task.ProfessionTags = []string{}
+		}
+
+		tasks[i] = task
+		total.Add(total, task.RewardWei)
+	}
+	totalReward := blockchain.FromWei(total, 8)
+
+	// Get user's eth_address
+	var ethAddress string
+	err = pool.QueryRow(ctx, "SELECT eth_address FROM users WHERE did = $1", claims.DID).Scan(&ethAddress)
+	if err != nil {
+		return response.Error(404, "User not found")
+	}
+
+	// Check balance and allowance once for the batch total
+	var permit *blockchain.PermitSignature
+	if req.Permit != nil {
+		permit, err = blockchain.ParsePermit(req.Permit.Deadline, req.Permit.V, req.Permit.R, req.Permit.S)
+		if err != nil {
+			return response.Error(400, fmt.Sprintf("Invalid permit: %v", err))
+		}
+		err = client.CheckBalance(ctx, common.HexToAddress(ethAddress), total)
+	} else {
+		approver, approverErr := blockchain.NewApproverFromEnv(client)
+		if approverErr != nil {
+			return response.RetryableError(500, fmt.Sprintf("Approver error: %v", approverErr))
+		}
+		err = client.EnsureAllowance(ctx, approver, blockchain.ApprovalRequest{
+			Owner:     common.HexToAddress(ethAddress),
+			Amount:    total,
+			AuthToken: authHeader,
+		})
+	}
+	switch {
+	case errors.Is(err, blockchain.ErrInsufficientBalance):
+		userBalance, balErr := client.GetBalance(ethAddress)
+		if balErr != nil {
+			return response.Error(400, fmt.Sprintf("Insufficient XZT balance. Required: %s XZT", totalReward))
+		}
+		return response.Error(400, fmt.Sprintf("Insufficient XZT balance. Required: %s XZT, Available: %s XZT",
+			totalReward, blockchain.FromWei(userBalance, 2)))
+	case errors.Is(err, blockchain.ErrApprovalRequired):
+		return response.Error(400, "Escrow allowance insufficient. Please sign a permit for the batch total.")
+	case err != nil:
+		return response.RetryableError(500, fmt.Sprintf("Failed to approve escrow contract: %v. Please try again.", err))
+	}
+
+	// Insert every task as pending first, so a failed batch can be marked cancelled
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return response.RetryableError(500, "Failed to start transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	taskIDs := make([]string, len(tasks))
+	for i, task := range tasks {
+		err = tx.QueryRow(ctx, `
+			INSERT INTO tasks (
+				contract_task_id, project_id, creator_did, task_name,
+				task_description, acceptance_criteria, reward_amount,
+				visibility, status, profession_tags, chain_id, escrow_address, milestone_bps,
+				template_id
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			RETURNING task_id
+		`, -1, req.ProjectID, claims.DID, task.TaskName,
+			task.TaskDescription, task.AcceptanceCriteria, task.RewardAmount,
+			req.Visibility, "pending", task.ProfessionTags,
+			client.ChainID.Int64(), client.EscrowAddress.Hex(), models.MilestoneColumn(task.MilestoneBps),
+			task.TemplateID).Scan(&taskIDs[i])
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Failed to save tasks[%d]: %v", i, err))
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return response.Error(500, "Failed to commit transaction")
+	}
+	fmt.Printf("Batch of %d tasks saved to database, now creating on blockchain...\n", len(taskIDs))
+
+	// Create all tasks on blockchain in one transaction
+	amounts := make([]*big.Int, len(tasks))
+	schedules := make([][]uint16, len(tasks))
+	for i, task := range tasks {
+		amounts[i] = task.RewardWei
+		schedules[i] = task.MilestoneBps
+	}
+	contractTaskIDs, txHash, err := client.CreateTasks(ethAddress, amounts, schedules, permit)
+	if err != nil {
+		// Blockchain failed, mark the whole batch as cancelled in database
+		_, updateErr := pool.Exec(ctx, `
+			UPDATE tasks
+			SET status = 'cancelled',
+			    updated_at = NOW(),
+			    task_description = task_description || E'\n\n[系统消息] 区块链创建失败: ' || $1
+			WHERE task_id = ANY($2::uuid[])
+		`, err.Error(), taskIDs)
+		if updateErr != nil {
+			fmt.Printf("Failed to update task status: %v\n", updateErr)
+		}
+		return response.Error(500, fmt.Sprintf("Failed to create tasks on blockchain: %v", err))
+	}
+
+	// Update tasks with their contract_task_id and set status to bidding
+	tx, err = pool.Begin(ctx)
+	if err == nil {
+		defer tx.Rollback(ctx)
+		for i, taskID := range taskIDs {
+			_, err = tx.Exec(ctx, `
+				UPDATE tasks
+				SET contract_task_id = $1,
+				    status = 'bidding',
+				    updated_at = NOW()
+				WHERE task_id = $2
+			`, contractTaskIDs[i], taskID)
+			if err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = tx.Commit(ctx)
+		}
+	}
+	if err != nil {
+		// This is bad - blockchain succeeded but database update failed
+		// Log the orphaned tasks for manual recovery
+		fmt.Printf("CRITICAL: Batch created on blockchain (contract_task_ids=%v, tx=%s) but failed to update database (task_ids=%v): %v\n",
+			contractTaskIDs, txHash, taskIDs, err)
+		return response.Error(500, fmt.Sprintf("Tasks created on blockchain but database update failed. TX: %s. Please contact support.", txHash))
+	}
+
+	results := make([]BatchTaskResult, len(tasks))
+	for i, task := range tasks {
+		results[i] = BatchTaskResult{
+			TaskID:         taskIDs[i],
+			ContractTaskID: int64(contractTaskIDs[i]),
+			TaskName:       task.TaskName,
+			RewardAmount:   task.RewardAmount,
+			Status:         "bidding",
+		}
+	}
+
+	return response.Success(CreateTasksBatchResponse{
+		Tasks:         results,
+		TotalReward:   totalReward,
+		ChainID:       client.ChainID.Int64(),
+		EscrowAddress: client.EscrowAddress.Hex(),
+		TxHash:        txHash,
+		ExplorerURL:   client.Chain.TxURL(txHash),
+	})
+}
+
+func main() {
+	lambda.Start(idempotency.Wrap(handler))
+}
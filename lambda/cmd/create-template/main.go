@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/jackc/pgx/v5"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/blockchain"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/idempotency"
+	"github.com/x-zero/xz-wallet/pkg/models"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+type CreateTemplateRequest struct {
+	TemplateName       string   `json:"template_name"`
+	TaskDescription    string   `json:"task_description"`
+	AcceptanceCriteria string   `json:"acceptance_criteria"`
+	ProfessionTags     []string `json:"profession_tags"`
+	MilestoneBps       []uint16 `json:"milestone_bps,omitempty"` // design/implementation/final shares, default 3000/5000/2000
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	projectID := request.PathParameters["id"]
+	if projectID == "" {
+		return response.Error(400, "Missing project ID")
+	}
+
+	var req CreateTemplateRequest
+	if err := json.Unmarshal([]byte(request.Body), &req); err != nil {
+		return response.Error(400, "Invalid request body")
+	}
+
+	req.TemplateName = strings.TrimSpace(req.TemplateName)
+	if req.TemplateName == "" {
+		return response.Error(400, "Missing template_name")
+	}
+	var milestoneBps []int32
+	if req.MilestoneBps != nil {
+		if len(req.MilestoneBps) != len(models.Milestones) {
+			return response.Error(400, fmt.Sprintf("milestone_bps needs %d entries (design, implementation, final)", len(models.Milestones)))
+		}
+		if err := blockchain.ValidateMilestoneBps(req.MilestoneBps); err != nil {
+			return response.Error(400, fmt.Sprintf("Invalid milestone_bps: %v", err))
+		}
+		for _, share := range req.MilestoneBps {
+			milestoneBps = append(milestoneBps, int32(share))
+		}
+	}
+	if req.ProfessionTags == nil {
+		req.ProfessionTags = []string{}
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	template := models.TaskTemplate{
+		ProjectID:          projectID,
+		CreatorDID:         claims.DID,
+		TemplateName:       req.TemplateName,
+		TaskDescription:    req.TaskDescription,
+		AcceptanceCriteria: req.AcceptanceCriteria,
+		ProfessionTags:     req.ProfessionTags,
+		MilestoneBps:       milestoneBps,
+	}
+	err = pool.QueryRow(ctx, `
+		INSERT INTO task_templates (
+			project_id, creator_did, template_name, task_description,
+			acceptance_criteria, profession_tags, milestone_bps
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (project_id, template_name) DO NOTHING
+		RETURNING template_id, created_at, updated_at
+	`, projectID, claims.DID, req.TemplateName, req.TaskDescription,
+		req.AcceptanceCriteria, req.ProfessionTags, milestoneBps).Scan(&template.TemplateID, &template.CreatedAt, &template.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return response.Error(409, fmt.Sprintf("Project already has a template named %q", req.TemplateName))
+	}
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Failed to save template: %v", err))
+	}
+
+	return response.Success(template)
+}
+
+func main() {
+	lambda.Start(idempotency.Wrap(handler))
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/jackc/pgx/v5"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	projectID := request.PathParameters["id"]
+	templateID := request.PathParameters["template_id"]
+	if projectID == "" || templateID == "" {
+		return response.Error(400, "Missing project or template ID")
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	var creatorDID string
+	err = pool.QueryRow(ctx, `
+		SELECT creator_did FROM task_templates WHERE template_id = $1 AND project_id = $2
+	`, templateID, projectID).Scan(&creatorDID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return response.Error(404, "Template not found")
+	}
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to load template: %v", err))
+	}
+
+	if creatorDID != claims.DID {
+		return response.Error(403, "Only the template creator can delete it")
+	}
+
+	// Tasks already created from the template keep their copied details
+	_, err = pool.Exec(ctx, `DELETE FROM task_templates WHERE template_id = $1`, templateID)
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to delete template: %v", err))
+	}
+
+	return response.Success(map[string]interface{}{
+		"template_id": templateID,
+		"deleted":     true,
+	})
+}
+
+func main() {
+	lambda.Start(handler)
+}
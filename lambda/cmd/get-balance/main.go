@@ -7,6 +7,7 @@ import (
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/x-zero/xz-wallet/pkg/auth"
 	"github.com/x-zero/xz-wallet/pkg/blockchain"
 	"github.com/x-zero/xz-wallet/pkg/db"
@@ -14,10 +15,32 @@ import (
 )
 
 type BalanceResponse struct {
-	DID        string `json:"did"`
-	EthAddress string `json:"eth_address"`
-	XZTBalance string `json:"xzt_balance"`
-	Username   string `json:"username"`
+	DID             string       `json:"did"`
+	EthAddress      string       `json:"eth_address"`
+	PayoutAddress   *string      `json:"payout_address,omitempty"` // Verified wallet that receives task payouts
+	XZTBalance      string       `json:"xzt_balance"`
+	Username        string       `json:"username"`
+	EscrowedBalance string       `json:"escrowed_balance"` // Locked in open tasks the user created
+	PendingBalance  string       `json:"pending_balance"`  // Still owed to the user as executor
+	EscrowAllowance string       `json:"escrow_allowance"`
+	ETHBalance      string       `json:"eth_balance"`
+	EscrowedTasks   []TaskAmount `json:"escrowed_tasks"`
+	PendingTasks    []TaskAmount `json:"pending_tasks"`
+}
+
+type TaskAmount struct {
+	TaskID         string `json:"task_id"`
+	ContractTaskID int64  `json:"contract_task_id"`
+	TaskName       string `json:"task_name"`
+	Remaining      string `json:"remaining"`
+}
+
+type openTask struct {
+	TaskID         string
+	ContractTaskID int64
+	TaskName       string
+	IsCreator      bool
+	Client         *blockchain.BlockchainClient
 }
 
 func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
@@ -27,8 +50,8 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 			StatusCode: 200,
 			Headers: map[string]string{
 				"Access-Control-Allow-Origin":  "*",
-				"Access-Control-Allow-Headers": "Content-Type,Authorization",
-				"Access-Control-Allow-Methods": "GET,POST,PUT,DELETE,OPTIONS",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
 			},
 			Body: "",
 		}, nil
@@ -62,32 +85,113 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	// Get user info from database
 	pool := db.GetPool()
 	var ethAddress, username string
+	var payoutAddress *string
 	err = pool.QueryRow(ctx, 
-		"SELECT eth_address, username FROM users WHERE did = $1",
+		"SELECT eth_address, username, payout_address FROM users WHERE did = $1",
 		claims.DID,
-	).Scan(&ethAddress, &username)
+	).Scan(&ethAddress, &username, &payoutAddress)
 	if err != nil {
 		return response.Error(404, "User not found")
 	}
 
-	// Get balance from blockchain
-	balance, err := client.GetBalance(ethAddress)
+	// Open escrow tasks the user created or executes
+	rows, err := pool.Query(ctx, `
+		SELECT task_id, contract_task_id, task_name, creator_did = $1, chain_id, escrow_address
+		FROM tasks
+		WHERE (creator_did = $1 OR executor_did = $1)
+		  AND contract_task_id >= 0
+		  AND status NOT IN ('completed', 'cancelled')
+	`, claims.DID)
 	if err != nil {
-		return response.Error(500, fmt.Sprintf("Failed to get balance: %v", err))
+		return response.Error(500, fmt.Sprintf("Query failed: %v", err))
+	}
+	defer rows.Close()
+
+	// Group tasks by the escrow that holds them; the default escrow always gets a group
+	type escrowGroup struct {
+		createdIDs, executingIDs []uint64
+		balance                  *blockchain.WalletBalance
+	}
+	groups := map[*blockchain.BlockchainClient]*escrowGroup{client: {}}
+
+	var tasks []openTask
+	for rows.Next() {
+		var t openTask
+		var chainID *int64
+		var escrowAddress *string
+		if err := rows.Scan(&t.TaskID, &t.ContractTaskID, &t.TaskName, &t.IsCreator, &chainID, &escrowAddress); err != nil {
+			return response.Error(500, fmt.Sprintf("Scan failed: %v", err))
+		}
+		t.Client, err = blockchain.ClientForTask(chainID, escrowAddress)
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Blockchain error: %v", err))
+		}
+		g, ok := groups[t.Client]
+		if !ok {
+			g = &escrowGroup{}
+			groups[t.Client] = g
+		}
+		if t.IsCreator {
+			g.createdIDs = append(g.createdIDs, uint64(t.ContractTaskID))
+		} else {
+			g.executingIDs = append(g.executingIDs, uint64(t.ContractTaskID))
+		}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return response.Error(500, fmt.Sprintf("Query failed: %v", err))
+	}
+
+	// Read everything in one multicall per escrow
+	owner := common.HexToAddress(ethAddress)
+	escrowed, pending := new(big.Int), new(big.Int)
+	for c, g := range groups {
+		g.balance, err = c.GetWalletBalance(ctx, owner, g.createdIDs, g.executingIDs)
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Failed to get balance: %v", err))
+		}
+		escrowed.Add(escrowed, g.balance.Escrowed)
+		pending.Add(pending, g.balance.Pending)
+	}
+	wb := groups[client].balance
+
+	resp := BalanceResponse{
+		DID:             claims.DID,
+		EthAddress:      ethAddress,
+		PayoutAddress:   payoutAddress,
+		XZTBalance:      blockchain.FromWei(wb.XZT, 8),
+		Username:        username,
+		EscrowedBalance: blockchain.FromWei(escrowed, 8),
+		PendingBalance:  blockchain.FromWei(pending, 8),
+		EscrowAllowance: blockchain.FromWei(wb.Allowance, 8),
+		ETHBalance:      blockchain.FromWei(wb.ETH, 8),
+		EscrowedTasks:   []TaskAmount{},
+		PendingTasks:    []TaskAmount{},
+	}
+	for _, t := range tasks {
+		balance := groups[t.Client].balance
+		amounts := balance.Executing
+		if t.IsCreator {
+			amounts = balance.Created
+		}
+		remaining, ok := amounts[uint64(t.ContractTaskID)]
+		if !ok || remaining.Sign() == 0 {
+			continue
+		}
+		amount := TaskAmount{
+			TaskID:         t.TaskID,
+			ContractTaskID: t.ContractTaskID,
+			TaskName:       t.TaskName,
+			Remaining:      blockchain.FromWei(remaining, 8),
+		}
+		if t.IsCreator {
+			resp.EscrowedTasks = append(resp.EscrowedTasks, amount)
+		} else {
+			resp.PendingTasks = append(resp.PendingTasks, amount)
+		}
 	}
 
-	// Convert to decimal (18 decimals)
-	balanceFloat := new(big.Float).SetInt(balance)
-	divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
-	balanceFloat.Quo(balanceFloat, divisor)
-	balanceStr := balanceFloat.Text('f', 8)
-
-	return response.Success(BalanceResponse{
-		DID:        claims.DID,
-		EthAddress: ethAddress,
-		XZTBalance: balanceStr,
-		Username:   username,
-	})
+	return response.Success(resp)
 }
 
 func main() {
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/blockchain"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/models"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+// How long a signed authorization stays valid on chain
+const signatureTTL = time.Hour
+
+// AuthorizationResponse is what the creator (and executor) sign for a v2 escrow
+type AuthorizationResponse struct {
+	Action         string             `json:"action"`
+	ChainID        int64              `json:"chain_id"`
+	EscrowAddress  string             `json:"escrow_address"`
+	ContractTaskID int64              `json:"contract_task_id"`
+	Amount         string             `json:"amount"`
+	AmountWei      string             `json:"amount_wei"`
+	Executor       string             `json:"executor"` // On-chain executor the signature pays
+	Nonce          string             `json:"nonce"`
+	Deadline       int64              `json:"deadline"`
+	Digest         string             `json:"digest"`
+	Signers        []string           `json:"signers"`
+	TypedData      apitypes.TypedData `json:"typed_data"`
+}
+
+// submittedStatus is the task status in which a milestone can be approved
+var submittedStatus = map[string]string{
+	"design":         models.TaskStatusDesignSubmitted,
+	"implementation": models.TaskStatusImplementationSubmitted,
+	"final":          models.TaskStatusFinalSubmitted,
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	taskID := request.PathParameters["id"]
+	if taskID == "" {
+		return response.Error(400, "Missing task ID")
+	}
+
+	params := request.QueryStringParameters
+	action := params["action"]
+	switch action {
+	case "release_milestone", "cancel", "resign", "top_up":
+	default:
+		return response.Error(400, "action must be release_milestone, cancel, resign or top_up")
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	var task struct {
+		ContractTaskID int64
+		CreatorDID     string
+		ExecutorDID    *string
+		Status         string
+		ChainID        *int64
+		EscrowAddress  *string
+	}
+	err = pool.QueryRow(ctx, `
+		SELECT contract_task_id, creator_did, executor_did, status, chain_id, escrow_address
+		FROM tasks WHERE task_id = $1
+	`, taskID).Scan(&task.ContractTaskID, &task.CreatorDID, &task.ExecutorDID, &task.Status,
+		&task.ChainID, &task.EscrowAddress)
+	if err != nil {
+		return response.Error(404, "Task not found")
+	}
+
+	isCreator := task.CreatorDID == claims.DID
+	isExecutor := task.ExecutorDID != nil && *task.ExecutorDID == claims.DID
+	if !isCreator && !isExecutor {
+		return response.Error(403, "Only task creator or executor can sign escrow actions")
+	}
+
+	client, err := blockchain.ClientForTask(task.ChainID, task.EscrowAddress)
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Blockchain error: %v", err))
+	}
+	if !client.IsEscrowV2() {
+		return response.Error(400, "Task escrow does not use signed authorizations")
+	}
+
+	// Build the action exactly as approve-work and cancel-task will
+	deadline := big.NewInt(time.Now().Add(signatureTTL).Unix())
+	var authorization *blockchain.EscrowAuthorization
+	signers := []string{"creator"}
+
+	switch action {
+	case "release_milestone":
+		milestone := params["milestone"]
+		status, ok := submittedStatus[milestone]
+		if !ok {
+			return response.Error(400, "Invalid milestone")
+		}
+		if task.Status != status {
+			return response.Error(400, fmt.Sprintf("Invalid task status for %s approval", milestone))
+		}
+		authorization, err = client.NewReleaseAuthorization(ctx, uint64(task.ContractTaskID), models.MilestoneIndex(milestone), deadline)
+	case "cancel":
+		if task.Status == models.TaskStatusCompleted || task.Status == models.TaskStatusCancelled {
+			return response.Error(400, fmt.Sprintf("Cannot cancel task in status: %s", task.Status))
+		}
+		executorAmount := big.NewInt(0)
+		if value := params["executor_amount"]; value != "" {
+			executorAmount, err = blockchain.ToWei(value)
+			if err != nil {
+				return response.Error(400, fmt.Sprintf("Invalid executor_amount: %v", err))
+			}
+		}
+		if executorAmount.Sign() > 0 {
+			if task.ExecutorDID == nil {
+				return response.Error(400, "Task has no executor to pay")
+			}
+			signers = append(signers, "executor")
+		}
+		authorization, err = client.NewCancelAuthorization(ctx, uint64(task.ContractTaskID), executorAmount, deadline)
+	case "resign":
+		// A direct assignee declining the invitation (see respond-invitation)
+		if !isExecutor {
+			return response.Error(403, "Only the executor can resign")
+		}
+		if task.Status != models.TaskStatusAccepted {
+			return response.Error(400, fmt.Sprintf("Cannot resign from task in status: %s", task.Status))
+		}
+		signers = []string{"executor"}
+		authorization, err = client.NewResignAuthorization(ctx, uint64(task.ContractTaskID), deadline)
+	case "top_up":
+		// Raising the reward in change-reward without a permit
+		if !isCreator {
+			return response.Error(403, "Only the creator can top up")
+		}
+		if task.Status != models.TaskStatusBidding && task.Status != models.TaskStatusBlocked {
+			return response.Error(400, "Reward can only change while bidding")
+		}
+		newWei, parseErr := blockchain.ToWei(params["reward_amount"])
+		if parseErr != nil {
+			return response.Error(400, fmt.Sprintf("Invalid reward_amount: %v", parseErr))
+		}
+		// change-reward tops up the difference to the amount the escrow holds
+		_, _, total, _, _, readErr := client.GetTask(uint64(task.ContractTaskID))
+		if readErr != nil {
+			return response.Error(500, fmt.Sprintf("Failed to read task from escrow: %v", readErr))
+		}
+		amount := new(big.Int).Sub(newWei, total)
+		if amount.Sign() <= 0 {
+			return response.Error(400, "reward_amount must be above the current reward")
+		}
+		authorization, err = client.NewTopUpAuthorization(ctx, uint64(task.ContractTaskID), amount, deadline)
+	}
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to build authorization: %v", err))
+	}
+
+	return response.Success(AuthorizationResponse{
+		Action:         action,
+		ChainID:        client.ChainID.Int64(),
+		EscrowAddress:  client.EscrowAddress.Hex(),
+		ContractTaskID: task.ContractTaskID,
+		Amount:         blockchain.FromWei(authorization.Amount, 8),
+		AmountWei:      authorization.Amount.String(),
+		Executor:       authorization.Executor.Hex(),
+		Nonce:          authorization.Nonce.String(),
+		Deadline:       deadline.Int64(),
+		Digest:         client.Digest(authorization).Hex(),
+		Signers:        signers,
+		TypedData:      client.TypedData(authorization),
+	})
+}
+
+func main() {
+	lambda.Start(handler)
+}
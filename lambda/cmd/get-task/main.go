@@ -3,20 +3,55 @@ package main
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/x-zero/xz-wallet/pkg/db"
 	"github.com/x-zero/xz-wallet/pkg/models"
 	"github.com/x-zero/xz-wallet/pkg/response"
 )
 
 type GetTaskResponse struct {
-	Task        models.Task              `json:"task"`
-	Creator     UserInfo                 `json:"creator"`
-	Executor    *UserInfo                `json:"executor,omitempty"`
-	Submissions []models.TaskSubmission  `json:"submissions"`
-	Bids        []BidInfo                `json:"bids,omitempty"`
+	Task         models.Task             `json:"task"`
+	Creator      UserInfo                `json:"creator"`
+	Executor     *UserInfo               `json:"executor,omitempty"`
+	Submissions  []models.TaskSubmission `json:"submissions"`
+	Milestones   []MilestoneSubmissions  `json:"milestones"` // Submissions grouped by milestone, oldest revision first
+	Bids         []BidInfo               `json:"bids,omitempty"`
+	Invitations  []models.TaskInvitation `json:"invitations,omitempty"`
+	Team         []TeamMemberInfo        `json:"team,omitempty"`
+	Edits        []models.TaskEdit       `json:"edits,omitempty"`
+	Subtasks     []TaskLink              `json:"subtasks,omitempty"`
+	Rollup       *Rollup                 `json:"rollup,omitempty"`       // Set when the task has subtasks
+	Dependencies []TaskLink              `json:"dependencies,omitempty"` // Tasks this one waits on
+	Dependents   []TaskLink              `json:"dependents,omitempty"`   // Tasks waiting on this one
+}
+
+// MilestoneSubmissions lists every revision submitted for a milestone
+type MilestoneSubmissions struct {
+	Milestone string                  `json:"milestone"`
+	Revisions []models.TaskSubmission `json:"revisions"`
+}
+
+// TaskLink summarizes a related task
+type TaskLink struct {
+	TaskID       string `json:"task_id"`
+	TaskName     string `json:"task_name"`
+	Status       string `json:"status"`
+	RewardAmount string `json:"reward_amount"`
+	PaidAmount   string `json:"paid_amount"`
+}
+
+// Rollup totals a task and all its subtasks, nested ones included
+type Rollup struct {
+	SubtasksTotal     int    `json:"subtasks_total"`
+	SubtasksCompleted int    `json:"subtasks_completed"`
+	SubtasksCancelled int    `json:"subtasks_cancelled"`
+	ProgressPercent   int    `json:"progress_percent"` // Completed share of subtasks not cancelled
+	EscrowTotal       string `json:"escrow_total"`     // Rewards locked, or paid out for cancelled tasks
+	PaidTotal         string `json:"paid_total"`
 }
 
 type UserInfo struct {
@@ -26,6 +61,11 @@ type UserInfo struct {
 	CreditScore int    `json:"credit_score"`
 }
 
+type TeamMemberInfo struct {
+	models.TaskTeamMember
+	Username string `json:"username"`
+}
+
 type BidInfo struct {
 	models.TaskBid
 	BidderUsername    string   `json:"bidder_username"`
@@ -34,6 +74,7 @@ type BidInfo struct {
 	BidderTasksCompleted int   `json:"bidder_tasks_completed"`
 	BidderProfessionTags []string `json:"bidder_profession_tags"`
 	BidderBio         *string  `json:"bidder_bio,omitempty"`
+	Revisions         int      `json:"revisions"` // Versions in task_bid_revisions
 }
 
 func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
@@ -42,8 +83,8 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 			StatusCode: 200,
 			Headers: map[string]string{
 				"Access-Control-Allow-Origin":  "*",
-				"Access-Control-Allow-Headers": "Content-Type,Authorization",
-				"Access-Control-Allow-Methods": "GET,POST,PUT,DELETE,OPTIONS",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
 			},
 		}, nil
 	}
@@ -62,16 +103,16 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	// Get task
 	var task models.Task
 	err := pool.QueryRow(ctx, `
-		SELECT task_id, contract_task_id, project_id, creator_did, executor_did,
+		SELECT task_id, contract_task_id, chain_id, escrow_address, project_id, creator_did, executor_did, executor_address,
 		       task_name, task_description, acceptance_criteria,
-		       reward_amount, paid_amount, visibility, status, profession_tags,
-		       created_at, updated_at, completed_at, cancelled_at
+		       reward_amount, paid_amount, visibility, assignment_mode, status, profession_tags, milestone_bps, template_id,
+		       parent_task_id, created_at, updated_at, completed_at, cancelled_at
 		FROM tasks WHERE task_id = $1
 	`, taskID).Scan(
-		&task.TaskID, &task.ContractTaskID, &task.ProjectID, &task.CreatorDID, &task.ExecutorDID,
+		&task.TaskID, &task.ContractTaskID, &task.ChainID, &task.EscrowAddress, &task.ProjectID, &task.CreatorDID, &task.ExecutorDID, &task.ExecutorAddress,
 		&task.TaskName, &task.TaskDescription, &task.AcceptanceCriteria,
-		&task.RewardAmount, &task.PaidAmount, &task.Visibility, &task.Status, &task.ProfessionTags,
-		&task.CreatedAt, &task.UpdatedAt, &task.CompletedAt, &task.CancelledAt,
+		&task.RewardAmount, &task.PaidAmount, &task.Visibility, &task.AssignmentMode, &task.Status, &task.ProfessionTags, &task.MilestoneBps, &task.TemplateID,
+		&task.ParentTaskID, &task.CreatedAt, &task.UpdatedAt, &task.CompletedAt, &task.CancelledAt,
 	)
 	if err != nil {
 		return response.Error(404, "Task not found")
@@ -100,7 +141,7 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 
 	// Get submissions
 	rows, err := pool.Query(ctx, `
-		SELECT submission_id, task_id, submission_type, content, file_urls,
+		SELECT submission_id, task_id, submission_type, revision, content, file_urls,
 		       status, rejection_reason, submitted_at, reviewed_at
 		FROM task_submissions WHERE task_id = $1 ORDER BY submitted_at DESC
 	`, taskID)
@@ -113,7 +154,7 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	for rows.Next() {
 		var sub models.TaskSubmission
 		err := rows.Scan(
-			&sub.SubmissionID, &sub.TaskID, &sub.SubmissionType, &sub.Content, &sub.FileURLs,
+			&sub.SubmissionID, &sub.TaskID, &sub.SubmissionType, &sub.Revision, &sub.Content, &sub.FileURLs,
 			&sub.Status, &sub.RejectionReason, &sub.SubmittedAt, &sub.ReviewedAt,
 		)
 		if err == nil {
@@ -121,12 +162,17 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		}
 	}
 
+	attachReviews(ctx, pool, taskID, submissions)
+
 	// Get bids (only for creator)
 	bids := []BidInfo{}
 	bidRows, err := pool.Query(ctx, `
 		SELECT tb.bid_id, tb.task_id, tb.bidder_did, tb.bid_message,
-		       tb.credit_score_snapshot, tb.status, tb.created_at, tb.updated_at,
-		       u.username, u.email, u.credit_score, u.tasks_completed, u.profession_tags, u.bio
+		       tb.credit_score_snapshot, tb.proposed_reward::text, tb.estimated_delivery_date,
+		       tb.proposed_milestone_bps, tb.attachment_urls, tb.proposed_team, tb.reward_snapshot::text,
+		       tb.status, tb.created_at, tb.updated_at,
+		       u.username, u.email, u.credit_score, u.tasks_completed, u.profession_tags, u.bio,
+		       (SELECT COUNT(*) FROM task_bid_revisions r WHERE r.bid_id = tb.bid_id)
 		FROM task_bids tb
 		JOIN users u ON tb.bidder_did = u.did
 		WHERE tb.task_id = $1
@@ -138,9 +184,12 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 			var bid BidInfo
 			err := bidRows.Scan(
 				&bid.BidID, &bid.TaskID, &bid.BidderDID, &bid.BidMessage,
-				&bid.CreditScoreSnapshot, &bid.Status, &bid.CreatedAt, &bid.UpdatedAt,
+				&bid.CreditScoreSnapshot, &bid.ProposedReward, &bid.EstimatedDeliveryDate,
+				&bid.ProposedMilestoneBps, &bid.AttachmentURLs, &bid.ProposedTeam, &bid.RewardSnapshot,
+				&bid.Status, &bid.CreatedAt, &bid.UpdatedAt,
 				&bid.BidderUsername, &bid.BidderEmail, &bid.BidderCreditScore, 
 				&bid.BidderTasksCompleted, &bid.BidderProfessionTags, &bid.BidderBio,
+				&bid.Revisions,
 			)
 			if err == nil {
 				bids = append(bids, bid)
@@ -148,15 +197,229 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		}
 	}
 
+	// Get invitations (invite-only tasks)
+	var invitations []models.TaskInvitation
+	if task.Visibility == models.VisibilityInvite {
+		inviteRows, err := pool.Query(ctx, `
+			SELECT invitation_id, task_id, invitee_did, status, created_at, responded_at
+			FROM task_invitations WHERE task_id = $1
+			ORDER BY created_at
+		`, taskID)
+		if err == nil {
+			defer inviteRows.Close()
+			for inviteRows.Next() {
+				var invitation models.TaskInvitation
+				err := inviteRows.Scan(
+					&invitation.InvitationID, &invitation.TaskID, &invitation.InviteeDID,
+					&invitation.Status, &invitation.CreatedAt, &invitation.RespondedAt,
+				)
+				if err == nil {
+					invitations = append(invitations, invitation)
+				}
+			}
+		}
+	}
+
+	// Get executor team with each member's share and what they were paid
+	var team []TeamMemberInfo
+	teamRows, err := pool.Query(ctx, `
+		SELECT m.task_id, m.member_did, m.payout_address, m.position, m.share_bps,
+		       m.paid_amount::text, m.created_at, u.username
+		FROM task_team_members m
+		JOIN users u ON m.member_did = u.did
+		WHERE m.task_id = $1
+		ORDER BY m.position
+	`, taskID)
+	if err == nil {
+		defer teamRows.Close()
+		for teamRows.Next() {
+			var member TeamMemberInfo
+			err := teamRows.Scan(
+				&member.TaskID, &member.MemberDID, &member.PayoutAddress, &member.Position, &member.ShareBps,
+				&member.PaidAmount, &member.CreatedAt, &member.Username,
+			)
+			if err == nil {
+				team = append(team, member)
+			}
+		}
+	}
+
+	// Get edit history, oldest first
+	var edits []models.TaskEdit
+	editRows, err := pool.Query(ctx, `
+		SELECT edit_id, task_id, editor_did, changes, tx_hash, created_at
+		FROM task_edits WHERE task_id = $1
+		ORDER BY created_at
+	`, taskID)
+	if err == nil {
+		defer editRows.Close()
+		for editRows.Next() {
+			var edit models.TaskEdit
+			err := editRows.Scan(&edit.EditID, &edit.TaskID, &edit.EditorDID, &edit.Changes, &edit.TxHash, &edit.CreatedAt)
+			if err == nil {
+				edits = append(edits, edit)
+			}
+		}
+	}
+
+	// Get subtasks, dependencies and dependents
+	subtasks := taskLinks(ctx, pool, `
+		SELECT task_id, task_name, status, reward_amount::text, paid_amount::text
+		FROM tasks WHERE parent_task_id = $1 ORDER BY created_at
+	`, taskID)
+	dependencies := taskLinks(ctx, pool, `
+		SELECT t.task_id, t.task_name, t.status, t.reward_amount::text, t.paid_amount::text
+		FROM task_dependencies d JOIN tasks t ON t.task_id = d.depends_on_task_id
+		WHERE d.task_id = $1 ORDER BY d.created_at
+	`, taskID)
+	dependents := taskLinks(ctx, pool, `
+		SELECT t.task_id, t.task_name, t.status, t.reward_amount::text, t.paid_amount::text
+		FROM task_dependencies d JOIN tasks t ON t.task_id = d.task_id
+		WHERE d.depends_on_task_id = $1 ORDER BY d.created_at
+	`, taskID)
+
+	// Roll up progress and escrow over the whole subtask tree
+	var rollup *Rollup
+	if len(subtasks) > 0 {
+		var r Rollup
+		err = pool.QueryRow(ctx, `
+			WITH RECURSIVE tree AS (
+				SELECT task_id, status, reward_amount, paid_amount FROM tasks WHERE task_id = $1
+				UNION
+				SELECT t.task_id, t.status, t.reward_amount, t.paid_amount
+				FROM tasks t JOIN tree ON t.parent_task_id = tree.task_id
+			)
+			SELECT COUNT(*) FILTER (WHERE task_id <> $1),
+			       COUNT(*) FILTER (WHERE task_id <> $1 AND status = 'completed'),
+			       COUNT(*) FILTER (WHERE task_id <> $1 AND status = 'cancelled'),
+			       SUM(CASE WHEN status = 'cancelled' THEN paid_amount ELSE reward_amount END)::text,
+			       SUM(paid_amount)::text
+			FROM tree
+		`, taskID).Scan(&r.SubtasksTotal, &r.SubtasksCompleted, &r.SubtasksCancelled, &r.EscrowTotal, &r.PaidTotal)
+		if err == nil {
+			if open := r.SubtasksTotal - r.SubtasksCancelled; open > 0 {
+				r.ProgressPercent = r.SubtasksCompleted * 100 / open
+			}
+			rollup = &r
+		}
+	}
+
 	return response.Success(GetTaskResponse{
-		Task:        task,
-		Creator:     creator,
-		Executor:    executor,
-		Submissions: submissions,
-		Bids:        bids,
+		Task:         task,
+		Creator:      creator,
+		Executor:     executor,
+		Submissions:  submissions,
+		Milestones:   groupByMilestone(submissions),
+		Bids:         bids,
+		Invitations:  invitations,
+		Team:         team,
+		Edits:        edits,
+		Subtasks:     subtasks,
+		Rollup:       rollup,
+		Dependencies: dependencies,
+		Dependents:   dependents,
 	})
 }
 
+// attachReviews fills in each submission's per-criterion review and its
+// changes against the previous revision of the same milestone
+func attachReviews(ctx context.Context, pool *pgxpool.Pool, taskID string, submissions []models.TaskSubmission) {
+	reviews := make(map[string][]models.CriterionReview)
+	rows, err := pool.Query(ctx, `
+		SELECT c.submission_id, c.criterion_index, c.criterion, c.result, c.comment
+		FROM submission_criteria c JOIN task_submissions s ON s.submission_id = c.submission_id
+		WHERE s.task_id = $1
+		ORDER BY c.submission_id, c.criterion_index
+	`, taskID)
+	if err == nil {
+		for rows.Next() {
+			var submissionID string
+			var review models.CriterionReview
+			if err := rows.Scan(&submissionID, &review.CriterionIndex, &review.Criterion, &review.Result, &review.Comment); err == nil {
+				reviews[submissionID] = append(reviews[submissionID], review)
+			}
+		}
+		rows.Close()
+	}
+
+	previous := make(map[string]*models.TaskSubmission)
+	for i := range submissions {
+		sub := &submissions[i]
+		sub.Review = reviews[sub.SubmissionID]
+		previous[fmt.Sprintf("%s/%d", sub.SubmissionType, sub.Revision)] = sub
+	}
+	for i := range submissions {
+		sub := &submissions[i]
+		prev, ok := previous[fmt.Sprintf("%s/%d", sub.SubmissionType, sub.Revision-1)]
+		if !ok {
+			continue
+		}
+		changes := &models.SubmissionChanges{
+			PreviousSubmissionID: prev.SubmissionID,
+			ContentChanged:       sub.Content != prev.Content,
+			FilesAdded:           missingFrom(sub.FileURLs, prev.FileURLs),
+			FilesRemoved:         missingFrom(prev.FileURLs, sub.FileURLs),
+		}
+		for _, review := range prev.Review {
+			if review.Result == models.CriterionFail {
+				changes.PreviouslyFailed = append(changes.PreviouslyFailed, review.CriterionIndex)
+			}
+		}
+		sub.Changes = changes
+	}
+}
+
+// missingFrom returns the entries of a that are not in b
+func missingFrom(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, entry := range b {
+		in[entry] = true
+	}
+	var missing []string
+	for _, entry := range a {
+		if !in[entry] {
+			missing = append(missing, entry)
+		}
+	}
+	return missing
+}
+
+// groupByMilestone groups submissions in schedule order, oldest revision first
+func groupByMilestone(submissions []models.TaskSubmission) []MilestoneSubmissions {
+	groups := []MilestoneSubmissions{}
+	for _, milestone := range models.Milestones {
+		var revisions []models.TaskSubmission
+		for _, sub := range submissions {
+			if sub.SubmissionType == milestone {
+				revisions = append(revisions, sub)
+			}
+		}
+		if len(revisions) > 0 {
+			sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision < revisions[j].Revision })
+			groups = append(groups, MilestoneSubmissions{Milestone: milestone, Revisions: revisions})
+		}
+	}
+	return groups
+}
+
+// taskLinks runs a query selecting task_id, task_name, status, reward_amount
+// and paid_amount; failures leave the list empty like the other sections
+func taskLinks(ctx context.Context, pool *pgxpool.Pool, query string, taskID string) []TaskLink {
+	var links []TaskLink
+	rows, err := pool.Query(ctx, query, taskID)
+	if err != nil {
+		return links
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var link TaskLink
+		if err := rows.Scan(&link.TaskID, &link.TaskName, &link.Status, &link.RewardAmount, &link.PaidAmount); err == nil {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
 func main() {
 	lambda.Start(handler)
 }
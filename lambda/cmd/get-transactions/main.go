@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/blockchain"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+type Transaction struct {
+	TxHash         string    `json:"tx_hash"`
+	BlockNumber    uint64    `json:"block_number"`
+	Timestamp      time.Time `json:"timestamp"`
+	Type           string    `json:"type"`
+	Direction      string    `json:"direction"`
+	Counterparty   string    `json:"counterparty"`
+	Amount         string    `json:"amount"`
+	ContractTaskID *int64    `json:"contract_task_id,omitempty"`
+	TaskID         *string   `json:"task_id,omitempty"`
+	TaskName       *string   `json:"task_name,omitempty"`
+}
+
+type TransactionsResponse struct {
+	Transactions []Transaction `json:"transactions"`
+	Total        int           `json:"total"`
+	Limit        int           `json:"limit"`
+	Offset       int           `json:"offset"`
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	// Parse query parameters
+	params := request.QueryStringParameters
+	limit, offset := defaultLimit, 0
+	if value := params["limit"]; value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxLimit {
+			return response.Error(400, fmt.Sprintf("limit must be between 1 and %d", maxLimit))
+		}
+	}
+	if value := params["offset"]; value != "" {
+		offset, err = strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return response.Error(400, "offset must be a non-negative integer")
+		}
+	}
+
+	from, err := parseDate(params["from"], false)
+	if err != nil {
+		return response.Error(400, "Invalid from date (use YYYY-MM-DD or RFC 3339)")
+	}
+	to, err := parseDate(params["to"], true)
+	if err != nil {
+		return response.Error(400, "Invalid to date (use YYYY-MM-DD or RFC 3339)")
+	}
+	txType := params["type"]
+	switch txType {
+	case "", blockchain.HistoryTaskLock, blockchain.HistoryMilestoneReceived, blockchain.HistoryRefund,
+		blockchain.HistoryTransferIn, blockchain.HistoryTransferOut:
+	default:
+		return response.Error(400, fmt.Sprintf("Unknown type: %s", txType))
+	}
+	exportCSV := params["format"] == "csv"
+
+	// Initialize
+	if err := db.InitDB(); err != nil {
+		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+	}
+	client, err := blockchain.InitClient()
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Blockchain error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	var ethAddress string
+	err = pool.QueryRow(ctx, "SELECT eth_address FROM users WHERE did = $1", claims.DID).Scan(&ethAddress)
+	if err != nil {
+		return response.Error(404, "User not found")
+	}
+
+	// The date range becomes a block range, so only its logs are read
+	var history []blockchain.HistoryEntry
+	first, last, ok, err := client.BlockRange(ctx, from, to)
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to resolve date range: %v", err))
+	}
+	if ok {
+		history, err = client.WalletHistory(ctx, common.HexToAddress(ethAddress), first, last)
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Failed to load transactions: %v", err))
+		}
+	}
+
+	// Apply filters
+	filtered := make([]blockchain.HistoryEntry, 0, len(history))
+	for _, entry := range history {
+		if txType != "" && entry.Type != txType {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+
+	// CSV export covers every matching entry; JSON is paginated
+	page := filtered
+	if !exportCSV {
+		if offset >= len(filtered) {
+			page = nil
+		} else {
+			end := offset + limit
+			if end > len(filtered) {
+				end = len(filtered)
+			}
+			page = filtered[offset:end]
+		}
+	}
+
+	// Block times are fetched only for the entries returned
+	if err := client.FillTimestamps(ctx, page); err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to load block times: %v", err))
+	}
+
+	transactions, err := linkTasks(ctx, client, page)
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to load tasks: %v", err))
+	}
+
+	if exportCSV {
+		body, err := toCSV(transactions)
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Failed to build CSV: %v", err))
+		}
+		return response.CSV("xzt-transactions.csv", body)
+	}
+
+	return response.Success(TransactionsResponse{
+		Transactions: transactions,
+		Total:        len(filtered),
+		Limit:        limit,
+		Offset:       offset,
+	})
+}
+
+// linkTasks converts history entries and attaches the database task for escrow movements
+func linkTasks(ctx context.Context, client *blockchain.BlockchainClient, entries []blockchain.HistoryEntry) ([]Transaction, error) {
+	transactions := make([]Transaction, 0, len(entries))
+	escrows := make([]common.Address, 0, len(entries))
+	var contractIDs []int64
+	for _, entry := range entries {
+		tx := Transaction{
+			TxHash:       entry.TxHash,
+			BlockNumber:  entry.BlockNumber,
+			Timestamp:    entry.Timestamp,
+			Type:         entry.Type,
+			Direction:    entry.Direction,
+			Counterparty: entry.Counterparty.Hex(),
+			Amount:       blockchain.FromWei(entry.Amount, 8),
+		}
+		if entry.ContractTaskID != nil && entry.ContractTaskID.IsInt64() {
+			id := entry.ContractTaskID.Int64()
+			tx.ContractTaskID = &id
+			contractIDs = append(contractIDs, id)
+		}
+		transactions = append(transactions, tx)
+		escrows = append(escrows, entry.EscrowAddress)
+	}
+
+	if len(contractIDs) == 0 {
+		return transactions, nil
+	}
+
+	type taskRef struct {
+		TaskID   string
+		TaskName string
+	}
+	// Contract task ids are unique per escrow; rows without an escrow predate the registry
+	taskKey := func(escrow common.Address, contractID int64) string {
+		return fmt.Sprintf("%s:%d", escrow.Hex(), contractID)
+	}
+	tasks := make(map[string]taskRef)
+
+	rows, err := db.GetPool().Query(ctx, `
+		SELECT contract_task_id, escrow_address, task_id, task_name FROM tasks
+		WHERE contract_task_id = ANY($1) AND (chain_id = $2 OR chain_id IS NULL)
+	`, contractIDs, client.ChainID.Int64())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var contractID int64
+		var escrowAddress *string
+		var ref taskRef
+		if err := rows.Scan(&contractID, &escrowAddress, &ref.TaskID, &ref.TaskName); err != nil {
+			return nil, err
+		}
+		escrow := client.EscrowAddress
+		if escrowAddress != nil {
+			escrow = common.HexToAddress(*escrowAddress)
+		}
+		tasks[taskKey(escrow, contractID)] = ref
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range transactions {
+		if transactions[i].ContractTaskID == nil {
+			continue
+		}
+		if ref, ok := tasks[taskKey(escrows[i], *transactions[i].ContractTaskID)]; ok {
+			transactions[i].TaskID = &ref.TaskID
+			transactions[i].TaskName = &ref.TaskName
+		}
+	}
+
+	return transactions, nil
+}
+
+func toCSV(transactions []Transaction) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"timestamp", "type", "direction", "amount", "counterparty", "task_id", "task_name", "contract_task_id", "tx_hash", "block_number"})
+	for _, tx := range transactions {
+		var taskID, taskName, contractTaskID string
+		if tx.TaskID != nil {
+			taskID = *tx.TaskID
+		}
+		if tx.TaskName != nil {
+			taskName = *tx.TaskName
+		}
+		if tx.ContractTaskID != nil {
+			contractTaskID = strconv.FormatInt(*tx.ContractTaskID, 10)
+		}
+		w.Write([]string{
+			tx.Timestamp.Format(time.RFC3339), tx.Type, tx.Direction, tx.Amount, tx.Counterparty,
+			taskID, taskName, contractTaskID, tx.TxHash, strconv.FormatUint(tx.BlockNumber, 10),
+		})
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// parseDate accepts YYYY-MM-DD or RFC 3339. A bare end date covers the whole day.
+func parseDate(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
+func main() {
+	lambda.Start(handler)
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/blockchain"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/models"
+	"github.com/x-zero/xz-wallet/pkg/ranking"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+type ListBidsResponse struct {
+	TaskID string    `json:"task_id"`
+	Sort   string    `json:"sort"`
+	Bids   []BidInfo `json:"bids"`
+}
+
+type BidInfo struct {
+	models.TaskBid
+	Bidder BidderInfo    `json:"bidder"`
+	Score  ranking.Score `json:"score"`
+}
+
+type BidderInfo struct {
+	DID              string   `json:"did"`
+	Username         string   `json:"username"`
+	CreditScore      int      `json:"credit_score"`
+	TasksCompleted   int      `json:"tasks_completed"`
+	TasksCancelled   int      `json:"tasks_cancelled"`
+	ProfessionTags   []string `json:"profession_tags"`
+	OnTimeDeliveries int      `json:"on_time_deliveries"`
+	DatedDeliveries  int      `json:"dated_deliveries"`
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	taskID := request.PathParameters["id"]
+	if taskID == "" {
+		return response.Error(400, "Missing task ID")
+	}
+
+	sortBy := request.QueryStringParameters["sort"]
+	if sortBy == "" {
+		sortBy = "newest"
+	}
+	if sortBy != "newest" && sortBy != "recommended" {
+		return response.Error(400, "sort must be newest or recommended")
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	var creatorDID, rewardAmount string
+	var taskTags []string
+	err = pool.QueryRow(ctx, `
+		SELECT creator_did, reward_amount::text, profession_tags FROM tasks WHERE task_id = $1
+	`, taskID).Scan(&creatorDID, &rewardAmount, &taskTags)
+	if err != nil {
+		return response.Error(404, "Task not found")
+	}
+
+	if creatorDID != claims.DID {
+		return response.Error(403, "Only creator can list bids")
+	}
+
+	rewardWei, err := blockchain.ToWei(rewardAmount)
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Invalid task reward: %v", err))
+	}
+
+	// On-time history: completed tasks whose accepted bid named a delivery date
+	rows, err := pool.Query(ctx, `
+		SELECT tb.bid_id, tb.task_id, tb.bidder_did, tb.bid_message, tb.credit_score_snapshot,
+		       tb.proposed_reward::text, tb.estimated_delivery_date, tb.proposed_milestone_bps,
+		       tb.attachment_urls, tb.proposed_team, tb.status, tb.created_at, tb.updated_at,
+		       u.username, u.credit_score, u.tasks_completed, u.tasks_cancelled, u.profession_tags,
+		       COALESCE(d.on_time, 0), COALESCE(d.dated, 0)
+		FROM task_bids tb
+		JOIN users u ON tb.bidder_did = u.did
+		LEFT JOIN LATERAL (
+			SELECT COUNT(*) FILTER (WHERE t.completed_at::date <= ab.estimated_delivery_date) AS on_time,
+			       COUNT(*) AS dated
+			FROM task_bids ab
+			JOIN tasks t ON t.task_id = ab.task_id
+			WHERE ab.bidder_did = tb.bidder_did
+			  AND ab.status = 'accepted'
+			  AND ab.estimated_delivery_date IS NOT NULL
+			  AND t.status = 'completed'
+		) d ON TRUE
+		WHERE tb.task_id = $1
+		ORDER BY tb.created_at DESC
+	`, taskID)
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Query failed: %v", err))
+	}
+	defer rows.Close()
+
+	var bids []BidInfo
+	var candidates []ranking.Bid
+	for rows.Next() {
+		var bid BidInfo
+		var userTags []string
+		err := rows.Scan(
+			&bid.BidID, &bid.TaskID, &bid.BidderDID, &bid.BidMessage, &bid.CreditScoreSnapshot,
+			&bid.ProposedReward, &bid.EstimatedDeliveryDate, &bid.ProposedMilestoneBps,
+			&bid.AttachmentURLs, &bid.ProposedTeam, &bid.Status, &bid.CreatedAt, &bid.UpdatedAt,
+			&bid.Bidder.Username, &bid.Bidder.CreditScore, &bid.Bidder.TasksCompleted,
+			&bid.Bidder.TasksCancelled, &userTags,
+			&bid.Bidder.OnTimeDeliveries, &bid.Bidder.DatedDeliveries,
+		)
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Scan failed: %v", err))
+		}
+		bid.Bidder.DID = bid.BidderDID
+		bid.Bidder.ProfessionTags = userTags
+		if bid.Bidder.ProfessionTags == nil {
+			bid.Bidder.ProfessionTags = []string{}
+		}
+
+		var proposedWei *big.Int
+		if bid.ProposedReward != nil {
+			if proposedWei, err = blockchain.ToWei(*bid.ProposedReward); err != nil {
+				return response.Error(500, fmt.Sprintf("Invalid proposed reward: %v", err))
+			}
+		}
+
+		bids = append(bids, bid)
+		candidates = append(candidates, ranking.Bid{
+			BidID:            bid.BidID,
+			CreditScore:      bid.Bidder.CreditScore,
+			TasksCompleted:   bid.Bidder.TasksCompleted,
+			TasksCancelled:   bid.Bidder.TasksCancelled,
+			ProfessionTags:   userTags,
+			OnTimeDeliveries: bid.Bidder.OnTimeDeliveries,
+			DatedDeliveries:  bid.Bidder.DatedDeliveries,
+			ProposedReward:   proposedWei,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return response.Error(500, fmt.Sprintf("Query failed: %v", err))
+	}
+
+	scores, order := ranking.Rank(ranking.Task{ProfessionTags: taskTags, Reward: rewardWei}, candidates)
+
+	result := make([]BidInfo, 0, len(bids))
+	if sortBy == "recommended" {
+		// Only open bids can still be chosen, so they rank ahead of the rest
+		for _, open := range []bool{true, false} {
+			for _, i := range order {
+				if (bids[i].Status == models.BidStatusPending) == open {
+					bids[i].Score = scores[i]
+					result = append(result, bids[i])
+				}
+			}
+		}
+	} else {
+		for i := range bids {
+			bids[i].Score = scores[i]
+			result = append(result, bids[i])
+		}
+	}
+
+	return response.Success(ListBidsResponse{
+		TaskID: taskID,
+		Sort:   sortBy,
+		Bids:   result,
+	})
+}
+
+func main() {
+	lambda.Start(handler)
+}
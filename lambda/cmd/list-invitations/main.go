@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/models"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+type ListInvitationsResponse struct {
+	Invitations []InvitationWithTask `json:"invitations"`
+	Total       int                  `json:"total"`
+}
+
+type InvitationWithTask struct {
+	models.TaskInvitation
+	TaskName        string `json:"task_name"`
+	RewardAmount    string `json:"reward_amount"`
+	TaskStatus      string `json:"task_status"`
+	AssignmentMode  string `json:"assignment_mode"`
+	CreatorDID      string `json:"creator_did"`
+	CreatorUsername string `json:"creator_username"`
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	status := request.QueryStringParameters["status"]
+	if status != "" && status != models.InvitationStatusPending &&
+		status != models.InvitationStatusAccepted && status != models.InvitationStatusDeclined {
+		return response.Error(400, "status must be pending, accepted or declined")
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	rows, err := pool.Query(ctx, `
+		SELECT ti.invitation_id, ti.task_id, ti.invitee_did, ti.status, ti.created_at, ti.responded_at,
+		       t.task_name, t.reward_amount, t.status, t.assignment_mode, t.creator_did, u.username
+		FROM task_invitations ti
+		JOIN tasks t ON ti.task_id = t.task_id
+		JOIN users u ON t.creator_did = u.did
+		WHERE ti.invitee_did = $1 AND ($2 = '' OR ti.status = $2)
+		ORDER BY ti.created_at DESC
+	`, claims.DID, status)
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Query failed: %v", err))
+	}
+	defer rows.Close()
+
+	invitations := []InvitationWithTask{}
+	for rows.Next() {
+		var inv InvitationWithTask
+		err := rows.Scan(
+			&inv.InvitationID, &inv.TaskID, &inv.InviteeDID, &inv.Status, &inv.CreatedAt, &inv.RespondedAt,
+			&inv.TaskName, &inv.RewardAmount, &inv.TaskStatus, &inv.AssignmentMode, &inv.CreatorDID, &inv.CreatorUsername,
+		)
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Scan failed: %v", err))
+		}
+		invitations = append(invitations, inv)
+	}
+
+	return response.Success(ListInvitationsResponse{
+		Invitations: invitations,
+		Total:       len(invitations),
+	})
+}
+
+func main() {
+	lambda.Start(handler)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/models"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+// Notifications returned per request, by default and at most
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+type ListNotificationsResponse struct {
+	Notifications []models.Notification `json:"notifications"`
+	Total         int                   `json:"total"`
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	limit := defaultLimit
+	if value := request.QueryStringParameters["limit"]; value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxLimit {
+			return response.Error(400, fmt.Sprintf("limit must be 1 to %d", maxLimit))
+		}
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	rows, err := pool.Query(ctx, `
+		SELECT notification_id, user_did, task_id, type, payload, created_at
+		FROM notifications
+		WHERE user_did = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, claims.DID, limit)
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Query failed: %v", err))
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var notification models.Notification
+		err := rows.Scan(
+			&notification.NotificationID, &notification.UserDID, &notification.TaskID,
+			&notification.Type, &notification.Payload, &notification.CreatedAt,
+		)
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Scan failed: %v", err))
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return response.Success(ListNotificationsResponse{
+		Notifications: notifications,
+		Total:         len(notifications),
+	})
+}
+
+func main() {
+	lambda.Start(handler)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/x-zero/xz-wallet/pkg/blockchain"
 	"github.com/x-zero/xz-wallet/pkg/db"
 	"github.com/x-zero/xz-wallet/pkg/models"
 	"github.com/x-zero/xz-wallet/pkg/response"
@@ -18,9 +19,19 @@ type ListTasksResponse struct {
 
 type TaskWithDetails struct {
 	models.Task
-	CreatorUsername  string  `json:"creator_username"`
-	ExecutorUsername *string `json:"executor_username,omitempty"`
-	BidCount         int     `json:"bid_count"`
+	CreatorUsername  string        `json:"creator_username"`
+	ExecutorUsername *string       `json:"executor_username,omitempty"`
+	BidCount         int           `json:"bid_count"`
+	OnChain          *OnChainState `json:"onchain,omitempty"`
+}
+
+// OnChainState is the live escrow state of a task (with ?onchain=true)
+type OnChainState struct {
+	Executor    string `json:"executor"`
+	TotalAmount string `json:"total_amount"`
+	PaidAmount  string `json:"paid_amount"`
+	Remaining   string `json:"remaining"`
+	Cancelled   bool   `json:"cancelled"`
 }
 
 func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
@@ -29,8 +40,8 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 			StatusCode: 200,
 			Headers: map[string]string{
 				"Access-Control-Allow-Origin":  "*",
-				"Access-Control-Allow-Headers": "Content-Type,Authorization",
-				"Access-Control-Allow-Methods": "GET,POST,PUT,DELETE,OPTIONS",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
 			},
 		}, nil
 	}
@@ -48,11 +59,12 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	executorDID := request.QueryStringParameters["executor_did"]
 	creatorDID := request.QueryStringParameters["creator_did"]
 	bidderDID := request.QueryStringParameters["bidder_did"]
+	parentTaskID := request.QueryStringParameters["parent_task_id"]
 
 	// Build query
 	query := `
 		SELECT 
-			t.task_id, t.contract_task_id, t.project_id, t.creator_did, t.executor_did,
+			t.task_id, t.contract_task_id, t.chain_id, t.escrow_address, t.project_id, t.creator_did, t.executor_did,
 			t.task_name, t.task_description, t.acceptance_criteria,
 			t.reward_amount, t.paid_amount, t.visibility, t.status, t.profession_tags,
 			t.created_at, t.updated_at,
@@ -84,7 +96,7 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		argCount++
 	}
 	if executorDID != "" {
-		query += fmt.Sprintf(" AND t.executor_did = $%d", argCount)
+		query += fmt.Sprintf(" AND (t.executor_did = $%d OR EXISTS (SELECT 1 FROM task_team_members m WHERE m.task_id = t.task_id AND m.member_did = $%d))", argCount, argCount)
 		args = append(args, executorDID)
 		argCount++
 	}
@@ -94,11 +106,21 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		argCount++
 	}
 	if bidderDID != "" {
-		// Filter tasks where user has placed a bid
-		query += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM task_bids WHERE task_id = t.task_id AND bidder_did = $%d)", argCount)
+		// Filter tasks where user has placed a bid (and not withdrawn it)
+		query += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM task_bids WHERE task_id = t.task_id AND bidder_did = $%d AND status != 'withdrawn')", argCount)
 		args = append(args, bidderDID)
 		argCount++
 	}
+	if parentTaskID != "" {
+		query += fmt.Sprintf(" AND t.parent_task_id = $%d", argCount)
+		args = append(args, parentTaskID)
+		argCount++
+	}
+
+	// Invite-only tasks show up only when asked for, or in a user's own lists
+	if visibility == "" && creatorDID == "" && executorDID == "" && bidderDID == "" {
+		query += " AND t.visibility != 'invite'"
+	}
 
 	query += " GROUP BY t.task_id, u_creator.username, u_executor.username ORDER BY t.created_at DESC"
 
@@ -112,7 +134,7 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	for rows.Next() {
 		var task TaskWithDetails
 		err := rows.Scan(
-			&task.TaskID, &task.ContractTaskID, &task.ProjectID, &task.CreatorDID, &task.ExecutorDID,
+			&task.TaskID, &task.ContractTaskID, &task.ChainID, &task.EscrowAddress, &task.ProjectID, &task.CreatorDID, &task.ExecutorDID,
 			&task.TaskName, &task.TaskDescription, &task.AcceptanceCriteria,
 			&task.RewardAmount, &task.PaidAmount, &task.Visibility, &task.Status, &task.ProfessionTags,
 			&task.CreatedAt, &task.UpdatedAt,
@@ -124,12 +146,66 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		tasks = append(tasks, task)
 	}
 
+	// Optionally attach live escrow state, read in one batch
+	if request.QueryStringParameters["onchain"] == "true" {
+		if err := attachOnChainState(ctx, tasks); err != nil {
+			return response.Error(500, fmt.Sprintf("Blockchain error: %v", err))
+		}
+	}
+
 	return response.Success(ListTasksResponse{
 		Tasks: tasks,
 		Total: len(tasks),
 	})
 }
 
+func attachOnChainState(ctx context.Context, tasks []TaskWithDetails) error {
+	// Group tasks by the escrow that holds them; each group is one batch
+	type group struct {
+		client  *blockchain.BlockchainClient
+		indexes []int
+		ids     []uint64
+	}
+	groups := make(map[*blockchain.BlockchainClient]*group)
+	for i, task := range tasks {
+		if task.ContractTaskID == nil || *task.ContractTaskID < 0 {
+			continue
+		}
+		client, err := blockchain.ClientForTask(task.ChainID, task.EscrowAddress)
+		if err != nil {
+			return err
+		}
+		g, ok := groups[client]
+		if !ok {
+			g = &group{client: client}
+			groups[client] = g
+		}
+		g.indexes = append(g.indexes, i)
+		g.ids = append(g.ids, uint64(*task.ContractTaskID))
+	}
+
+	for _, g := range groups {
+		states, err := g.client.NewBatchReader().TaskStates(ctx, g.ids)
+		if err != nil {
+			return err
+		}
+		for n, i := range g.indexes {
+			state, ok := states[g.ids[n]]
+			if !ok {
+				continue
+			}
+			tasks[i].OnChain = &OnChainState{
+				Executor:    state.Executor.Hex(),
+				TotalAmount: blockchain.FromWei(state.TotalAmount, 8),
+				PaidAmount:  blockchain.FromWei(state.PaidAmount, 8),
+				Remaining:   blockchain.FromWei(state.Remaining, 8),
+				Cancelled:   state.Cancelled,
+			}
+		}
+	}
+	return nil
+}
+
 func main() {
 	lambda.Start(handler)
 }
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/models"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+type ListTemplatesResponse struct {
+	Templates []models.TaskTemplate `json:"templates"`
+	Total     int                   `json:"total"`
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	if _, err := auth.ValidateToken(authHeader); err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	projectID := request.PathParameters["id"]
+	if projectID == "" {
+		return response.Error(400, "Missing project ID")
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	rows, err := pool.Query(ctx, `
+		SELECT template_id, project_id, creator_did, template_name, task_description,
+		       acceptance_criteria, profession_tags, milestone_bps, created_at, updated_at
+		FROM task_templates
+		WHERE project_id = $1
+		ORDER BY template_name
+	`, projectID)
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Query failed: %v", err))
+	}
+	defer rows.Close()
+
+	templates := []models.TaskTemplate{}
+	for rows.Next() {
+		var template models.TaskTemplate
+		err := rows.Scan(
+			&template.TemplateID, &template.ProjectID, &template.CreatorDID, &template.TemplateName, &template.TaskDescription,
+			&template.AcceptanceCriteria, &template.ProfessionTags, &template.MilestoneBps, &template.CreatedAt, &template.UpdatedAt,
+		)
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Scan failed: %v", err))
+		}
+		templates = append(templates, template)
+	}
+
+	return response.Success(ListTemplatesResponse{
+		Templates: templates,
+		Total:     len(templates),
+	})
+}
+
+func main() {
+	lambda.Start(handler)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	var req LogoutRequest
+	if request.Body != "" {
+		if err := json.Unmarshal([]byte(request.Body), &req); err != nil {
+			return response.Error(400, "Invalid request body")
+		}
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	// Revoke the access token (legacy tokens without jti simply expire)
+	if claims.ID != "" {
+		if err := auth.RevokeToken(ctx, pool, claims); err != nil {
+			return response.Error(500, fmt.Sprintf("Failed to revoke token: %v", err))
+		}
+	}
+
+	// Revoke the refresh token family so the session cannot be renewed
+	if req.RefreshToken != "" {
+		if err := auth.RevokeRefreshToken(ctx, pool, claims.DID, req.RefreshToken); err != nil {
+			return response.Error(500, fmt.Sprintf("Failed to revoke refresh token: %v", err))
+		}
+	}
+
+	return response.SuccessWithMessage("Logged out")
+}
+
+func main() {
+	lambda.Start(handler)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/blockchain"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/models"
+	"github.com/x-zero/xz-wallet/pkg/ranking"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 50
+
+	// Newest open tasks considered for ranking
+	maxCandidates = 500
+)
+
+type RecommendTasksResponse struct {
+	Tasks      []RecommendedTask `json:"tasks"`
+	Total      int               `json:"total"`
+	Limit      int               `json:"limit"`
+	Offset     int               `json:"offset"`
+	NextOffset *int              `json:"next_offset,omitempty"`
+	CreditTier string            `json:"credit_tier"`
+	AsOf       time.Time         `json:"as_of"` // Pass back as ?as_of= to page through the same ranking
+}
+
+type RecommendedTask struct {
+	models.Task
+	CreatorUsername string        `json:"creator_username"`
+	Score           ranking.Score `json:"score"`
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	limit, err := queryInt(request.QueryStringParameters["limit"], defaultLimit)
+	if err != nil || limit < 1 || limit > maxLimit {
+		return response.Error(400, fmt.Sprintf("limit must be between 1 and %d", maxLimit))
+	}
+	offset, err := queryInt(request.QueryStringParameters["offset"], 0)
+	if err != nil || offset < 0 {
+		return response.Error(400, "offset must be a non-negative integer")
+	}
+
+	// Freshness depends on the clock; a fixed as_of keeps pages consistent
+	asOf := time.Now().UTC().Truncate(time.Second)
+	if value := request.QueryStringParameters["as_of"]; value != "" {
+		if asOf, err = time.Parse(time.RFC3339, value); err != nil {
+			return response.Error(400, "as_of must be an RFC 3339 time")
+		}
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	var executor ranking.Executor
+	err = pool.QueryRow(ctx, `
+		SELECT credit_score, COALESCE(profession_tags, '{}') FROM users WHERE did = $1
+	`, claims.DID).Scan(&executor.CreditScore, &executor.ProfessionTags)
+	if err != nil {
+		return response.Error(404, "User not found")
+	}
+
+	// Tags of the caller's completed tasks
+	err = pool.QueryRow(ctx, `
+		SELECT COALESCE(array_agg(tag), '{}')
+		FROM tasks t, unnest(t.profession_tags) AS tag
+		WHERE t.executor_did = $1 AND t.status = 'completed'
+	`, claims.DID).Scan(&executor.CompletedTags)
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Query failed: %v", err))
+	}
+
+	// Open tasks the caller could bid on: not their own, not already bid on,
+	// and either global, in a project they belong to, or an invitation they
+	// have not declined
+	rows, err := pool.Query(ctx, `
+		SELECT t.task_id, t.contract_task_id, t.chain_id, t.escrow_address, t.project_id, t.creator_did,
+		       t.task_name, t.task_description, t.acceptance_criteria,
+		       t.reward_amount, t.paid_amount, t.visibility, t.status, t.profession_tags,
+		       t.created_at, t.updated_at,
+		       u.username
+		FROM tasks t
+		JOIN users u ON t.creator_did = u.did
+		WHERE t.status IN ('pending', 'bidding')
+		  AND t.creator_did != $1
+		  AND t.created_at <= $3
+		  AND NOT EXISTS (SELECT 1 FROM task_bids tb WHERE tb.task_id = t.task_id AND tb.bidder_did = $1)
+		  AND (t.visibility = 'global'
+		      OR (t.visibility = 'project' AND EXISTS (
+		          SELECT 1 FROM project_members pm WHERE pm.project_id = t.project_id AND pm.user_did = $1
+		      ))
+		      OR (t.visibility = 'invite' AND EXISTS (
+		          SELECT 1 FROM task_invitations ti
+		          WHERE ti.task_id = t.task_id AND ti.invitee_did = $1 AND ti.status != 'declined'
+		      )))
+		ORDER BY t.created_at DESC, t.task_id
+		LIMIT $2
+	`, claims.DID, maxCandidates, asOf)
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Query failed: %v", err))
+	}
+	defer rows.Close()
+
+	var tasks []RecommendedTask
+	var candidates []ranking.OpenTask
+	for rows.Next() {
+		var task RecommendedTask
+		err := rows.Scan(
+			&task.TaskID, &task.ContractTaskID, &task.ChainID, &task.EscrowAddress, &task.ProjectID, &task.CreatorDID,
+			&task.TaskName, &task.TaskDescription, &task.AcceptanceCriteria,
+			&task.RewardAmount, &task.PaidAmount, &task.Visibility, &task.Status, &task.ProfessionTags,
+			&task.CreatedAt, &task.UpdatedAt,
+			&task.CreatorUsername,
+		)
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Scan failed: %v", err))
+		}
+		reward, err := blockchain.ToWei(task.RewardAmount)
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Invalid task reward: %v", err))
+		}
+		tasks = append(tasks, task)
+		candidates = append(candidates, ranking.OpenTask{
+			TaskID:         task.TaskID,
+			ProfessionTags: task.ProfessionTags,
+			Reward:         reward,
+			CreatedAt:      task.CreatedAt,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return response.Error(500, fmt.Sprintf("Query failed: %v", err))
+	}
+
+	scores, order := ranking.RecommendTasks(executor, candidates, asOf)
+
+	page := []RecommendedTask{}
+	for n := offset; n < len(order) && n < offset+limit; n++ {
+		i := order[n]
+		tasks[i].Score = scores[i]
+		page = append(page, tasks[i])
+	}
+
+	resp := RecommendTasksResponse{
+		Tasks:      page,
+		Total:      len(order),
+		Limit:      limit,
+		Offset:     offset,
+		CreditTier: ranking.TierFor(executor.CreditScore).Name,
+		AsOf:       asOf,
+	}
+	if next := offset + limit; next < len(order) {
+		resp.NextOffset = &next
+	}
+	return response.Success(resp)
+}
+
+// queryInt parses an optional integer query parameter
+func queryInt(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func main() {
+	lambda.Start(handler)
+}
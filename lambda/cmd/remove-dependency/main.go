@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/jackc/pgx/v5"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/models"
+	"github.com/x-zero/xz-wallet/pkg/response"
+	"github.com/x-zero/xz-wallet/pkg/taskgraph"
+)
+
+type DependencyResponse struct {
+	TaskID          string `json:"task_id"`
+	DependsOnTaskID string `json:"depends_on_task_id"`
+	Status          string `json:"status"` // Task status after the change
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	taskID := request.PathParameters["id"]
+	dependsOnTaskID := request.PathParameters["depends_on_id"]
+	if taskID == "" || dependsOnTaskID == "" {
+		return response.Error(400, "Missing task ID")
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return response.Error(500, "Failed to start transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	var creatorDID, status string
+	err = tx.QueryRow(ctx, `
+		SELECT creator_did, status FROM tasks WHERE task_id = $1 FOR UPDATE
+	`, taskID).Scan(&creatorDID, &status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return response.Error(404, "Task not found")
+	}
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to load task: %v", err))
+	}
+
+	if creatorDID != claims.DID {
+		return response.Error(403, "Only creator can change dependencies")
+	}
+	if !taskgraph.Editable(status) {
+		return response.Error(400, "Dependencies can only change before an executor is assigned")
+	}
+
+	tag, err := tx.Exec(ctx, `
+		DELETE FROM task_dependencies WHERE task_id = $1 AND depends_on_task_id = $2
+	`, taskID, dependsOnTaskID)
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to remove dependency: %v", err))
+	}
+	if tag.RowsAffected() == 0 {
+		return response.Error(404, "Dependency not found")
+	}
+
+	// Removing the last unfinished prerequisite, e.g. a cancelled one, opens bidding
+	if status == models.TaskStatusBlocked {
+		unmet, err := taskgraph.UnmetDependencies(ctx, tx, taskID)
+		if err != nil {
+			return response.Error(500, fmt.Sprintf("Failed to check dependencies: %v", err))
+		}
+		if unmet == 0 {
+			_, err = tx.Exec(ctx, `
+				UPDATE tasks SET status = 'bidding', updated_at = CURRENT_TIMESTAMP WHERE task_id = $1
+			`, taskID)
+			if err != nil {
+				return response.Error(500, fmt.Sprintf("Failed to update task: %v", err))
+			}
+			status = models.TaskStatusBidding
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return response.Error(500, "Failed to commit transaction")
+	}
+
+	return response.Success(DependencyResponse{
+		TaskID:          taskID,
+		DependsOnTaskID: dependsOnTaskID,
+		Status:          status,
+	})
+}
+
+func main() {
+	lambda.Start(handler)
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v5"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/bids"
+	"github.com/x-zero/xz-wallet/pkg/blockchain"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/idempotency"
+	"github.com/x-zero/xz-wallet/pkg/models"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+// RespondInvitationRequest carries the executor's signed resignation when a
+// direct assignee declines a task whose v2 escrow already names them
+type RespondInvitationRequest struct {
+	ExecutorSignature string `json:"executor_signature,omitempty"` // executor's EIP-712 ResignExecutor signature
+	Deadline          int64  `json:"deadline,omitempty"`
+}
+
+type RespondInvitationResponse struct {
+	TaskID     string `json:"task_id"`
+	Status     string `json:"status"`
+	TaskStatus string `json:"task_status"`
+	TxHash     string `json:"tx_hash,omitempty"`
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	taskID := request.PathParameters["id"]
+	if taskID == "" {
+		return response.Error(400, "Missing task ID")
+	}
+
+	var newStatus string
+	switch request.PathParameters["action"] {
+	case "accept":
+		newStatus = models.InvitationStatusAccepted
+	case "decline":
+		newStatus = models.InvitationStatusDeclined
+	default:
+		return response.Error(400, "Action must be accept or decline")
+	}
+
+	var req RespondInvitationRequest
+	if request.Body != "" {
+		if err := json.Unmarshal([]byte(request.Body), &req); err != nil {
+			return response.Error(400, "Invalid request body")
+		}
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return response.RetryableError(500, "Failed to start transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	var task struct {
+		ContractTaskID int64
+		Status         string
+		AssignmentMode string
+		ExecutorDID    *string
+		ChainID        *int64
+		EscrowAddress  *string
+	}
+	err = tx.QueryRow(ctx, `
+		SELECT contract_task_id, status, assignment_mode, executor_did, chain_id, escrow_address
+		FROM tasks WHERE task_id = $1 FOR UPDATE
+	`, taskID).Scan(&task.ContractTaskID, &task.Status, &task.AssignmentMode, &task.ExecutorDID,
+		&task.ChainID, &task.EscrowAddress)
+	if err != nil {
+		return response.Error(404, "Task not found")
+	}
+
+	var invitationID, status string
+	err = tx.QueryRow(ctx, `
+		SELECT invitation_id, status FROM task_invitations WHERE task_id = $1 AND invitee_did = $2
+	`, taskID, claims.DID).Scan(&invitationID, &status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return response.Error(404, "Invitation not found")
+	}
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Failed to load invitation: %v", err))
+	}
+
+	// Responding the same way twice is a no-op; a decline is final
+	if status == newStatus {
+		return response.Success(RespondInvitationResponse{TaskID: taskID, Status: status, TaskStatus: task.Status})
+	}
+	if status == models.InvitationStatusDeclined {
+		return response.Error(400, "Invitation was already declined")
+	}
+	if task.Status == models.TaskStatusCompleted || task.Status == models.TaskStatusCancelled {
+		return response.Error(400, fmt.Sprintf("Task is %s", task.Status))
+	}
+
+	isExecutor := task.ExecutorDID != nil && *task.ExecutorDID == claims.DID
+	resign := newStatus == models.InvitationStatusDeclined && isExecutor
+	if resign {
+		// A direct assignee can hand the task back until work is submitted
+		if task.AssignmentMode != models.AssignmentDirect || task.Status != models.TaskStatusAccepted {
+			return response.Error(400, "You are the task's executor; ask the creator to cancel instead")
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE tasks SET executor_did = NULL, executor_address = NULL, accepted_criteria = NULL, status = 'pending',
+			    updated_at = CURRENT_TIMESTAMP
+			WHERE task_id = $1
+		`, taskID)
+		if err != nil {
+			return response.RetryableError(500, "Failed to update task")
+		}
+		task.Status = models.TaskStatusPending
+	}
+
+	if newStatus == models.InvitationStatusDeclined {
+		// A declined invitee's open bid is withdrawn with it
+		var bidID string
+		err = tx.QueryRow(ctx, `
+			UPDATE task_bids SET status = 'withdrawn', updated_at = CURRENT_TIMESTAMP
+			WHERE task_id = $1 AND bidder_did = $2 AND status = 'pending'
+			RETURNING bid_id
+		`, taskID, claims.DID).Scan(&bidID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return response.RetryableError(500, "Failed to withdraw bid")
+		}
+		if bidID != "" {
+			if err := bids.RecordRevision(ctx, tx, bidID, models.BidRevisionWithdrawn); err != nil {
+				return response.RetryableError(500, fmt.Sprintf("Failed to record bid revision: %v", err))
+			}
+
+			// A task left without pending bids goes back to pending
+			reopened, err := bids.ReturnToPending(ctx, tx, taskID)
+			if err != nil {
+				return response.RetryableError(500, "Failed to update task status")
+			}
+			if reopened {
+				task.Status = models.TaskStatusPending
+			}
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE task_invitations SET status = $1, responded_at = CURRENT_TIMESTAMP
+		WHERE invitation_id = $2
+	`, newStatus, invitationID)
+	if err != nil {
+		return response.RetryableError(500, "Failed to update invitation")
+	}
+
+	// The escrow must forget the executor too, or it would still pay them.
+	// This runs last so only the commit can fail after the chain call.
+	var txHash string
+	if resign {
+		client, err := blockchain.ClientForTask(task.ChainID, task.EscrowAddress)
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Blockchain error: %v", err))
+		}
+		_, executor, _, _, _, err := client.GetTask(uint64(task.ContractTaskID))
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Failed to read task from blockchain: %v", err))
+		}
+
+		// Nothing to clear while the executor was never set on chain
+		if common.HexToAddress(executor) != (common.Address{}) {
+			if !client.IsEscrowV2() {
+				return response.Error(400, "The escrow already names you as executor; ask the creator to cancel instead")
+			}
+			if req.ExecutorSignature == "" || req.Deadline == 0 {
+				return response.Error(400, "executor_signature and deadline are required: sign the resign authorization from GET /tasks/{id}/escrow-authorization")
+			}
+			executorSig, err := blockchain.ParseSignature(req.ExecutorSignature)
+			if err != nil {
+				return response.Error(400, fmt.Sprintf("executor_signature: %v", err))
+			}
+			authorization, err := client.NewResignAuthorization(ctx, uint64(task.ContractTaskID), big.NewInt(req.Deadline))
+			if err != nil {
+				return response.RetryableError(500, fmt.Sprintf("Failed to build authorization: %v", err))
+			}
+			if err := client.VerifyEscrowSignatures(ctx, authorization, nil, executorSig); err != nil {
+				return response.Error(400, fmt.Sprintf("Invalid signature: %v", err))
+			}
+			txHash, err = client.ResignExecutorSigned(ctx, authorization, executorSig)
+			if err != nil {
+				return response.Error(500, fmt.Sprintf("Failed to resign on blockchain: %v", err))
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		if txHash != "" {
+			fmt.Printf("CRITICAL: Executor resigned on blockchain (task_id=%s, tx=%s) but failed to update database: %v\n",
+				taskID, txHash, err)
+		}
+		return response.Error(500, "Failed to commit transaction")
+	}
+
+	return response.Success(RespondInvitationResponse{
+		TaskID:     taskID,
+		Status:     newStatus,
+		TaskStatus: task.Status,
+		TxHash:     txHash,
+	})
+}
+
+func main() {
+	lambda.Start(idempotency.Wrap(handler))
+}
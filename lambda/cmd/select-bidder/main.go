@@ -4,24 +4,41 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/big"
+	"strings"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/x-zero/xz-wallet/pkg/auth"
 	"github.com/x-zero/xz-wallet/pkg/blockchain"
 	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/idempotency"
+	"github.com/x-zero/xz-wallet/pkg/models"
 	"github.com/x-zero/xz-wallet/pkg/response"
 )
 
 type SelectBidderRequest struct {
-	BidderDID string `json:"bidder_did"`
+	BidderDID   string                 `json:"bidder_did"`
+	AcceptPrice bool                   `json:"accept_price,omitempty"` // Take the bid's proposed_reward as the task reward
+	Team        []models.BidTeamMember `json:"team,omitempty"`         // Optional; must repeat the team proposed with the bid
+}
+
+type TeamMemberResponse struct {
+	DID      string `json:"did"`
+	PayoutTo string `json:"payout_to"`
+	ShareBps uint16 `json:"share_bps"`
 }
 
 type SelectBidderResponse struct {
-	TaskID      string `json:"task_id"`
-	ExecutorDID string `json:"executor_did"`
-	TxHash      string `json:"tx_hash"`
-	Status      string `json:"status"`
+	TaskID       string               `json:"task_id"`
+	ExecutorDID  string               `json:"executor_did"`
+	PayoutTo     string               `json:"payout_to"`
+	RewardAmount string               `json:"reward_amount"`
+	RefundTxHash string               `json:"refund_tx_hash,omitempty"`
+	Team         []TeamMemberResponse `json:"team,omitempty"`
+	TxHash       string               `json:"tx_hash"`
+	Status       string               `json:"status"`
 }
 
 func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
@@ -30,8 +47,8 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 			StatusCode: 200,
 			Headers: map[string]string{
 				"Access-Control-Allow-Origin":  "*",
-				"Access-Control-Allow-Headers": "Content-Type,Authorization",
-				"Access-Control-Allow-Methods": "GET,POST,PUT,DELETE,OPTIONS",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
 			},
 		}, nil
 	}
@@ -61,29 +78,45 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	}
 
 	if err := db.InitDB(); err != nil {
-		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+		return response.RetryableError(500, fmt.Sprintf("Database error: %v", err))
 	}
 
-	client, err := blockchain.InitClient()
+	pool := db.GetPool()
+
+	// Lock the task until the executor is recorded, so a second selection or
+	// a reward change cannot move the escrow under this one
+	tx, err := pool.Begin(ctx)
 	if err != nil {
-		return response.Error(500, fmt.Sprintf("Blockchain error: %v", err))
+		return response.RetryableError(500, "Failed to start transaction")
 	}
-
-	pool := db.GetPool()
+	defer tx.Rollback(ctx)
 
 	// Get task
 	var task struct {
 		ContractTaskID int64
 		CreatorDID     string
 		Status         string
+		RewardAmount   string
+		ChainID        *int64
+		EscrowAddress  *string
+		Criteria       string
 	}
-	err = pool.QueryRow(ctx, `
-		SELECT contract_task_id, creator_did, status FROM tasks WHERE task_id = $1
-	`, taskID).Scan(&task.ContractTaskID, &task.CreatorDID, &task.Status)
+	err = tx.QueryRow(ctx, `
+		SELECT contract_task_id, creator_did, status, reward_amount::text, chain_id, escrow_address, acceptance_criteria
+		FROM tasks WHERE task_id = $1
+		FOR UPDATE
+	`, taskID).Scan(&task.ContractTaskID, &task.CreatorDID, &task.Status, &task.RewardAmount,
+		&task.ChainID, &task.EscrowAddress, &task.Criteria)
 	if err != nil {
 		return response.Error(404, "Task not found")
 	}
 
+	// Settle on the escrow that holds the task
+	client, err := blockchain.ClientForTask(task.ChainID, task.EscrowAddress)
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Blockchain error: %v", err))
+	}
+
 	// Verify user is creator
 	if task.CreatorDID != claims.DID {
 		return response.Error(403, "Only creator can select bidder")
@@ -96,42 +129,161 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 
 	// Verify bid exists
 	var bidID string
-	err = pool.QueryRow(ctx, `
-		SELECT bid_id FROM task_bids WHERE task_id = $1 AND bidder_did = $2 AND status = 'pending'
-	`, taskID, req.BidderDID).Scan(&bidID)
+	var proposedReward *string
+	var proposedTeam []models.BidTeamMember
+	err = tx.QueryRow(ctx, `
+		SELECT bid_id, proposed_reward::text, proposed_team FROM task_bids
+		WHERE task_id = $1 AND bidder_did = $2 AND status = 'pending'
+	`, taskID, req.BidderDID).Scan(&bidID, &proposedReward, &proposedTeam)
 	if err != nil {
 		return response.Error(404, "Bid not found")
 	}
 
-	// Get bidder's eth_address
-	var executorEthAddress string
-	err = pool.QueryRow(ctx, "SELECT eth_address FROM users WHERE did = $1", req.BidderDID).Scan(&executorEthAddress)
+	// The team and its split are the ones the bidder proposed with the bid;
+	// the creator can repeat them to confirm but cannot change them
+	if len(req.Team) > 0 && !models.SameTeam(req.Team, proposedTeam) {
+		return response.Error(400, "team must match the team proposed with the bid")
+	}
+
+	// A team is paid by splitting every executor payment on chain, which
+	// only the v2 escrow does. The bidder leads and signs for the team.
+	var team []TeamMemberResponse
+	if len(proposedTeam) > 0 {
+		if !client.IsEscrowV2() {
+			return response.Error(400, "Task escrow cannot split payouts; the bid's team cannot be selected")
+		}
+		members, err := models.LeadFirst(req.BidderDID, proposedTeam)
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Invalid team on bid: %v", err))
+		}
+		for _, member := range members {
+			if member.DID == task.CreatorDID {
+				return response.Error(400, "Creator cannot be a team member")
+			}
+		}
+
+		shares := make([]uint16, len(members))
+		for i, member := range members {
+			shares[i] = member.ShareBps
+		}
+		if err := blockchain.ValidateTeamShares(shares); err != nil {
+			return response.Error(400, err.Error())
+		}
+
+		addresses := make(map[string]bool)
+		for _, member := range members {
+			var payoutTo string
+			err = tx.QueryRow(ctx, `
+				SELECT COALESCE(payout_address, eth_address) FROM users WHERE did = $1
+			`, member.DID).Scan(&payoutTo)
+			if err != nil {
+				return response.Error(404, fmt.Sprintf("Team member not found: %s", member.DID))
+			}
+			if addresses[strings.ToLower(payoutTo)] {
+				return response.Error(400, fmt.Sprintf("Team members share payout address %s", payoutTo))
+			}
+			addresses[strings.ToLower(payoutTo)] = true
+			team = append(team, TeamMemberResponse{DID: member.DID, PayoutTo: payoutTo, ShareBps: member.ShareBps})
+		}
+	}
+
+	// Pay the bidder's verified payout address, falling back to their eth_address
+	var executorAddress string
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(payout_address, eth_address) FROM users WHERE did = $1
+	`, req.BidderDID).Scan(&executorAddress)
 	if err != nil {
 		return response.Error(404, "Bidder not found")
 	}
 
-	// Set executor on blockchain
-	txHash, err := client.SetExecutor(uint64(task.ContractTaskID), executorEthAddress)
+	// The escrow only sets an executor once. A retry after the database
+	// update failed finds this bidder already set and skips the transaction.
+	payee := executorAddress
+	if len(team) > 0 {
+		payee = team[0].PayoutTo
+	}
+	_, onChainExecutor, lockedWei, _, _, err := client.GetTask(uint64(task.ContractTaskID))
 	if err != nil {
-		return response.Error(500, fmt.Sprintf("Failed to set executor on blockchain: %v", err))
+		return response.RetryableError(500, fmt.Sprintf("Failed to read task from blockchain: %v", err))
+	}
+	alreadySet := strings.EqualFold(onChainExecutor, payee)
+	if !alreadySet && common.HexToAddress(onChainExecutor) != (common.Address{}) {
+		return response.Error(409, "Task already has a different executor on chain")
 	}
 
-	// Update database
-	tx, err := pool.Begin(ctx)
+	// Accepting a lower price refunds the difference before the executor is
+	// set. It is measured from what the escrow holds, so a retry after the
+	// refund went through refunds nothing more.
+	rewardAmount := task.RewardAmount
+	var refundTxHash string
+	if req.AcceptPrice {
+		if proposedReward == nil {
+			return response.Error(400, "Bid has no proposed_reward to accept")
+		}
+		proposedWei, err := blockchain.ToWei(*proposedReward)
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Invalid proposed reward: %v", err))
+		}
+		refund := new(big.Int).Sub(lockedWei, proposedWei)
+		if refund.Sign() < 0 {
+			return response.Error(400, "Proposed reward exceeds the locked task reward")
+		}
+		if refund.Sign() > 0 {
+			if !client.IsEscrowV2() {
+				return response.Error(400, "Task escrow cannot refund part of the reward; select without accept_price")
+			}
+			refundTxHash, err = client.RefundPartial(uint64(task.ContractTaskID), refund)
+			if err != nil {
+				return response.Error(500, fmt.Sprintf("Failed to refund difference on blockchain: %v", err))
+			}
+		}
+		rewardAmount = *proposedReward
+	}
+
+	// Set executor, or the team led by the bidder, on blockchain
+	var txHash string
+	if alreadySet {
+		fmt.Printf("Executor %s already set on chain for task %s\n", payee, taskID)
+	} else if len(team) > 0 {
+		addresses := make([]string, len(team))
+		shares := make([]uint16, len(team))
+		for i, member := range team {
+			addresses[i] = member.PayoutTo
+			shares[i] = member.ShareBps
+		}
+		txHash, err = client.SetExecutorTeam(uint64(task.ContractTaskID), addresses, shares)
+	} else {
+		txHash, err = client.SetExecutor(uint64(task.ContractTaskID), executorAddress)
+	}
 	if err != nil {
-		return response.Error(500, "Failed to start transaction")
+		return response.Error(500, fmt.Sprintf("Failed to set executor on blockchain: %v", err))
 	}
-	defer tx.Rollback(ctx)
 
-	// Update task
+	// Update task, fixing the criteria the work will be reviewed against
 	_, err = tx.Exec(ctx, `
-		UPDATE tasks SET executor_did = $1, status = 'accepted', updated_at = CURRENT_TIMESTAMP
-		WHERE task_id = $2
-	`, req.BidderDID, taskID)
+		UPDATE tasks SET executor_did = $1, executor_address = $2, reward_amount = $3, status = 'accepted',
+		    accepted_criteria = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE task_id = $5
+	`, req.BidderDID, executorAddress, rewardAmount, models.AcceptanceCriteriaList(task.Criteria), taskID)
 	if err != nil {
 		return response.Error(500, "Failed to update task")
 	}
 
+	// Record the team in escrow order, replacing any left by an earlier attempt
+	_, err = tx.Exec(ctx, `DELETE FROM task_team_members WHERE task_id = $1`, taskID)
+	if err != nil {
+		return response.Error(500, "Failed to update team")
+	}
+	for i, member := range team {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO task_team_members (task_id, member_did, payout_address, position, share_bps)
+			VALUES ($1, $2, $3, $4, $5)
+		`, taskID, member.DID, member.PayoutTo, i, member.ShareBps)
+		if err != nil {
+			return response.Error(500, "Failed to record team member")
+		}
+	}
+
 	// Update selected bid
 	_, err = tx.Exec(ctx, `
 		UPDATE task_bids SET status = 'accepted', updated_at = CURRENT_TIMESTAMP
@@ -155,13 +307,17 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	}
 
 	return response.Success(SelectBidderResponse{
-		TaskID:      taskID,
-		ExecutorDID: req.BidderDID,
-		TxHash:      txHash,
-		Status:      "accepted",
+		TaskID:       taskID,
+		ExecutorDID:  req.BidderDID,
+		PayoutTo:     executorAddress,
+		RewardAmount: rewardAmount,
+		RefundTxHash: refundTxHash,
+		Team:         team,
+		TxHash:       txHash,
+		Status:       "accepted",
 	})
 }
 
 func main() {
-	lambda.Start(handler)
+	lambda.Start(idempotency.Wrap(handler))
 }
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/blockchain"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/idempotency"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+// SetPayoutAddressRequest is a SIWE message signed by the payout address
+type SetPayoutAddressRequest struct {
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+}
+
+type SetPayoutAddressResponse struct {
+	PayoutAddress   string    `json:"payout_address"`
+	PreviousAddress *string   `json:"previous_address,omitempty"`
+	VerifiedAt      time.Time `json:"verified_at"`
+}
+
+var (
+	// siweDomain is SIWE_DOMAIN, checked at startup
+	siweDomain string
+	// siweChainID is the chain registry's default chain
+	siweChainID int64
+)
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	var req SetPayoutAddressRequest
+	if err := json.Unmarshal([]byte(request.Body), &req); err != nil {
+		return response.Error(400, "Invalid request body")
+	}
+
+	if req.Message == "" || req.Signature == "" {
+		return response.Error(400, "Missing message or signature")
+	}
+
+	// The message must be a fresh SIWE proof naming this user
+	msg, err := auth.ParseSIWEMessage(req.Message)
+	if err != nil {
+		return response.Error(400, fmt.Sprintf("Invalid message: %v", err))
+	}
+
+	if msg.Statement != auth.PayoutStatement(claims.DID) {
+		return response.Error(400, fmt.Sprintf("Invalid message: statement must be %q", auth.PayoutStatement(claims.DID)))
+	}
+
+	if err := msg.Validate(siweDomain, siweChainID, time.Now()); err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid message: %v", err))
+	}
+
+	if err := auth.VerifySignature(req.Message, req.Signature, msg.Address); err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid signature: %v", err))
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return response.RetryableError(500, "Failed to start transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	// Nonce is consumed only after the signature checks out
+	err = auth.ConsumeNonce(ctx, tx, msg.Nonce, msg.Address)
+	if errors.Is(err, auth.ErrInvalidNonce) {
+		return response.Error(401, "Invalid or expired nonce")
+	}
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Failed to verify nonce: %v", err))
+	}
+
+	var ethAddress string
+	var previous *string
+	err = tx.QueryRow(ctx, `
+		SELECT eth_address, payout_address FROM users WHERE did = $1 FOR UPDATE
+	`, claims.DID).Scan(&ethAddress, &previous)
+	if err != nil {
+		return response.Error(404, "User not found")
+	}
+
+	payoutAddress := msg.Address.Hex()
+	if strings.EqualFold(payoutAddress, ethAddress) {
+		return response.Error(400, "Address is already your wallet address; remove the payout address instead")
+	}
+
+	// Re-verifying the current address refreshes verified_at and is logged too
+	var verifiedAt time.Time
+	err = tx.QueryRow(ctx, `
+		UPDATE users SET payout_address = $1, payout_address_verified_at = CURRENT_TIMESTAMP
+		WHERE did = $2
+		RETURNING payout_address_verified_at
+	`, payoutAddress, claims.DID).Scan(&verifiedAt)
+	if err != nil {
+		return response.RetryableError(500, "Failed to update payout address")
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO payout_address_changes (user_did, old_address, new_address, message, signature, source_ip, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, claims.DID, previous, payoutAddress, req.Message, req.Signature,
+		request.RequestContext.Identity.SourceIP, request.RequestContext.Identity.UserAgent)
+	if err != nil {
+		return response.RetryableError(500, "Failed to record payout address change")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return response.Error(500, "Failed to commit transaction")
+	}
+
+	return response.Success(SetPayoutAddressResponse{
+		PayoutAddress:   payoutAddress,
+		PreviousAddress: previous,
+		VerifiedAt:      verifiedAt,
+	})
+}
+
+func main() {
+	// Refuse to start without a domain to check SIWE messages against
+	domain, err := auth.SIWEDomain()
+	if err != nil {
+		log.Fatal(err)
+	}
+	siweDomain = domain
+
+	registry, err := blockchain.LoadRegistry()
+	if err != nil {
+		log.Fatal(err)
+	}
+	siweChainID = registry.DefaultChainID
+
+	lambda.Start(idempotency.Wrap(handler))
+}
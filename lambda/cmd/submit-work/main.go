@@ -9,8 +9,10 @@ import (
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/x-zero/xz-wallet/pkg/auth"
 	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/idempotency"
 	"github.com/x-zero/xz-wallet/pkg/models"
 	"github.com/x-zero/xz-wallet/pkg/response"
+	"github.com/x-zero/xz-wallet/pkg/taskgraph"
 )
 
 type SubmitWorkRequest struct {
@@ -25,8 +27,8 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 			StatusCode: 200,
 			Headers: map[string]string{
 				"Access-Control-Allow-Origin":  "*",
-				"Access-Control-Allow-Headers": "Content-Type,Authorization",
-				"Access-Control-Allow-Methods": "POST,OPTIONS",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
 			},
 		}, nil
 	}
@@ -56,7 +58,7 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	}
 
 	if err := db.InitDB(); err != nil {
-		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+		return response.RetryableError(500, fmt.Sprintf("Database error: %v", err))
 	}
 
 	pool := db.GetPool()
@@ -86,6 +88,15 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 			return response.Error(400, "Can only submit design when task is accepted")
 		}
 		newStatus = models.TaskStatusDesignSubmitted
+
+		// A directly assigned task skips bidding, so its prerequisites gate the first submission
+		unmet, err := taskgraph.UnmetDependencies(ctx, pool, taskID)
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Failed to check dependencies: %v", err))
+		}
+		if unmet > 0 {
+			return response.Error(400, fmt.Sprintf("Task is waiting on %d unfinished prerequisite task(s)", unmet))
+		}
 	case "implementation":
 		if task.Status != models.TaskStatusDesignApproved {
 			return response.Error(400, "Can only submit implementation after design is approved")
@@ -103,19 +114,21 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	// Start transaction
 	tx, err := pool.Begin(ctx)
 	if err != nil {
-		return response.Error(500, "Failed to start transaction")
+		return response.RetryableError(500, "Failed to start transaction")
 	}
 	defer tx.Rollback(ctx)
 
-	// Insert submission record
+	// Insert submission record as the milestone's next revision
 	var submissionID string
+	var revision int
 	err = tx.QueryRow(ctx, `
-		INSERT INTO task_submissions (task_id, submission_type, content, file_urls, status)
-		VALUES ($1, $2, $3, $4, 'pending')
-		RETURNING submission_id
-	`, taskID, req.SubmissionType, req.Content, req.FileURLs).Scan(&submissionID)
+		INSERT INTO task_submissions (task_id, submission_type, revision, content, file_urls, status)
+		SELECT $1, $2, COALESCE(MAX(revision), 0) + 1, $3, $4, 'pending'
+		FROM task_submissions WHERE task_id = $1 AND submission_type = $2
+		RETURNING submission_id, revision
+	`, taskID, req.SubmissionType, req.Content, req.FileURLs).Scan(&submissionID, &revision)
 	if err != nil {
-		return response.Error(500, fmt.Sprintf("Failed to create submission: %v", err))
+		return response.RetryableError(500, fmt.Sprintf("Failed to create submission: %v", err))
 	}
 
 	// Update task status
@@ -125,7 +138,7 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		WHERE task_id = $2
 	`, newStatus, taskID)
 	if err != nil {
-		return response.Error(500, fmt.Sprintf("Failed to update task status: %v", err))
+		return response.RetryableError(500, fmt.Sprintf("Failed to update task status: %v", err))
 	}
 
 	// Commit transaction
@@ -136,10 +149,11 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	return response.Success(map[string]interface{}{
 		"message":       "Work submitted successfully",
 		"submission_id": submissionID,
+		"revision":      revision,
 		"new_status":    newStatus,
 	})
 }
 
 func main() {
-	lambda.Start(handler)
+	lambda.Start(idempotency.Wrap(handler))
 }
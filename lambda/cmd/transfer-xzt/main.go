@@ -0,0 +1,383 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v5"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/blockchain"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/idempotency"
+	"github.com/x-zero/xz-wallet/pkg/models"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+type TransferRequest struct {
+	ToDID     string                `json:"to_did,omitempty"`
+	ToAddress string                `json:"to_address,omitempty"`
+	Amount    string                `json:"amount"`
+	Permit    *models.PermitRequest `json:"permit,omitempty"`
+}
+
+type TransferResponse struct {
+	TxID   string `json:"tx_id"`
+	TxHash string `json:"tx_hash"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount string `json:"amount"`
+	Status string `json:"status"`
+}
+
+// transferLimits are the backend-enforced limits for regular transfers (not task payments)
+type transferLimits struct {
+	MaxPerTx        *big.Int // Individual wallets, per transaction
+	DailyLimit      *big.Int // Individual wallets, rolling 24 hours
+	SystemWallet    common.Address
+	SystemMaxBps    int64 // System wallet, share of its balance per transaction
+	HasSystemWallet bool
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	var req TransferRequest
+	if err := json.Unmarshal([]byte(request.Body), &req); err != nil {
+		return response.Error(400, "Invalid request body")
+	}
+
+	if req.Amount == "" || (req.ToDID == "" && req.ToAddress == "") {
+		return response.Error(400, "Missing amount or recipient")
+	}
+
+	amountWei, err := blockchain.ToWei(req.Amount)
+	if err != nil || amountWei.Sign() <= 0 {
+		return response.Error(400, "Invalid amount")
+	}
+
+	limits, err := loadLimits()
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Configuration error: %v", err))
+	}
+
+	// Initialize
+	if err := db.InitDB(); err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Database error: %v", err))
+	}
+	client, err := blockchain.InitClient()
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Blockchain error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	// Get sender's eth_address
+	var fromAddress string
+	err = pool.QueryRow(ctx, "SELECT eth_address FROM users WHERE did = $1", claims.DID).Scan(&fromAddress)
+	if err != nil {
+		return response.Error(404, "User not found")
+	}
+	from := common.HexToAddress(fromAddress)
+
+	// Resolve recipient by DID or address
+	var to common.Address
+	if req.ToDID != "" {
+		var toAddress string
+		err = pool.QueryRow(ctx, "SELECT eth_address FROM users WHERE did = $1", req.ToDID).Scan(&toAddress)
+		if err != nil {
+			return response.Error(404, "Recipient not found")
+		}
+		to = common.HexToAddress(toAddress)
+	} else {
+		if !common.IsHexAddress(req.ToAddress) {
+			return response.Error(400, "Invalid to_address")
+		}
+		to = common.HexToAddress(req.ToAddress)
+	}
+
+	if to == from {
+		return response.Error(400, "Cannot transfer to yourself")
+	}
+
+	// Balance and per-transaction limits
+	balance, err := client.GetBalance(from.Hex())
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Failed to get balance: %v", err))
+	}
+	if balance.Cmp(amountWei) < 0 {
+		return response.Error(400, fmt.Sprintf("Insufficient XZT balance. Available: %s XZT", blockchain.FromWei(balance, 2)))
+	}
+
+	isSystemWallet := limits.HasSystemWallet && from == limits.SystemWallet
+	if isSystemWallet {
+		maxAmount := new(big.Int).Mul(balance, big.NewInt(limits.SystemMaxBps))
+		maxAmount.Div(maxAmount, big.NewInt(10000))
+		if amountWei.Cmp(maxAmount) > 0 {
+			return response.Error(400, fmt.Sprintf("System wallet transfers are limited to %d%% of balance (%s XZT)",
+				limits.SystemMaxBps/100, blockchain.FromWei(maxAmount, 2)))
+		}
+	} else if amountWei.Cmp(limits.MaxPerTx) > 0 {
+		return response.Error(400, fmt.Sprintf("Amount exceeds the per-transaction limit of %s XZT", blockchain.FromWei(limits.MaxPerTx, 2)))
+	}
+
+	// Pick how the transfer is signed
+	provider, err := client.NewSignerProviderFromEnv()
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Signer error: %v", err))
+	}
+	signer, err := provider.SignerFor(ctx, claims.DID, from)
+	if err != nil && !errors.Is(err, blockchain.ErrNoSigner) {
+		return response.RetryableError(500, fmt.Sprintf("Signer error: %v", err))
+	}
+	var permit *blockchain.PermitSignature
+	if signer == nil {
+		if req.Permit == nil {
+			return response.Error(400, "Please sign a permit for the admin wallet to send this transfer")
+		}
+		permit, err = blockchain.ParsePermit(req.Permit.Deadline, req.Permit.V, req.Permit.R, req.Permit.S)
+		if err != nil {
+			return response.Error(400, fmt.Sprintf("Invalid permit: %v", err))
+		}
+	}
+
+	amountStr := blockchain.FromWei(amountWei, 8)
+
+	// Check the daily limit and record the pending transfer atomically,
+	// so concurrent requests cannot both slip under the limit
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return response.RetryableError(500, "Failed to start transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", strings.ToLower(from.Hex()))
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Failed to lock wallet: %v", err))
+	}
+
+	// Settle transfers whose receipt had not arrived; until then they count
+	// toward the daily limit
+	if err := settleSubmitted(ctx, tx, client, from); err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Failed to settle submitted transfers: %v", err))
+	}
+
+	if !isSystemWallet {
+		var sentToday string
+		err = tx.QueryRow(ctx, `
+			SELECT COALESCE(SUM(amount), 0)::text FROM xzt_transactions
+			WHERE tx_type = 'transfer'
+			  AND LOWER(from_address) = $1
+			  AND status IN ('pending', 'submitted', 'confirmed')
+			  AND created_at > NOW() - INTERVAL '24 hours'
+		`, strings.ToLower(from.Hex())).Scan(&sentToday)
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Failed to check daily limit: %v", err))
+		}
+		sentTodayWei, err := blockchain.ToWei(sentToday)
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Failed to check daily limit: %v", err))
+		}
+		if new(big.Int).Add(sentTodayWei, amountWei).Cmp(limits.DailyLimit) > 0 {
+			remaining := new(big.Int).Sub(limits.DailyLimit, sentTodayWei)
+			if remaining.Sign() < 0 {
+				remaining.SetInt64(0)
+			}
+			return response.Error(400, fmt.Sprintf("Amount exceeds the daily limit of %s XZT. Remaining today: %s XZT",
+				blockchain.FromWei(limits.DailyLimit, 2), blockchain.FromWei(remaining, 2)))
+		}
+	}
+
+	var txID string
+	err = tx.QueryRow(ctx, `
+		INSERT INTO xzt_transactions (from_address, to_address, amount, tx_type, status)
+		VALUES ($1, $2, $3, 'transfer', 'pending')
+		RETURNING tx_id
+	`, from.Hex(), to.Hex(), amountStr).Scan(&txID)
+	if err != nil {
+		return response.RetryableError(500, fmt.Sprintf("Failed to record transfer: %v", err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return response.Error(500, "Failed to commit transaction")
+	}
+
+	// Send on chain
+	var result *blockchain.TransferResult
+	if signer != nil {
+		result, err = client.TransferAs(ctx, signer, to, amountWei)
+	} else {
+		result, err = client.TransferWithPermit(ctx, from, to, amountWei, permit)
+	}
+	if errors.Is(err, blockchain.ErrUnconfirmed) {
+		// Sent but not mined yet: keep it counted until its receipt settles it
+		_, updateErr := pool.Exec(ctx, `
+			UPDATE xzt_transactions SET tx_hash = $1, status = 'submitted' WHERE tx_id = $2
+		`, result.TxHash, txID)
+		if updateErr != nil {
+			fmt.Printf("CRITICAL: Transfer %s sent (tx=%s) but failed to mark it submitted: %v\n", txID, result.TxHash, updateErr)
+		}
+		return response.Success(TransferResponse{
+			TxID:   txID,
+			TxHash: result.TxHash,
+			From:   from.Hex(),
+			To:     to.Hex(),
+			Amount: amountStr,
+			Status: "submitted",
+		})
+	}
+	if err != nil {
+		_, updateErr := pool.Exec(ctx, `
+			UPDATE xzt_transactions SET status = 'failed' WHERE tx_id = $1
+		`, txID)
+		if updateErr != nil {
+			fmt.Printf("Failed to mark transfer %s as failed: %v\n", txID, updateErr)
+		}
+		return response.Error(500, fmt.Sprintf("Failed to transfer: %v", err))
+	}
+
+	_, err = pool.Exec(ctx, `
+		UPDATE xzt_transactions
+		SET tx_hash = $1, block_number = $2, status = 'confirmed', confirmed_at = NOW()
+		WHERE tx_id = $3
+	`, result.TxHash, result.BlockNumber, txID)
+	if err != nil {
+		// Transfer went through; only the record is stale
+		fmt.Printf("CRITICAL: Transfer %s confirmed (tx=%s) but failed to update record: %v\n", txID, result.TxHash, err)
+	}
+
+	return response.Success(TransferResponse{
+		TxID:   txID,
+		TxHash: result.TxHash,
+		From:   from.Hex(),
+		To:     to.Hex(),
+		Amount: amountStr,
+		Status: "confirmed",
+	})
+}
+
+// settleSubmitted confirms or fails the wallet's submitted transfers whose
+// receipts have arrived. Call it holding the wallet lock.
+func settleSubmitted(ctx context.Context, tx pgx.Tx, client *blockchain.BlockchainClient, from common.Address) error {
+	rows, err := tx.Query(ctx, `
+		SELECT tx_id, tx_hash FROM xzt_transactions
+		WHERE tx_type = 'transfer' AND status = 'submitted' AND LOWER(from_address) = $1
+	`, strings.ToLower(from.Hex()))
+	if err != nil {
+		return err
+	}
+	submitted := make(map[string]string)
+	for rows.Next() {
+		var txID, txHash string
+		if err := rows.Scan(&txID, &txHash); err != nil {
+			rows.Close()
+			return err
+		}
+		submitted[txID] = txHash
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for txID, txHash := range submitted {
+		result, err := client.TransferOutcome(ctx, txHash)
+		switch {
+		case errors.Is(err, blockchain.ErrUnconfirmed):
+			continue
+		case errors.Is(err, blockchain.ErrTransferReverted):
+			_, err = tx.Exec(ctx, `UPDATE xzt_transactions SET status = 'failed' WHERE tx_id = $1`, txID)
+		case err != nil:
+			return err
+		default:
+			_, err = tx.Exec(ctx, `
+				UPDATE xzt_transactions SET block_number = $1, status = 'confirmed', confirmed_at = NOW()
+				WHERE tx_id = $2
+			`, result.BlockNumber, txID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadLimits reads TRANSFER_MAX_PER_TX (default 1000), TRANSFER_DAILY_LIMIT
+// (default 5000), SYSTEM_WALLET_ADDRESS and TRANSFER_SYSTEM_MAX_BPS (default 3000)
+func loadLimits() (*transferLimits, error) {
+	maxPerTx, err := amountEnv("TRANSFER_MAX_PER_TX", "1000")
+	if err != nil {
+		return nil, err
+	}
+	dailyLimit, err := amountEnv("TRANSFER_DAILY_LIMIT", "5000")
+	if err != nil {
+		return nil, err
+	}
+
+	limits := &transferLimits{
+		MaxPerTx:     maxPerTx,
+		DailyLimit:   dailyLimit,
+		SystemMaxBps: 3000,
+	}
+
+	if value := os.Getenv("TRANSFER_SYSTEM_MAX_BPS"); value != "" {
+		bps, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || bps <= 0 || bps > 10000 {
+			return nil, fmt.Errorf("invalid TRANSFER_SYSTEM_MAX_BPS: %s", value)
+		}
+		limits.SystemMaxBps = bps
+	}
+
+	if value := os.Getenv("SYSTEM_WALLET_ADDRESS"); value != "" {
+		if !common.IsHexAddress(value) {
+			return nil, fmt.Errorf("invalid SYSTEM_WALLET_ADDRESS: %s", value)
+		}
+		limits.SystemWallet = common.HexToAddress(value)
+		limits.HasSystemWallet = true
+	}
+
+	return limits, nil
+}
+
+func amountEnv(name, def string) (*big.Int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		value = def
+	}
+	wei, err := blockchain.ToWei(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return wei, nil
+}
+
+func main() {
+	lambda.Start(idempotency.Wrap(handler))
+}
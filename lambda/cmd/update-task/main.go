@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/jackc/pgx/v5"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/models"
+	"github.com/x-zero/xz-wallet/pkg/response"
+	"github.com/x-zero/xz-wallet/pkg/taskgraph"
+)
+
+// UpdateTaskRequest holds the fields to change; omitted fields are kept
+type UpdateTaskRequest struct {
+	TaskName           *string   `json:"task_name,omitempty"`
+	TaskDescription    *string   `json:"task_description,omitempty"`
+	AcceptanceCriteria *string   `json:"acceptance_criteria,omitempty"`
+	ProfessionTags     *[]string `json:"profession_tags,omitempty"`
+	ParentTaskID       *string   `json:"parent_task_id,omitempty"` // Empty string detaches a subtask
+}
+
+type UpdateTaskResponse struct {
+	TaskID  string                        `json:"task_id"`
+	EditID  string                        `json:"edit_id,omitempty"`
+	Changes map[string]models.FieldChange `json:"changes"`
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	taskID := request.PathParameters["id"]
+	if taskID == "" {
+		return response.Error(400, "Missing task ID")
+	}
+
+	var req UpdateTaskRequest
+	if err := json.Unmarshal([]byte(request.Body), &req); err != nil {
+		return response.Error(400, "Invalid request body")
+	}
+	if req.TaskName != nil && strings.TrimSpace(*req.TaskName) == "" {
+		return response.Error(400, "task_name cannot be empty")
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return response.Error(500, "Failed to start transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	var task models.Task
+	err = tx.QueryRow(ctx, `
+		SELECT project_id, creator_did, status, task_name, task_description, acceptance_criteria, profession_tags,
+		       parent_task_id
+		FROM tasks WHERE task_id = $1 FOR UPDATE
+	`, taskID).Scan(&task.ProjectID, &task.CreatorDID, &task.Status, &task.TaskName, &task.TaskDescription,
+		&task.AcceptanceCriteria, &task.ProfessionTags, &task.ParentTaskID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return response.Error(404, "Task not found")
+	}
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to load task: %v", err))
+	}
+
+	if task.CreatorDID != claims.DID {
+		return response.Error(403, "Only creator can edit the task")
+	}
+
+	// Once an executor is chosen the task is what they agreed to
+	if task.Status != models.TaskStatusBidding && task.Status != models.TaskStatusBlocked {
+		return response.Error(400, "Task can only be edited while bidding")
+	}
+
+	changes := make(map[string]models.FieldChange)
+	if req.TaskName != nil && *req.TaskName != task.TaskName {
+		changes["task_name"] = models.FieldChange{Old: task.TaskName, New: *req.TaskName}
+		task.TaskName = *req.TaskName
+	}
+	if req.TaskDescription != nil && *req.TaskDescription != task.TaskDescription {
+		changes["task_description"] = models.FieldChange{Old: task.TaskDescription, New: *req.TaskDescription}
+		task.TaskDescription = *req.TaskDescription
+	}
+	if req.AcceptanceCriteria != nil && *req.AcceptanceCriteria != task.AcceptanceCriteria {
+		changes["acceptance_criteria"] = models.FieldChange{Old: task.AcceptanceCriteria, New: *req.AcceptanceCriteria}
+		task.AcceptanceCriteria = *req.AcceptanceCriteria
+	}
+	if req.ProfessionTags != nil && !slices.Equal(*req.ProfessionTags, task.ProfessionTags) {
+		tags := *req.ProfessionTags
+		if tags == nil {
+			tags = []string{}
+		}
+		changes["profession_tags"] = models.FieldChange{Old: task.ProfessionTags, New: tags}
+		task.ProfessionTags = tags
+	}
+	if req.ParentTaskID != nil {
+		oldParent := ""
+		if task.ParentTaskID != nil {
+			oldParent = *task.ParentTaskID
+		}
+		newParent := *req.ParentTaskID
+		if newParent != oldParent {
+			if newParent != "" {
+				var projectID, creatorDID, status string
+				err = tx.QueryRow(ctx, `
+					SELECT project_id, creator_did, status FROM tasks WHERE task_id = $1
+				`, newParent).Scan(&projectID, &creatorDID, &status)
+				if err != nil {
+					return response.Error(404, "Parent task not found")
+				}
+				if projectID != task.ProjectID || creatorDID != claims.DID || status == models.TaskStatusCancelled {
+					return response.Error(400, "Parent task must be an open task you created in the same project")
+				}
+				if err := taskgraph.LockProject(ctx, tx, task.ProjectID); err != nil {
+					return response.Error(500, fmt.Sprintf("Failed to lock project: %v", err))
+				}
+				cycle, err := taskgraph.ParentCycle(ctx, tx, taskID, newParent)
+				if err != nil {
+					return response.Error(500, fmt.Sprintf("Failed to check subtasks: %v", err))
+				}
+				if cycle {
+					return response.Error(400, "Parent task is a subtask of this task")
+				}
+			}
+			changes["parent_task_id"] = models.FieldChange{Old: task.ParentTaskID, New: nullable(newParent)}
+			task.ParentTaskID = nullable(newParent)
+		}
+	}
+
+	// Nothing changed, nothing to record
+	if len(changes) == 0 {
+		return response.Success(UpdateTaskResponse{TaskID: taskID, Changes: changes})
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE tasks
+		SET task_name = $1, task_description = $2, acceptance_criteria = $3, profession_tags = $4,
+		    parent_task_id = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE task_id = $6
+	`, task.TaskName, task.TaskDescription, task.AcceptanceCriteria, task.ProfessionTags, task.ParentTaskID, taskID)
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to update task: %v", err))
+	}
+
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return response.Error(500, "Failed to encode changes")
+	}
+	var editID string
+	err = tx.QueryRow(ctx, `
+		INSERT INTO task_edits (task_id, editor_did, changes) VALUES ($1, $2, $3::jsonb)
+		RETURNING edit_id
+	`, taskID, claims.DID, string(changesJSON)).Scan(&editID)
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to record edit: %v", err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return response.Error(500, "Failed to commit transaction")
+	}
+
+	return response.Success(UpdateTaskResponse{
+		TaskID:  taskID,
+		EditID:  editID,
+		Changes: changes,
+	})
+}
+
+// nullable maps an empty ID to NULL
+func nullable(id string) *string {
+	if id == "" {
+		return nil
+	}
+	return &id
+}
+
+func main() {
+	lambda.Start(handler)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/jackc/pgx/v5"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/bids"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/models"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+type WithdrawBidResponse struct {
+	BidID  string `json:"bid_id"`
+	Status string `json:"status"`
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": response.AllowHeaders,
+				"Access-Control-Allow-Methods": response.AllowMethods,
+			},
+		}, nil
+	}
+
+	// Validate JWT
+	authHeader := request.Headers["Authorization"]
+	if authHeader == "" {
+		authHeader = request.Headers["authorization"]
+	}
+	claims, err := auth.ValidateToken(authHeader)
+	if err != nil {
+		return response.Error(401, fmt.Sprintf("Invalid token: %v", err))
+	}
+
+	taskID := request.PathParameters["id"]
+	if taskID == "" {
+		return response.Error(400, "Missing task ID")
+	}
+
+	if err := db.InitDB(); err != nil {
+		return response.Error(500, fmt.Sprintf("Database error: %v", err))
+	}
+
+	pool := db.GetPool()
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return response.Error(500, "Failed to start transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the task before the bid, in the order select-bidder takes them
+	if _, err := tx.Exec(ctx, "SELECT 1 FROM tasks WHERE task_id = $1 FOR UPDATE", taskID); err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to lock task: %v", err))
+	}
+
+	var bidID, bidStatus string
+	err = tx.QueryRow(ctx, `
+		SELECT bid_id, status FROM task_bids WHERE task_id = $1 AND bidder_did = $2 FOR UPDATE
+	`, taskID, claims.DID).Scan(&bidID, &bidStatus)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return response.Error(404, "Bid not found")
+	}
+	if err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to load bid: %v", err))
+	}
+
+	// Withdrawing twice is a no-op
+	if bidStatus == models.BidStatusWithdrawn {
+		return response.Success(WithdrawBidResponse{BidID: bidID, Status: bidStatus})
+	}
+	if bidStatus != models.BidStatusPending {
+		return response.Error(400, fmt.Sprintf("Cannot withdraw a bid that is %s", bidStatus))
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE task_bids SET status = 'withdrawn', updated_at = CURRENT_TIMESTAMP
+		WHERE bid_id = $1
+	`, bidID)
+	if err != nil {
+		return response.Error(500, "Failed to withdraw bid")
+	}
+
+	if err := bids.RecordRevision(ctx, tx, bidID, models.BidRevisionWithdrawn); err != nil {
+		return response.Error(500, fmt.Sprintf("Failed to record bid revision: %v", err))
+	}
+
+	// A task left without pending bids goes back to pending
+	if _, err := bids.ReturnToPending(ctx, tx, taskID); err != nil {
+		return response.Error(500, "Failed to update task status")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return response.Error(500, "Failed to commit transaction")
+	}
+
+	return response.Success(WithdrawBidResponse{
+		BidID:  bidID,
+		Status: models.BidStatusWithdrawn,
+	})
+}
+
+func main() {
+	lambda.Start(handler)
+}
@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// JWK is a single JSON Web Key (RSA or Ed25519 only)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+var (
+	jwksMu        sync.Mutex
+	jwksKeys      map[string]interface{}
+	jwksFetchedAt time.Time
+)
+
+// jwksConfigured reports whether JWT_JWKS_URL or JWT_JWKS_FILE is set
+func jwksConfigured() bool {
+	return os.Getenv("JWT_JWKS_URL") != "" || os.Getenv("JWT_JWKS_FILE") != ""
+}
+
+// lookupKey returns the public key for kid, refreshing the JWKS once on a miss
+func lookupKey(kid, alg string) (interface{}, error) {
+	if !jwksConfigured() {
+		return nil, fmt.Errorf("%s tokens are not accepted: no JWKS configured", alg)
+	}
+
+	jwksMu.Lock()
+	defer jwksMu.Unlock()
+
+	if jwksKeys == nil || time.Since(jwksFetchedAt) > jwksCacheTTL() {
+		if err := loadJWKS(); err != nil {
+			return nil, err
+		}
+	}
+
+	key, ok := findKey(kid)
+	if !ok {
+		// Key may have been rotated in since the last fetch
+		if err := loadJWKS(); err != nil {
+			return nil, err
+		}
+		key, ok = findKey(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown key ID: %q", kid)
+		}
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey:
+		if alg != "RS256" {
+			return nil, fmt.Errorf("key %q cannot verify %s", kid, alg)
+		}
+	case ed25519.PublicKey:
+		if alg != "EdDSA" {
+			return nil, fmt.Errorf("key %q cannot verify %s", kid, alg)
+		}
+	}
+
+	return key, nil
+}
+
+// findKey matches kid; a token without kid matches a single-key set
+func findKey(kid string) (interface{}, bool) {
+	if kid == "" && len(jwksKeys) == 1 {
+		for _, key := range jwksKeys {
+			return key, true
+		}
+	}
+	key, ok := jwksKeys[kid]
+	return key, ok
+}
+
+// loadJWKS reads the key set from JWT_JWKS_FILE or JWT_JWKS_URL. Caller holds jwksMu.
+func loadJWKS() error {
+	var data []byte
+	var err error
+
+	if path := os.Getenv("JWT_JWKS_FILE"); path != "" {
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read JWKS file: %w", err)
+		}
+	} else {
+		data, err = fetchJWKS(os.Getenv("JWT_JWKS_URL"))
+		if err != nil {
+			return err
+		}
+	}
+
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	jwksKeys = keys
+	jwksFetchedAt = time.Now()
+	return nil
+}
+
+func fetchJWKS(url string) ([]byte, error) {
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("JWKS endpoint returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	return data, nil
+}
+
+// ParseJWKS decodes a key set into public keys indexed by kid
+func ParseJWKS(data []byte) (map[string]interface{}, error) {
+	var set JWKS
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	keys := make(map[string]interface{})
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		switch jwk.Kty {
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+			if err != nil {
+				return nil, fmt.Errorf("invalid modulus for key %q: %w", jwk.Kid, err)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+			if err != nil {
+				return nil, fmt.Errorf("invalid exponent for key %q: %w", jwk.Kid, err)
+			}
+			keys[jwk.Kid] = &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			}
+		case "OKP":
+			if jwk.Crv != "Ed25519" {
+				continue
+			}
+			x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+			if err != nil || len(x) != ed25519.PublicKeySize {
+				return nil, fmt.Errorf("invalid Ed25519 key %q", jwk.Kid)
+			}
+			keys[jwk.Kid] = ed25519.PublicKey(x)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS contains no usable signing keys")
+	}
+	return keys, nil
+}
+
+// jwksCacheTTL reads JWT_JWKS_CACHE_TTL (default 10m)
+func jwksCacheTTL() time.Duration {
+	if value := os.Getenv("JWT_JWKS_CACHE_TTL"); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return 10 * time.Minute
+}
@@ -1,12 +1,17 @@
 package auth
 
 import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/x-zero/xz-wallet/pkg/db"
 )
 
 // Claims represents JWT claims
@@ -16,44 +21,82 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-// ValidateToken validates JWT token and returns claims
+// ValidateToken validates JWT token and returns claims.
+// Accepts RS256/EdDSA tokens whose kid is in the configured JWKS, and HS256
+// tokens while JWT_SECRET is set (migration path for did-login tokens).
 func ValidateToken(tokenString string) (*Claims, error) {
 	// Remove "Bearer " prefix if present
 	tokenString = strings.TrimPrefix(tokenString, "Bearer ")
 
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
+	if os.Getenv("JWT_SECRET") == "" && !jwksConfigured() {
 		return nil, fmt.Errorf("JWT_SECRET not set")
 	}
 
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(secret), nil
-	})
-
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keyFunc, parserOptions()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
 	}
 
-	return nil, fmt.Errorf("invalid token")
+	// Only tokens carrying a jti can be revoked
+	if claims.ID != "" && os.Getenv("JWT_REVOCATION_CHECK") != "false" {
+		if err := db.InitDB(); err != nil {
+			return nil, fmt.Errorf("failed to check revocation: %w", err)
+		}
+		revoked, err := IsRevoked(context.Background(), db.GetPool(), claims.ID)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, fmt.Errorf("token has been revoked")
+		}
+	}
+
+	return claims, nil
 }
 
-// GenerateToken generates a new JWT token
-func GenerateToken(did, username string) (string, error) {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return "", fmt.Errorf("JWT_SECRET not set")
+// keyFunc selects the verification key for the token's algorithm
+func keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		secret := os.Getenv("JWT_SECRET")
+		if secret == "" {
+			return nil, fmt.Errorf("HS256 tokens are not accepted")
+		}
+		return []byte(secret), nil
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodEd25519:
+		kid, _ := token.Header["kid"].(string)
+		return lookupKey(kid, token.Method.Alg())
+	default:
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 	}
+}
+
+// parserOptions builds the validation options from the environment
+func parserOptions() []jwt.ParserOption {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"HS256", "RS256", "EdDSA"}),
+		jwt.WithExpirationRequired(),
+	}
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		opts = append(opts, jwt.WithAudience(audience))
+	}
+	return opts
+}
 
+// GenerateToken generates a new short-lived access token.
+// Signs with JWT_SIGNING_KEY (RS256/EdDSA) when set, otherwise HS256 with JWT_SECRET.
+func GenerateToken(did, username string) (string, error) {
 	expiryStr := os.Getenv("JWT_EXPIRY")
 	if expiryStr == "" {
-		expiryStr = "168h" // Default 7 days
+		expiryStr = "15m" // Default 15 minutes, renewed with a refresh token
 	}
 
 	expiry, err := time.ParseDuration(expiryStr)
@@ -61,20 +104,81 @@ func GenerateToken(did, username string) (string, error) {
 		return "", fmt.Errorf("invalid JWT_EXPIRY: %w", err)
 	}
 
+	jti, err := randomHex(16)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token ID: %w", err)
+	}
+
+	now := time.Now()
 	claims := Claims{
 		DID:      did,
 		Username: username,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        jti,
+			Subject:   did,
+			Issuer:    os.Getenv("JWT_ISSUER"),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		claims.Audience = jwt.ClaimStrings{audience}
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(secret))
+	method, key, kid, err := signingKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	tokenString, err := token.SignedString(key)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
 
 	return tokenString, nil
 }
+
+// signingKey returns the method and key used by GenerateToken
+func signingKey() (jwt.SigningMethod, interface{}, string, error) {
+	pemData := os.Getenv("JWT_SIGNING_KEY")
+	if pemData == "" {
+		if path := os.Getenv("JWT_SIGNING_KEY_FILE"); path != "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, nil, "", fmt.Errorf("failed to read JWT_SIGNING_KEY_FILE: %w", err)
+			}
+			pemData = string(data)
+		}
+	}
+
+	if pemData == "" {
+		secret := os.Getenv("JWT_SECRET")
+		if secret == "" {
+			return nil, nil, "", fmt.Errorf("JWT_SECRET not set")
+		}
+		return jwt.SigningMethodHS256, []byte(secret), "", nil
+	}
+
+	kid := os.Getenv("JWT_SIGNING_KID")
+
+	if rsaKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(pemData)); err == nil {
+		return jwt.SigningMethodRS256, rsaKey, kid, nil
+	}
+	edKey, err := jwt.ParseEdPrivateKeyFromPEM([]byte(pemData))
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("JWT_SIGNING_KEY is neither an RSA nor an Ed25519 private key")
+	}
+	return jwt.SigningMethodEdDSA, edKey.(crypto.Signer), kid, nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
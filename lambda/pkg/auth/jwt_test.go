@@ -0,0 +1,228 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// useJWKS writes the public keys to a JWKS file and points JWT_JWKS_FILE at it
+func useJWKS(t *testing.T, keys ...JWK) {
+	t.Helper()
+
+	data, err := json.Marshal(JWKS{Keys: keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("JWT_JWKS_FILE", path)
+
+	// Drop keys cached by an earlier test
+	jwksMu.Lock()
+	jwksKeys = nil
+	jwksMu.Unlock()
+	t.Cleanup(func() {
+		jwksMu.Lock()
+		jwksKeys = nil
+		jwksMu.Unlock()
+	})
+}
+
+// useSigningKey makes GenerateToken sign with key under kid
+func useSigningKey(t *testing.T, key interface{}, kid string) {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("JWT_SIGNING_KEY", string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})))
+	t.Setenv("JWT_SIGNING_KID", kid)
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) JWK {
+	return JWK{
+		Kty: "RSA",
+		Kid: kid,
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ed25519JWK(kid string, key ed25519.PublicKey) JWK {
+	return JWK{Kty: "OKP", Kid: kid, Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(key)}
+}
+
+// signHS256 signs claims with secret, bypassing GenerateToken's defaults
+func signHS256(t *testing.T, claims Claims, secret string) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestValidateTokenHS256(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("JWT_REVOCATION_CHECK", "false")
+
+	token, err := GenerateToken("did:example:alice", "alice")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	claims, err := ValidateToken("Bearer " + token)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if claims.DID != "did:example:alice" || claims.Username != "alice" || claims.ID == "" {
+		t.Fatalf("claims = %+v", claims)
+	}
+
+	now := time.Now()
+	cases := []struct {
+		name  string
+		token string
+	}{
+		{"wrong secret", signHS256(t, Claims{DID: "did:example:alice", RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		}}, "other-secret")},
+		{"expired", signHS256(t, Claims{DID: "did:example:alice", RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(-time.Minute)),
+		}}, "test-secret")},
+		{"no expiry", signHS256(t, Claims{DID: "did:example:alice"}, "test-secret")},
+		{"garbage", "not-a-token"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ValidateToken(tc.token); err == nil {
+				t.Fatal("ValidateToken accepted the token")
+			}
+		})
+	}
+}
+
+func TestValidateTokenIssuerAndAudience(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("JWT_REVOCATION_CHECK", "false")
+	t.Setenv("JWT_ISSUER", "xz-wallet")
+	t.Setenv("JWT_AUDIENCE", "xz-api")
+
+	token, err := GenerateToken("did:example:alice", "alice")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	if _, err := ValidateToken(token); err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+
+	t.Setenv("JWT_AUDIENCE", "other-api")
+	if _, err := ValidateToken(token); err == nil {
+		t.Fatal("ValidateToken accepted a token for another audience")
+	}
+}
+
+func TestValidateTokenJWKS(t *testing.T) {
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("JWT_REVOCATION_CHECK", "false")
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	useJWKS(t, rsaJWK("rsa-1", &rsaKey.PublicKey), ed25519JWK("ed-1", edPublic))
+
+	cases := []struct {
+		name string
+		key  interface{}
+		kid  string
+		ok   bool
+	}{
+		{"RS256", rsaKey, "rsa-1", true},
+		{"EdDSA", edKey, "ed-1", true},
+		{"unknown kid", rsaKey, "rsa-2", false},
+		{"RS256 under an Ed25519 kid", rsaKey, "ed-1", false},
+		{"EdDSA under an RSA kid", edKey, "rsa-1", false},
+		{"missing kid with several keys", rsaKey, "", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			useSigningKey(t, tc.key, tc.kid)
+			token, err := GenerateToken("did:example:alice", "alice")
+			if err != nil {
+				t.Fatalf("GenerateToken: %v", err)
+			}
+			claims, err := ValidateToken(token)
+			if tc.ok && (err != nil || claims.DID != "did:example:alice") {
+				t.Fatalf("ValidateToken = %+v, %v; want the token accepted", claims, err)
+			}
+			if !tc.ok && err == nil {
+				t.Fatal("ValidateToken accepted the token")
+			}
+		})
+	}
+}
+
+func TestValidateTokenJWKSRejectsOtherKey(t *testing.T) {
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("JWT_REVOCATION_CHECK", "false")
+
+	published, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attacker, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	useJWKS(t, rsaJWK("rsa-1", &published.PublicKey))
+	useSigningKey(t, attacker, "rsa-1")
+
+	token, err := GenerateToken("did:example:alice", "alice")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	if _, err := ValidateToken(token); err == nil {
+		t.Fatal("ValidateToken accepted a token signed with an unpublished key")
+	}
+}
+
+func TestValidateTokenRejectsHS256WithoutSecret(t *testing.T) {
+	t.Setenv("JWT_REVOCATION_CHECK", "false")
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	useJWKS(t, rsaJWK("rsa-1", &rsaKey.PublicKey))
+
+	// Once JWT_SECRET is removed, leftover HS256 tokens stop working
+	t.Setenv("JWT_SECRET", "test-secret")
+	token, err := GenerateToken("did:example:alice", "alice")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	t.Setenv("JWT_SECRET", "")
+	if _, err := ValidateToken(token); err == nil {
+		t.Fatal("ValidateToken accepted an HS256 token without JWT_SECRET")
+	}
+}
@@ -0,0 +1,16 @@
+package auth
+
+import "strings"
+
+// payoutStatementPrefix starts the SIWE statement a payout address signs
+const payoutStatementPrefix = "Receive XZ Wallet task payouts at this address for "
+
+// PayoutStatement is the SIWE statement that links a payout address to a user
+func PayoutStatement(did string) string {
+	return payoutStatementPrefix + did
+}
+
+// IsPayoutStatement reports whether a SIWE statement is a payout address proof
+func IsPayoutStatement(statement string) bool {
+	return strings.HasPrefix(statement, payoutStatementPrefix)
+}
@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/x-zero/xz-wallet/pkg/db"
+)
+
+// ErrInvalidRefreshToken is returned for unknown, expired, revoked or reused refresh tokens
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+// TokenPair is an access token plus the refresh token that renews it
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresIn        int64     `json:"expires_in"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// IssueTokenPair issues an access token and starts a new refresh token family
+func IssueTokenPair(ctx context.Context, q db.Querier, did, username string) (*TokenPair, error) {
+	var familyID string
+	if err := q.QueryRow(ctx, "SELECT gen_random_uuid()::text").Scan(&familyID); err != nil {
+		return nil, fmt.Errorf("failed to create token family: %w", err)
+	}
+	return issuePair(ctx, q, did, username, familyID)
+}
+
+// RefreshTokens rotates a refresh token. Presenting an already-used token
+// revokes its whole family, since it means the token was copied. Marking the
+// old token used and storing the new one happen in one transaction, so a
+// failed insert cannot leave the user without a valid refresh token.
+func RefreshTokens(ctx context.Context, conn db.Beginner, refreshToken string) (*TokenPair, error) {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	pair, err := rotate(ctx, tx, refreshToken)
+	if err != nil && !errors.Is(err, ErrInvalidRefreshToken) {
+		return nil, err
+	}
+	// Commit on ErrInvalidRefreshToken too, so a family revoked for reuse stays revoked
+	if cerr := tx.Commit(ctx); cerr != nil {
+		return nil, fmt.Errorf("failed to commit refresh token rotation: %w", cerr)
+	}
+	return pair, err
+}
+
+func rotate(ctx context.Context, tx pgx.Tx, refreshToken string) (*TokenPair, error) {
+	hash := hashToken(refreshToken)
+
+	var did, username, familyID string
+	err := tx.QueryRow(ctx, `
+		UPDATE refresh_tokens
+		SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()
+		RETURNING user_did, username, family_id::text
+	`, hash).Scan(&did, &username, &familyID)
+	if err == nil {
+		return issuePair(ctx, tx, did, username, familyID)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	// Not rotatable: detect reuse of a token that was already exchanged
+	var usedAt *time.Time
+	err = tx.QueryRow(ctx, `
+		SELECT family_id::text, used_at FROM refresh_tokens WHERE token_hash = $1
+	`, hash).Scan(&familyID, &usedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up refresh token: %w", err)
+	}
+
+	if usedAt != nil {
+		if err := RevokeRefreshFamily(ctx, tx, familyID); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, ErrInvalidRefreshToken
+}
+
+// RevokeRefreshToken revokes the family of one of the user's refresh tokens (logout)
+func RevokeRefreshToken(ctx context.Context, q db.Querier, did, refreshToken string) error {
+	_, err := q.Exec(ctx, `
+		UPDATE refresh_tokens
+		SET revoked_at = NOW()
+		WHERE revoked_at IS NULL AND user_did = $2
+		  AND family_id = (SELECT family_id FROM refresh_tokens WHERE token_hash = $1)
+	`, hashToken(refreshToken), did)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	return nil
+}
+
+// RevokeRefreshFamily revokes every token in a refresh token family
+func RevokeRefreshFamily(ctx context.Context, q db.Querier, familyID string) error {
+	_, err := q.Exec(ctx, `
+		UPDATE refresh_tokens SET revoked_at = NOW()
+		WHERE family_id = $1 AND revoked_at IS NULL
+	`, familyID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+	return nil
+}
+
+func issuePair(ctx context.Context, q db.Querier, did, username, familyID string) (*TokenPair, error) {
+	accessToken, err := GenerateToken(did, username)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshTTL, err := refreshTokenTTL()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomHex(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	expiresAt := time.Now().Add(refreshTTL)
+	_, err = q.Exec(ctx, `
+		INSERT INTO refresh_tokens (token_hash, family_id, user_did, username, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, hashToken(refreshToken), familyID, did, username, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	accessTTL, _ := time.ParseDuration(os.Getenv("JWT_EXPIRY"))
+	if accessTTL == 0 {
+		accessTTL = 15 * time.Minute
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(accessTTL.Seconds()),
+		RefreshExpiresAt: expiresAt,
+	}, nil
+}
+
+// refreshTokenTTL reads JWT_REFRESH_EXPIRY (default 30 days)
+func refreshTokenTTL() (time.Duration, error) {
+	value := os.Getenv("JWT_REFRESH_EXPIRY")
+	if value == "" {
+		return 30 * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid JWT_REFRESH_EXPIRY: %w", err)
+	}
+	return d, nil
+}
+
+// hashToken stores refresh tokens as SHA-256 so a database leak cannot replay them
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/x-zero/xz-wallet/pkg/dbtest"
+)
+
+// newRefreshDB opens a test database with one user and HS256 signing
+func newRefreshDB(t *testing.T) pgx.Tx {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("JWT_SIGNING_KEY", "")
+
+	tx := dbtest.Begin(t)
+	if _, err := tx.Exec(context.Background(), `INSERT INTO users (did, username) VALUES ('did:example:alice', 'alice')`); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func issue(t *testing.T, tx pgx.Tx) *TokenPair {
+	t.Helper()
+
+	pair, err := IssueTokenPair(context.Background(), tx, "did:example:alice", "alice")
+	if err != nil {
+		t.Fatalf("IssueTokenPair: %v", err)
+	}
+	return pair
+}
+
+func TestRefreshTokensRotates(t *testing.T) {
+	tx := newRefreshDB(t)
+	ctx := context.Background()
+	first := issue(t, tx)
+
+	second, err := RefreshTokens(ctx, tx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshTokens: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken || second.AccessToken == "" {
+		t.Fatalf("rotation returned %+v", second)
+	}
+
+	// The new token keeps rotating
+	if _, err := RefreshTokens(ctx, tx, second.RefreshToken); err != nil {
+		t.Fatalf("RefreshTokens on the rotated token: %v", err)
+	}
+}
+
+func TestRefreshTokensReuseRevokesFamily(t *testing.T) {
+	tx := newRefreshDB(t)
+	ctx := context.Background()
+	first := issue(t, tx)
+	other := issue(t, tx)
+
+	second, err := RefreshTokens(ctx, tx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshTokens: %v", err)
+	}
+
+	// The spent token was copied: refuse it and revoke everything issued from it
+	if _, err := RefreshTokens(ctx, tx, first.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("reuse err = %v, want ErrInvalidRefreshToken", err)
+	}
+	if _, err := RefreshTokens(ctx, tx, second.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("rotated token after reuse: err = %v, want ErrInvalidRefreshToken", err)
+	}
+
+	// Other sessions of the same user are a different family
+	if _, err := RefreshTokens(ctx, tx, other.RefreshToken); err != nil {
+		t.Fatalf("other family: %v", err)
+	}
+}
+
+func TestRefreshTokensRejectsInvalid(t *testing.T) {
+	tx := newRefreshDB(t)
+	ctx := context.Background()
+
+	expired := issue(t, tx)
+	if _, err := tx.Exec(ctx, `UPDATE refresh_tokens SET expires_at = NOW() - INTERVAL '1 minute' WHERE token_hash = $1`, hashToken(expired.RefreshToken)); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name  string
+		token string
+	}{
+		{"unknown", "not-a-refresh-token"},
+		{"expired", expired.RefreshToken},
+		{"access token", issue(t, tx).AccessToken},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := RefreshTokens(ctx, tx, tc.token); !errors.Is(err, ErrInvalidRefreshToken) {
+				t.Fatalf("err = %v, want ErrInvalidRefreshToken", err)
+			}
+		})
+	}
+}
+
+func TestRefreshTokensRollsBackFailedRotation(t *testing.T) {
+	tx := newRefreshDB(t)
+	ctx := context.Background()
+	pair := issue(t, tx)
+
+	// Storing the new token fails: the old one must stay usable
+	t.Setenv("JWT_REFRESH_EXPIRY", "not-a-duration")
+	if _, err := RefreshTokens(ctx, tx, pair.RefreshToken); err == nil || errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("err = %v, want a configuration error", err)
+	}
+
+	t.Setenv("JWT_REFRESH_EXPIRY", "")
+	if _, err := RefreshTokens(ctx, tx, pair.RefreshToken); err != nil {
+		t.Fatalf("RefreshTokens after the failed rotation: %v", err)
+	}
+}
+
+func TestRevokeRefreshToken(t *testing.T) {
+	tx := newRefreshDB(t)
+	ctx := context.Background()
+	pair := issue(t, tx)
+
+	// Only the owner can log a family out
+	if err := RevokeRefreshToken(ctx, tx, "did:example:mallory", pair.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := RefreshTokens(ctx, tx, pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshTokens after another user's logout: %v", err)
+	}
+
+	if err := RevokeRefreshToken(ctx, tx, "did:example:alice", pair.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RefreshTokens(ctx, tx, rotated.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("err = %v, want ErrInvalidRefreshToken after logout", err)
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	tx := newRefreshDB(t)
+	ctx := context.Background()
+	t.Setenv("JWT_REVOCATION_CHECK", "false")
+
+	pair := issue(t, tx)
+	claims, err := ValidateToken(pair.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if revoked, err := IsRevoked(ctx, tx, claims.ID); err != nil || revoked {
+		t.Fatalf("revoked, err = %v, %v; want false, nil", revoked, err)
+	}
+
+	// Revoking twice is harmless
+	for i := 0; i < 2; i++ {
+		if err := RevokeToken(ctx, tx, claims); err != nil {
+			t.Fatalf("RevokeToken: %v", err)
+		}
+	}
+	if revoked, err := IsRevoked(ctx, tx, claims.ID); err != nil || !revoked {
+		t.Fatalf("revoked, err = %v, %v; want true, nil", revoked, err)
+	}
+
+	claims.ID = ""
+	if err := RevokeToken(ctx, tx, claims); err == nil {
+		t.Fatal("RevokeToken accepted a token without jti")
+	}
+}
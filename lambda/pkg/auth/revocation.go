@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/x-zero/xz-wallet/pkg/db"
+)
+
+// RevokeToken adds an access token's jti to the revocation list.
+// The row can be purged once the token would have expired anyway.
+func RevokeToken(ctx context.Context, q db.Querier, claims *Claims) error {
+	if claims.ID == "" {
+		return fmt.Errorf("token has no jti and cannot be revoked")
+	}
+
+	expiresAt := time.Now().Add(24 * time.Hour)
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	_, err := q.Exec(ctx, `
+		INSERT INTO revoked_tokens (jti, user_did, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING
+	`, claims.ID, claims.DID, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
+
+// IsRevoked reports whether a jti is on the revocation list
+func IsRevoked(ctx context.Context, q db.Querier, jti string) (bool, error) {
+	var revoked bool
+	err := q.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)
+	`, jti).Scan(&revoked)
+	if err != nil {
+		return false, fmt.Errorf("failed to check revocation: %w", err)
+	}
+	return revoked, nil
+}
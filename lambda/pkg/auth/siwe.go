@@ -0,0 +1,244 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/jackc/pgx/v5"
+	"github.com/x-zero/xz-wallet/pkg/db"
+)
+
+// ErrInvalidNonce is returned when a SIWE nonce is unknown, expired or already used
+var ErrInvalidNonce = errors.New("invalid or expired nonce")
+
+// SIWEMessage is a parsed EIP-4361 (Sign-In with Ethereum) message
+type SIWEMessage struct {
+	Domain         string
+	Address        common.Address
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        int64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+}
+
+const siweHeaderSuffix = " wants you to sign in with your Ethereum account:"
+
+// ParseSIWEMessage parses the plain-text EIP-4361 message format
+func ParseSIWEMessage(message string) (*SIWEMessage, error) {
+	lines := strings.Split(strings.ReplaceAll(message, "\r\n", "\n"), "\n")
+	if len(lines) < 2 || !strings.HasSuffix(lines[0], siweHeaderSuffix) {
+		return nil, fmt.Errorf("not a Sign-In with Ethereum message")
+	}
+
+	msg := &SIWEMessage{Domain: strings.TrimSuffix(lines[0], siweHeaderSuffix)}
+	if msg.Domain == "" {
+		return nil, fmt.Errorf("missing domain")
+	}
+
+	if !common.IsHexAddress(lines[1]) {
+		return nil, fmt.Errorf("invalid address: %q", lines[1])
+	}
+	msg.Address = common.HexToAddress(lines[1])
+
+	var issuedAt string
+	inResources := false
+	for _, line := range lines[2:] {
+		if inResources {
+			if strings.HasPrefix(line, "- ") {
+				msg.Resources = append(msg.Resources, strings.TrimPrefix(line, "- "))
+				continue
+			}
+			inResources = false
+		}
+
+		key, value, found := strings.Cut(line, ": ")
+		switch {
+		case line == "":
+			continue
+		case line == "Resources:":
+			inResources = true
+		case found && key == "URI":
+			msg.URI = value
+		case found && key == "Version":
+			msg.Version = value
+		case found && key == "Chain ID":
+			chainID, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid chain ID: %q", value)
+			}
+			msg.ChainID = chainID
+		case found && key == "Nonce":
+			msg.Nonce = value
+		case found && key == "Issued At":
+			issuedAt = value
+		case found && key == "Expiration Time":
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid expiration time: %q", value)
+			}
+			msg.ExpirationTime = &t
+		case found && key == "Not Before":
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid not-before time: %q", value)
+			}
+			msg.NotBefore = &t
+		case found && key == "Request ID":
+			msg.RequestID = value
+		case msg.URI == "" && msg.Statement == "":
+			msg.Statement = line
+		default:
+			return nil, fmt.Errorf("unexpected line: %q", line)
+		}
+	}
+
+	if msg.URI == "" || msg.Version == "" || msg.Nonce == "" || issuedAt == "" || msg.ChainID == 0 {
+		return nil, fmt.Errorf("message is missing required fields")
+	}
+	if msg.Version != "1" {
+		return nil, fmt.Errorf("unsupported version: %q", msg.Version)
+	}
+	if u, err := url.Parse(msg.URI); err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid URI: %q", msg.URI)
+	}
+
+	t, err := time.Parse(time.RFC3339, issuedAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid issued-at time: %q", issuedAt)
+	}
+	msg.IssuedAt = t
+
+	return msg, nil
+}
+
+// SIWEDomain reads SIWE_DOMAIN, the domain every SIWE message must name.
+// It is required: without it a message signed for any other site would pass.
+func SIWEDomain() (string, error) {
+	domain := os.Getenv("SIWE_DOMAIN")
+	if domain == "" {
+		return "", fmt.Errorf("SIWE_DOMAIN not set")
+	}
+	return domain, nil
+}
+
+// Validate checks the message's domain, URI, chain and time window.
+// chainID is the registry's default chain, the one auth-nonce advertises.
+func (m *SIWEMessage) Validate(domain string, chainID int64, now time.Time) error {
+	if domain == "" {
+		return fmt.Errorf("no SIWE domain to check against")
+	}
+	if m.Domain != domain {
+		return fmt.Errorf("domain mismatch: %s", m.Domain)
+	}
+	// The URI must point at the same site, over HTTP(S)
+	u, err := url.Parse(m.URI)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host != domain {
+		return fmt.Errorf("URI mismatch: %s", m.URI)
+	}
+	if chainID == 0 {
+		return fmt.Errorf("no chain ID to check against")
+	}
+	if m.ChainID != chainID {
+		return fmt.Errorf("chain ID mismatch: %d", m.ChainID)
+	}
+	if m.ExpirationTime != nil && now.After(*m.ExpirationTime) {
+		return fmt.Errorf("message has expired")
+	}
+	if m.NotBefore != nil && now.Before(*m.NotBefore) {
+		return fmt.Errorf("message is not yet valid")
+	}
+	// Allow a little clock skew for wallets ahead of the server
+	if m.IssuedAt.After(now.Add(5 * time.Minute)) {
+		return fmt.Errorf("issued-at time is in the future")
+	}
+	return nil
+}
+
+// VerifySignature checks a personal_sign (EIP-191) signature over the raw message
+func VerifySignature(message, signatureHex string, address common.Address) error {
+	sig, err := hexutil.Decode(signatureHex)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return fmt.Errorf("invalid signature length: %d", len(sig))
+	}
+
+	// Wallets return v as 27/28; go-ethereum expects 0/1
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pubKey, err := crypto.SigToPub(accounts.TextHash([]byte(message)), sig)
+	if err != nil {
+		return fmt.Errorf("failed to recover signer: %w", err)
+	}
+
+	if crypto.PubkeyToAddress(*pubKey) != address {
+		return fmt.Errorf("signature does not match address")
+	}
+	return nil
+}
+
+// CreateNonce stores a single-use login nonce for an address
+func CreateNonce(ctx context.Context, q db.Querier, address common.Address) (string, time.Time, error) {
+	nonce, err := randomHex(16)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	expiresAt := time.Now().Add(nonceTTL())
+	_, err = q.Exec(ctx, `
+		INSERT INTO auth_nonces (nonce, eth_address, expires_at)
+		VALUES ($1, $2, $3)
+	`, nonce, strings.ToLower(address.Hex()), expiresAt)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to store nonce: %w", err)
+	}
+
+	return nonce, expiresAt, nil
+}
+
+// ConsumeNonce marks a nonce used; it fails if the nonce was issued for another address
+func ConsumeNonce(ctx context.Context, q db.Querier, nonce string, address common.Address) error {
+	var consumed string
+	err := q.QueryRow(ctx, `
+		UPDATE auth_nonces
+		SET used_at = NOW()
+		WHERE nonce = $1 AND eth_address = $2 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING nonce
+	`, nonce, strings.ToLower(address.Hex())).Scan(&consumed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrInvalidNonce
+	}
+	if err != nil {
+		return fmt.Errorf("failed to consume nonce: %w", err)
+	}
+	return nil
+}
+
+// nonceTTL reads SIWE_NONCE_TTL (default 5m)
+func nonceTTL() time.Duration {
+	if value := os.Getenv("SIWE_NONCE_TTL"); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return 5 * time.Minute
+}
@@ -0,0 +1,233 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/x-zero/xz-wallet/pkg/dbtest"
+)
+
+const testDomain = "tasks.example.com"
+
+var testIssuedAt = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+// siweMessage builds a message for address; extra lines go after Issued At
+func siweMessage(address common.Address, uri string, chainID int64, nonce string, extra ...string) string {
+	lines := []string{
+		testDomain + siweHeaderSuffix,
+		address.Hex(),
+		"",
+		"Sign in to XZ Tasks",
+		"",
+		"URI: " + uri,
+		"Version: 1",
+		fmt.Sprintf("Chain ID: %d", chainID),
+		"Nonce: " + nonce,
+		"Issued At: " + testIssuedAt.Format(time.RFC3339),
+	}
+	return strings.Join(append(lines, extra...), "\n")
+}
+
+// personalSign signs message the way wallets do, with v as 27/28
+func personalSign(t *testing.T, key *ecdsa.PrivateKey, message string) string {
+	t.Helper()
+
+	sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return hexutil.Encode(sig)
+}
+
+func newKey(t *testing.T) (*ecdsa.PrivateKey, common.Address) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, crypto.PubkeyToAddress(key.PublicKey)
+}
+
+func TestParseSIWEMessage(t *testing.T) {
+	address := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	expiry := testIssuedAt.Add(10 * time.Minute)
+
+	msg, err := ParseSIWEMessage(siweMessage(address, "https://tasks.example.com/login", 11155111, "abc123",
+		"Expiration Time: "+expiry.Format(time.RFC3339),
+		"Resources:",
+		"- https://tasks.example.com/terms",
+	))
+	if err != nil {
+		t.Fatalf("ParseSIWEMessage: %v", err)
+	}
+	if msg.Domain != testDomain || msg.Address != address || msg.Statement != "Sign in to XZ Tasks" ||
+		msg.URI != "https://tasks.example.com/login" || msg.ChainID != 11155111 || msg.Nonce != "abc123" ||
+		!msg.IssuedAt.Equal(testIssuedAt) || msg.ExpirationTime == nil || !msg.ExpirationTime.Equal(expiry) ||
+		len(msg.Resources) != 1 {
+		t.Fatalf("parsed %+v", msg)
+	}
+
+	cases := []struct {
+		name    string
+		message string
+	}{
+		{"not SIWE", "hello"},
+		{"bad address", strings.Replace(siweMessage(address, "https://tasks.example.com", 1, "n"), address.Hex(), "0x123", 1)},
+		{"missing nonce", strings.Replace(siweMessage(address, "https://tasks.example.com", 1, "n"), "Nonce: n", "", 1)},
+		{"relative URI", siweMessage(address, "/login", 1, "n")},
+		{"URI without scheme", siweMessage(address, "tasks.example.com", 1, "n")},
+		{"bad chain ID", strings.Replace(siweMessage(address, "https://tasks.example.com", 1, "n"), "Chain ID: 1", "Chain ID: one", 1)},
+		{"bad expiry", siweMessage(address, "https://tasks.example.com", 1, "n", "Expiration Time: tomorrow")},
+		{"unknown field", siweMessage(address, "https://tasks.example.com", 1, "n", "Color: blue")},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseSIWEMessage(tc.message); err == nil {
+				t.Fatal("ParseSIWEMessage accepted the message")
+			}
+		})
+	}
+}
+
+func TestSIWEMessageValidate(t *testing.T) {
+	address := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	now := testIssuedAt.Add(time.Minute)
+	parse := func(message string) *SIWEMessage {
+		msg, err := ParseSIWEMessage(message)
+		if err != nil {
+			t.Fatalf("ParseSIWEMessage: %v", err)
+		}
+		return msg
+	}
+
+	cases := []struct {
+		name    string
+		message string
+		domain  string
+		chainID int64
+		ok      bool
+	}{
+		{"valid", siweMessage(address, "https://tasks.example.com/login", 11155111, "n"), testDomain, 11155111, true},
+		{"wrong domain", siweMessage(address, "https://tasks.example.com", 11155111, "n"), "other.example.com", 11155111, false},
+		{"no domain configured", siweMessage(address, "https://tasks.example.com", 11155111, "n"), "", 11155111, false},
+		{"URI on another site", siweMessage(address, "https://evil.example.com/login", 11155111, "n"), testDomain, 11155111, false},
+		{"URI on a subdomain", siweMessage(address, "https://api.tasks.example.com", 11155111, "n"), testDomain, 11155111, false},
+		{"URI with another scheme", siweMessage(address, "ftp://tasks.example.com", 11155111, "n"), testDomain, 11155111, false},
+		{"wrong chain", siweMessage(address, "https://tasks.example.com", 1, "n"), testDomain, 11155111, false},
+		{"no chain configured", siweMessage(address, "https://tasks.example.com", 11155111, "n"), testDomain, 0, false},
+		{"expired", siweMessage(address, "https://tasks.example.com", 11155111, "n",
+			"Expiration Time: "+now.Add(-time.Second).Format(time.RFC3339)), testDomain, 11155111, false},
+		{"not yet valid", siweMessage(address, "https://tasks.example.com", 11155111, "n",
+			"Not Before: "+now.Add(time.Hour).Format(time.RFC3339)), testDomain, 11155111, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := parse(tc.message).Validate(tc.domain, tc.chainID, now)
+			if tc.ok && err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if !tc.ok && err == nil {
+				t.Fatal("Validate accepted the message")
+			}
+		})
+	}
+
+	// Issued-at in the future beyond the clock skew allowance
+	msg := parse(siweMessage(address, "https://tasks.example.com", 11155111, "n"))
+	if err := msg.Validate(testDomain, 11155111, testIssuedAt.Add(-4*time.Minute)); err != nil {
+		t.Fatalf("within clock skew: %v", err)
+	}
+	if err := msg.Validate(testDomain, 11155111, testIssuedAt.Add(-6*time.Minute)); err == nil {
+		t.Fatal("Validate accepted a message issued in the future")
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	key, address := newKey(t)
+	_, other := newKey(t)
+	message := siweMessage(address, "https://tasks.example.com", 11155111, "n")
+	signature := personalSign(t, key, message)
+
+	if err := VerifySignature(message, signature, address); err != nil {
+		t.Fatalf("VerifySignature: %v", err)
+	}
+
+	// go-ethereum style 0/1 recovery IDs are accepted too
+	raw := hexutil.MustDecode(signature)
+	raw[crypto.RecoveryIDOffset] -= 27
+	if err := VerifySignature(message, hexutil.Encode(raw), address); err != nil {
+		t.Fatalf("VerifySignature with v=0/1: %v", err)
+	}
+
+	cases := []struct {
+		name      string
+		message   string
+		signature string
+		address   common.Address
+	}{
+		{"other signer", message, signature, other},
+		{"tampered message", strings.Replace(message, "Chain ID: 11155111", "Chain ID: 1", 1), signature, address},
+		{"not hex", message, "signature", address},
+		{"short", message, signature[:len(signature)-2], address},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := VerifySignature(tc.message, tc.signature, tc.address); err == nil {
+				t.Fatal("VerifySignature accepted the signature")
+			}
+		})
+	}
+}
+
+func TestConsumeNonce(t *testing.T) {
+	tx := dbtest.Begin(t)
+	ctx := context.Background()
+	_, address := newKey(t)
+	_, other := newKey(t)
+
+	nonce, expiresAt, err := CreateNonce(ctx, tx, address)
+	if err != nil {
+		t.Fatalf("CreateNonce: %v", err)
+	}
+	if !expiresAt.After(time.Now()) {
+		t.Fatalf("expires_at = %v, want in the future", expiresAt)
+	}
+
+	// Issued to someone else
+	if err := ConsumeNonce(ctx, tx, nonce, other); !errors.Is(err, ErrInvalidNonce) {
+		t.Fatalf("other address: err = %v, want ErrInvalidNonce", err)
+	}
+
+	if err := ConsumeNonce(ctx, tx, nonce, address); err != nil {
+		t.Fatalf("ConsumeNonce: %v", err)
+	}
+	if err := ConsumeNonce(ctx, tx, nonce, address); !errors.Is(err, ErrInvalidNonce) {
+		t.Fatalf("reused nonce: err = %v, want ErrInvalidNonce", err)
+	}
+
+	expired, _, err := CreateNonce(ctx, tx, address)
+	if err != nil {
+		t.Fatalf("CreateNonce: %v", err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE auth_nonces SET expires_at = NOW() - INTERVAL '1 second' WHERE nonce = $1`, expired); err != nil {
+		t.Fatal(err)
+	}
+	if err := ConsumeNonce(ctx, tx, expired, address); !errors.Is(err, ErrInvalidNonce) {
+		t.Fatalf("expired nonce: err = %v, want ErrInvalidNonce", err)
+	}
+
+	if err := ConsumeNonce(ctx, tx, "unknown", address); !errors.Is(err, ErrInvalidNonce) {
+		t.Fatalf("unknown nonce: err = %v, want ErrInvalidNonce", err)
+	}
+}
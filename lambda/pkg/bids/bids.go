@@ -0,0 +1,62 @@
+// Package bids keeps the revision history of bids and the task status that
+// follows from them
+package bids
+
+import (
+	"context"
+
+	"github.com/x-zero/xz-wallet/pkg/db"
+)
+
+// insertRevisions stores each selected bid as it now stands as its next revision
+const insertRevisions = `
+	INSERT INTO task_bid_revisions (bid_id, revision, action, bid_message, proposed_reward,
+		estimated_delivery_date, proposed_milestone_bps, attachment_urls, reward_snapshot, proposed_team)
+	SELECT b.bid_id,
+	       COALESCE((SELECT MAX(revision) FROM task_bid_revisions r WHERE r.bid_id = b.bid_id), 0) + 1,
+	       $2, b.bid_message, b.proposed_reward,
+	       b.estimated_delivery_date, b.proposed_milestone_bps, b.attachment_urls, b.reward_snapshot, b.proposed_team
+	FROM task_bids b
+`
+
+// RecordRevision stores the bid as it now stands as its next revision. The
+// bid row is locked first so concurrent revisions cannot take the same
+// number; call it inside a transaction.
+func RecordRevision(ctx context.Context, q db.Querier, bidID, action string) error {
+	if _, err := q.Exec(ctx, "SELECT 1 FROM task_bids WHERE bid_id = $1 FOR UPDATE", bidID); err != nil {
+		return err
+	}
+	_, err := q.Exec(ctx, insertRevisions+"WHERE b.bid_id = $1", bidID, action)
+	return err
+}
+
+// RecordTaskRevisions stores a revision of every pending bid on the task,
+// locking the bids like RecordRevision
+func RecordTaskRevisions(ctx context.Context, q db.Querier, taskID, action string) error {
+	_, err := q.Exec(ctx, `
+		SELECT 1 FROM task_bids WHERE task_id = $1 AND status = 'pending' ORDER BY bid_id FOR UPDATE
+	`, taskID)
+	if err != nil {
+		return err
+	}
+	_, err = q.Exec(ctx, insertRevisions+"WHERE b.task_id = $1 AND b.status = 'pending'", taskID, action)
+	return err
+}
+
+// ReturnToPending moves a bidding task left without pending bids back to
+// pending and reports whether it did. The task row is locked first, so two
+// bids withdrawn at once cannot each still see the other one pending.
+func ReturnToPending(ctx context.Context, q db.Querier, taskID string) (bool, error) {
+	if _, err := q.Exec(ctx, "SELECT 1 FROM tasks WHERE task_id = $1 FOR UPDATE", taskID); err != nil {
+		return false, err
+	}
+	tag, err := q.Exec(ctx, `
+		UPDATE tasks SET status = 'pending', updated_at = CURRENT_TIMESTAMP
+		WHERE task_id = $1 AND status = 'bidding'
+		  AND NOT EXISTS (SELECT 1 FROM task_bids WHERE task_id = $1 AND status = 'pending')
+	`, taskID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
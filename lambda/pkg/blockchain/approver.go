@@ -0,0 +1,204 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// ErrInsufficientBalance is returned when the owner cannot cover the amount at all
+	ErrInsufficientBalance = errors.New("insufficient XZT balance")
+	// ErrApprovalRequired is returned when the allowance is short and no approver can fix it
+	ErrApprovalRequired = errors.New("escrow allowance insufficient: sign a permit or approve the escrow contract")
+)
+
+// Approver grants the escrow contract an XZT allowance on behalf of a task creator
+type Approver interface {
+	Approve(ctx context.Context, req ApprovalRequest) error
+}
+
+// ApprovalRequest describes the allowance the escrow needs
+type ApprovalRequest struct {
+	Owner     common.Address
+	Amount    *big.Int
+	Permit    *PermitSignature // EIP-2612 permit signed by the owner, if the client sent one
+	AuthToken string           // Caller's bearer token, for approvers acting on the user's behalf
+}
+
+// PermitApprover relays owner-signed EIP-2612 permits from the admin wallet
+type PermitApprover struct {
+	Client *BlockchainClient
+}
+
+// Approve submits the request's permit
+func (a *PermitApprover) Approve(ctx context.Context, req ApprovalRequest) error {
+	if req.Permit == nil {
+		return ErrApprovalRequired
+	}
+	if _, err := a.Client.SubmitPermit(req.Owner, req.Amount, req.Permit); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DIDLoginApprover asks the did-login service, which holds the custodial
+// wallet keys, to approve the escrow. Kept for users whose clients cannot sign permits.
+type DIDLoginApprover struct {
+	BaseURL      string
+	TokenAddress common.Address
+	Spender      common.Address
+	HTTPClient   *http.Client
+}
+
+// Approve calls POST {BaseURL}/api/approve-escrow with the caller's token
+func (a *DIDLoginApprover) Approve(ctx context.Context, req ApprovalRequest) error {
+	if req.AuthToken == "" {
+		return fmt.Errorf("missing caller token for did-login approval")
+	}
+
+	jsonData, err := json.Marshal(map[string]string{
+		"token_address":   a.TokenAddress.Hex(),
+		"spender_address": a.Spender.Hex(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", a.BaseURL+"/api/approve-escrow", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", req.AuthToken)
+
+	httpClient := a.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 15 * time.Second}
+	}
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("approve-escrow API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if !result.Success {
+		if result.Error == "" {
+			result.Error = "unknown error"
+		}
+		return fmt.Errorf("approval failed: %s", result.Error)
+	}
+
+	return nil
+}
+
+// NewApproverFromEnv returns the fallback approver for requests without a permit,
+// selected by ESCROW_APPROVER ("did-login" or "none"). Returns nil for "none".
+func NewApproverFromEnv(c *BlockchainClient) (Approver, error) {
+	switch os.Getenv("ESCROW_APPROVER") {
+	case "", "none":
+		return nil, nil
+	case "did-login":
+		baseURL := os.Getenv("DID_LOGIN_API_URL")
+		if baseURL == "" {
+			return nil, fmt.Errorf("DID_LOGIN_API_URL not configured")
+		}
+		return &DIDLoginApprover{
+			BaseURL:      baseURL,
+			TokenAddress: c.TokenAddress,
+			Spender:      c.EscrowAddress,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown ESCROW_APPROVER: %s", os.Getenv("ESCROW_APPROVER"))
+	}
+}
+
+// EnsureAllowance makes sure the escrow can pull req.Amount from req.Owner.
+// If the allowance is short it asks the approver, then re-reads the allowance
+// until the approval is visible instead of sleeping a fixed time.
+func (c *BlockchainClient) EnsureAllowance(ctx context.Context, approver Approver, req ApprovalRequest) error {
+	allowance, err := c.Token.Allowance(&bind.CallOpts{Context: ctx}, req.Owner, c.EscrowAddress)
+	if err != nil {
+		return fmt.Errorf("failed to check allowance: %w", err)
+	}
+	if allowance.Cmp(req.Amount) >= 0 {
+		return nil
+	}
+
+	if err := c.CheckBalance(ctx, req.Owner, req.Amount); err != nil {
+		return err
+	}
+
+	if approver == nil {
+		return ErrApprovalRequired
+	}
+	if err := approver.Approve(ctx, req); err != nil {
+		return err
+	}
+
+	return c.WaitForAllowance(ctx, req.Owner, req.Amount)
+}
+
+// CheckBalance returns ErrInsufficientBalance when owner holds less than amount
+func (c *BlockchainClient) CheckBalance(ctx context.Context, owner common.Address, amount *big.Int) error {
+	balance, err := c.Token.BalanceOf(&bind.CallOpts{Context: ctx}, owner)
+	if err != nil {
+		return fmt.Errorf("failed to check balance: %w", err)
+	}
+	if balance.Cmp(amount) < 0 {
+		return ErrInsufficientBalance
+	}
+	return nil
+}
+
+// WaitForAllowance polls the allowance until it covers amount or the wait times out
+func (c *BlockchainClient) WaitForAllowance(ctx context.Context, owner common.Address, amount *big.Int) error {
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		allowance, err := c.Token.Allowance(&bind.CallOpts{Context: ctx}, owner, c.EscrowAddress)
+		if err == nil && allowance.Cmp(amount) >= 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("failed to confirm allowance: %w", err)
+			}
+			return fmt.Errorf("approval not visible on chain yet (allowance %s, need %s)", allowance, amount)
+		case <-ticker.C:
+		}
+	}
+}
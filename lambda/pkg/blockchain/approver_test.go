@@ -0,0 +1,106 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeApprover approves the escrow from the owner's key, the way the
+// did-login service does with its custodial keys
+type fakeApprover struct {
+	chain *testChain
+	calls int
+	err   error
+}
+
+func (a *fakeApprover) Approve(ctx context.Context, req ApprovalRequest) error {
+	a.calls++
+	if a.err != nil {
+		return a.err
+	}
+	auth, err := a.chain.CreateUserAuth(a.chain.creator)
+	if err != nil {
+		return err
+	}
+	tx, err := a.chain.Token.Approve(auth, a.chain.EscrowAddress, req.Amount)
+	if err != nil {
+		return err
+	}
+	_, err = a.chain.waitMined(ctx, tx)
+	return err
+}
+
+func TestEnsureAllowanceUsesApprover(t *testing.T) {
+	chain := newTestChain(t, "TaskEscrow")
+	creator := address(chain.creator)
+	chain.fund(t, creator, xzt(100))
+
+	approver := &fakeApprover{chain: chain}
+	req := ApprovalRequest{Owner: creator, Amount: xzt(40)}
+	if err := chain.EnsureAllowance(context.Background(), approver, req); err != nil {
+		t.Fatalf("EnsureAllowance: %v", err)
+	}
+	if approver.calls != 1 {
+		t.Fatalf("approver called %d times, want 1", approver.calls)
+	}
+
+	// The allowance now covers the amount, so the approver is not asked again
+	if err := chain.EnsureAllowance(context.Background(), approver, req); err != nil {
+		t.Fatalf("EnsureAllowance with allowance: %v", err)
+	}
+	if approver.calls != 1 {
+		t.Fatalf("approver called %d times, want 1", approver.calls)
+	}
+
+	taskID, _, err := chain.CreateTask(creator.Hex(), xzt(40), nil)
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	_, _, total, _, _, err := chain.GetTask(taskID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total.Cmp(xzt(40)) != 0 {
+		t.Fatalf("task total %s, want %s", total, xzt(40))
+	}
+}
+
+func TestEnsureAllowanceInsufficientBalance(t *testing.T) {
+	chain := newTestChain(t, "TaskEscrow")
+	creator := address(chain.creator)
+	chain.fund(t, creator, xzt(10))
+
+	approver := &fakeApprover{chain: chain}
+	err := chain.EnsureAllowance(context.Background(), approver, ApprovalRequest{Owner: creator, Amount: xzt(40)})
+	if !errors.Is(err, ErrInsufficientBalance) {
+		t.Fatalf("err = %v, want ErrInsufficientBalance", err)
+	}
+	if approver.calls != 0 {
+		t.Fatalf("approver called %d times, want 0", approver.calls)
+	}
+}
+
+func TestEnsureAllowanceWithoutApprover(t *testing.T) {
+	chain := newTestChain(t, "TaskEscrow")
+	creator := address(chain.creator)
+	chain.fund(t, creator, xzt(100))
+
+	err := chain.EnsureAllowance(context.Background(), nil, ApprovalRequest{Owner: creator, Amount: xzt(40)})
+	if !errors.Is(err, ErrApprovalRequired) {
+		t.Fatalf("err = %v, want ErrApprovalRequired", err)
+	}
+}
+
+func TestEnsureAllowanceApproverError(t *testing.T) {
+	chain := newTestChain(t, "TaskEscrow")
+	creator := address(chain.creator)
+	chain.fund(t, creator, xzt(100))
+
+	failure := errors.New("did-login unavailable")
+	approver := &fakeApprover{chain: chain, err: failure}
+	err := chain.EnsureAllowance(context.Background(), approver, ApprovalRequest{Owner: creator, Amount: xzt(40)})
+	if !errors.Is(err, failure) {
+		t.Fatalf("err = %v, want %v", err, failure)
+	}
+}
@@ -0,0 +1,80 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// WalletBalance is a wallet's XZT position across the token and the escrow
+type WalletBalance struct {
+	XZT       *big.Int
+	Allowance *big.Int // XZT the escrow may pull
+	ETH       *big.Int // For gas
+	Escrowed  *big.Int // Still locked in tasks the wallet created
+	Pending   *big.Int // Still owed to the wallet as executor
+	Created   map[uint64]*big.Int
+	Executing map[uint64]*big.Int
+}
+
+// GetWalletBalance reads balance, allowance, ETH and the remaining escrow of
+// the given tasks in one batch
+func (c *BlockchainClient) GetWalletBalance(ctx context.Context, owner common.Address, createdTaskIDs, executingTaskIDs []uint64) (*WalletBalance, error) {
+	balanceCall, err := c.TokenCall("balanceOf", owner)
+	if err != nil {
+		return nil, err
+	}
+	allowanceCall, err := c.TokenCall("allowance", owner, c.EscrowAddress)
+	if err != nil {
+		return nil, err
+	}
+	calls := []Call{balanceCall, allowanceCall, EthBalanceCall(owner)}
+
+	taskIDs := append(append([]uint64{}, createdTaskIDs...), executingTaskIDs...)
+	for _, id := range taskIDs {
+		call, err := c.EscrowCall("getRemainingAmount", new(big.Int).SetUint64(id))
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, call) // Unknown task ids revert and are skipped
+	}
+
+	results, err := c.NewBatchReader().Do(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+
+	wb := &WalletBalance{
+		Escrowed:  new(big.Int),
+		Pending:   new(big.Int),
+		Created:   make(map[uint64]*big.Int),
+		Executing: make(map[uint64]*big.Int),
+	}
+	if wb.XZT, err = UnpackBigInt(results[0]); err != nil {
+		return nil, fmt.Errorf("failed to read balance: %w", err)
+	}
+	if wb.Allowance, err = UnpackBigInt(results[1]); err != nil {
+		return nil, fmt.Errorf("failed to read allowance: %w", err)
+	}
+	if wb.ETH, err = UnpackBigInt(results[2]); err != nil {
+		return nil, fmt.Errorf("failed to read ETH balance: %w", err)
+	}
+
+	for i, id := range taskIDs {
+		remaining, err := UnpackBigInt(results[3+i])
+		if err != nil {
+			continue
+		}
+		if i < len(createdTaskIDs) {
+			wb.Created[id] = remaining
+			wb.Escrowed.Add(wb.Escrowed, remaining)
+		} else {
+			wb.Executing[id] = remaining
+			wb.Pending.Add(wb.Pending, remaining)
+		}
+	}
+
+	return wb, nil
+}
@@ -0,0 +1,215 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/x-zero/xz-wallet/pkg/blockchain/contracts"
+)
+
+// BatchReader resolves view calls in as few RPC round trips as possible:
+// Multicall3 aggregate calls, or JSON-RPC batches if Multicall3 is unavailable.
+// Results are cached for the reader's lifetime, so create one per request.
+type BatchReader struct {
+	client       *BlockchainClient
+	cache        map[string]CallResult
+	maxCalls     int
+	useMulticall bool
+}
+
+// TaskState is a task's live escrow state
+type TaskState struct {
+	Creator     common.Address
+	Executor    common.Address
+	TotalAmount *big.Int
+	PaidAmount  *big.Int
+	Remaining   *big.Int
+	Cancelled   bool
+}
+
+// NewBatchReader creates a reader; BATCH_MAX_CALLS caps calls per round trip (default 100)
+func (c *BlockchainClient) NewBatchReader() *BatchReader {
+	maxCalls := 100
+	if value := os.Getenv("BATCH_MAX_CALLS"); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			maxCalls = n
+		}
+	}
+	return &BatchReader{
+		client:       c,
+		cache:        make(map[string]CallResult),
+		maxCalls:     maxCalls,
+		useMulticall: os.Getenv("MULTICALL_DISABLED") != "true",
+	}
+}
+
+func cacheKey(call Call) string {
+	return call.Target.Hex() + ":" + hexutil.Encode(call.Data)
+}
+
+// Do returns one result per call. Calls that revert come back with Success false;
+// only transport failures return an error.
+func (r *BatchReader) Do(ctx context.Context, calls []Call) ([]CallResult, error) {
+	results := make([]CallResult, len(calls))
+
+	// Collect uncached calls, once each
+	var missing []Call
+	queued := make(map[string]bool)
+	for _, call := range calls {
+		key := cacheKey(call)
+		if _, ok := r.cache[key]; ok || queued[key] {
+			continue
+		}
+		queued[key] = true
+		missing = append(missing, Call{Target: call.Target, Data: call.Data, AllowFailure: true})
+	}
+
+	for start := 0; start < len(missing); start += r.maxCalls {
+		end := start + r.maxCalls
+		if end > len(missing) {
+			end = len(missing)
+		}
+		chunk := missing[start:end]
+
+		chunkResults, err := r.fetch(ctx, chunk)
+		if err != nil {
+			return nil, err
+		}
+		for i, call := range chunk {
+			r.cache[cacheKey(call)] = chunkResults[i]
+		}
+	}
+
+	for i, call := range calls {
+		results[i] = r.cache[cacheKey(call)]
+	}
+	return results, nil
+}
+
+// fetch sends one chunk, falling back to JSON-RPC batch if the multicall fails
+func (r *BatchReader) fetch(ctx context.Context, calls []Call) ([]CallResult, error) {
+	if r.useMulticall {
+		results, err := r.client.Multicall(ctx, calls)
+		if err == nil {
+			return results, nil
+		}
+		fmt.Printf("Multicall unavailable, falling back to JSON-RPC batch: %v\n", err)
+		r.useMulticall = false
+	}
+	return r.rpcBatch(ctx, calls)
+}
+
+// rpcBatch sends the calls as one JSON-RPC batch of eth_call requests.
+// Multicall3 getEthBalance calls become eth_getBalance.
+func (r *BatchReader) rpcBatch(ctx context.Context, calls []Call) ([]CallResult, error) {
+	elems := make([]rpc.BatchElem, len(calls))
+	outputs := make([]hexutil.Bytes, len(calls))
+	balances := make([]hexutil.Big, len(calls))
+	for i, call := range calls {
+		if owner, ok := ethBalanceOwner(call); ok {
+			elems[i] = rpc.BatchElem{
+				Method: "eth_getBalance",
+				Args:   []interface{}{owner, "latest"},
+				Result: &balances[i],
+			}
+			continue
+		}
+		elems[i] = rpc.BatchElem{
+			Method: "eth_call",
+			Args: []interface{}{
+				map[string]interface{}{"to": call.Target, "data": hexutil.Bytes(call.Data)},
+				"latest",
+			},
+			Result: &outputs[i],
+		}
+	}
+
+	rpcClient, ok := r.client.Client.(interface{ Client() *rpc.Client })
+	if !ok {
+		return nil, fmt.Errorf("backend does not support JSON-RPC batches")
+	}
+	if err := rpcClient.Client().BatchCallContext(ctx, elems); err != nil {
+		return nil, fmt.Errorf("batch call failed: %w", err)
+	}
+
+	results := make([]CallResult, len(calls))
+	for i, elem := range elems {
+		results[i] = CallResult{Success: elem.Error == nil, ReturnData: outputs[i]}
+		if elem.Method == "eth_getBalance" && elem.Error == nil {
+			results[i].ReturnData = common.LeftPadBytes(balances[i].ToInt().Bytes(), 32)
+		}
+	}
+	return results, nil
+}
+
+// ethBalanceOwner reports whether call is EthBalanceCall and for which address
+func ethBalanceOwner(call Call) (common.Address, bool) {
+	method := parsedMulticall3.Methods["getEthBalance"]
+	if call.Target != Multicall3Address() || len(call.Data) != 36 || !bytes.Equal(call.Data[:4], method.ID) {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(call.Data[4:]), true
+}
+
+// TaskStates reads getTask and getRemainingAmount for each contract task id.
+// Ids the escrow does not know are left out of the map.
+func (r *BatchReader) TaskStates(ctx context.Context, taskIDs []uint64) (map[uint64]*TaskState, error) {
+	parsed, err := contracts.TaskEscrowMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]Call, 0, len(taskIDs)*2)
+	for _, id := range taskIDs {
+		taskCall, err := r.client.EscrowCall("getTask", new(big.Int).SetUint64(id))
+		if err != nil {
+			return nil, err
+		}
+		remainingCall, err := r.client.EscrowCall("getRemainingAmount", new(big.Int).SetUint64(id))
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, taskCall, remainingCall)
+	}
+
+	results, err := r.Do(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[uint64]*TaskState)
+	for i, id := range taskIDs {
+		taskResult := results[2*i]
+		if !taskResult.Success {
+			continue
+		}
+		values, err := parsed.Unpack("getTask", taskResult.ReturnData)
+		if err != nil || len(values) != 5 {
+			continue
+		}
+		state := &TaskState{
+			Creator:     values[0].(common.Address),
+			Executor:    values[1].(common.Address),
+			TotalAmount: values[2].(*big.Int),
+			PaidAmount:  values[3].(*big.Int),
+			Cancelled:   values[4].(bool),
+		}
+		if state.Creator == (common.Address{}) {
+			continue // Never created
+		}
+		if remaining, err := UnpackBigInt(results[2*i+1]); err == nil {
+			state.Remaining = remaining
+		} else {
+			state.Remaining = new(big.Int).Sub(state.TotalAmount, state.PaidAmount)
+		}
+		states[id] = state
+	}
+	return states, nil
+}
@@ -6,129 +6,192 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/x-zero/xz-wallet/pkg/blockchain/contracts"
 )
 
 var (
-	client     *BlockchainClient
-	clientOnce sync.Once
+	clients  = make(map[string]*BlockchainClient)
+	clientMu sync.Mutex
 )
 
+// Backend is the chain access a BlockchainClient needs. Production uses
+// *ethclient.Client; tests use a simulated chain.
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	ChainID(ctx context.Context) (*big.Int, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
 // BlockchainClient wraps Ethereum client and contract instances
+// for one escrow contract on one chain
 type BlockchainClient struct {
-	Client       *ethclient.Client
-	Token        *contracts.XZToken
-	Escrow       *contracts.TaskEscrow
-	AdminAuth    *bind.TransactOpts
-	ChainID      *big.Int
-	TokenAddress common.Address
+	Client        Backend
+	Token         *contracts.XZToken
+	Escrow        *contracts.TaskEscrow
+	EscrowV2      *contracts.TaskEscrowV2 // Set when the escrow is v2 (signed releases and cancels)
+	AdminAuth     *bind.TransactOpts
+	ChainID       *big.Int
+	TokenAddress  common.Address
 	EscrowAddress common.Address
+	Transport     *FailoverTransport
+	Chain         *ChainConfig
 }
 
-// InitClient initializes the blockchain client (singleton)
+// InitClient returns the client for new tasks: the default chain's current escrow.
+// A failed initialization is not cached, so the next call tries again.
 func InitClient() (*BlockchainClient, error) {
-	var err error
-	clientOnce.Do(func() {
-		// Get environment variables
-		rpcURL := os.Getenv("SEPOLIA_RPC_URL")
-		if rpcURL == "" {
-			err = fmt.Errorf("SEPOLIA_RPC_URL not set")
-			return
-		}
+	r, err := LoadRegistry()
+	if err != nil {
+		return nil, err
+	}
+	chain := r.Chain(r.DefaultChainID)
+	return clientFor(chain, common.HexToAddress(chain.EscrowAddress))
+}
 
-		tokenAddr := os.Getenv("XZT_TOKEN_ADDRESS")
-		if tokenAddr == "" {
-			err = fmt.Errorf("XZT_TOKEN_ADDRESS not set")
-			return
-		}
+// ClientFor returns the client for a stored task's chain and escrow, so tasks
+// keep settling on the contract that holds their funds. Tasks created before
+// chain_id/escrow_address existed (zero values) use InitClient.
+func ClientFor(chainID int64, escrowAddress string) (*BlockchainClient, error) {
+	if chainID == 0 || escrowAddress == "" {
+		return InitClient()
+	}
 
-		escrowAddr := os.Getenv("TASK_ESCROW_ADDRESS")
-		if escrowAddr == "" {
-			err = fmt.Errorf("TASK_ESCROW_ADDRESS not set")
-			return
-		}
+	r, err := LoadRegistry()
+	if err != nil {
+		return nil, err
+	}
+	chain := r.Chain(chainID)
+	if chain == nil {
+		return nil, fmt.Errorf("chain %d not in registry", chainID)
+	}
+	escrow := common.HexToAddress(escrowAddress)
+	if !chain.HasEscrow(escrow) {
+		return nil, fmt.Errorf("escrow %s not registered for chain %d", escrow.Hex(), chainID)
+	}
+	return clientFor(chain, escrow)
+}
 
-		adminPrivateKey := os.Getenv("ADMIN_WALLET_PRIVATE_KEY")
-		if adminPrivateKey == "" {
-			err = fmt.Errorf("ADMIN_WALLET_PRIVATE_KEY not set")
-			return
-		}
+// ClientForTask is ClientFor for the nullable tasks.chain_id and tasks.escrow_address columns
+func ClientForTask(chainID *int64, escrowAddress *string) (*BlockchainClient, error) {
+	if chainID == nil || escrowAddress == nil {
+		return InitClient()
+	}
+	return ClientFor(*chainID, *escrowAddress)
+}
 
-		// Connect to Ethereum client
-		ethClient, err := ethclient.Dial(rpcURL)
-		if err != nil {
-			err = fmt.Errorf("failed to connect to Ethereum client: %w", err)
-			return
-		}
+func clientFor(chain *ChainConfig, escrow common.Address) (*BlockchainClient, error) {
+	clientMu.Lock()
+	defer clientMu.Unlock()
 
-		// Get chain ID
-		chainID, err := ethClient.ChainID(context.Background())
-		if err != nil {
-			err = fmt.Errorf("failed to get chain ID: %w", err)
-			return
-		}
+	key := fmt.Sprintf("%d:%s", chain.ChainID, escrow.Hex())
+	if c, ok := clients[key]; ok {
+		return c, nil
+	}
 
-		// Parse admin private key
-		privateKeyHex := adminPrivateKey
-		if len(privateKeyHex) > 2 && privateKeyHex[:2] == "0x" {
-			privateKeyHex = privateKeyHex[2:] // Remove "0x" prefix if present
-		}
-		privateKey, err := crypto.HexToECDSA(privateKeyHex)
-		if err != nil {
-			err = fmt.Errorf("failed to parse admin private key: %w", err)
-			return
-		}
+	c, err := newClient(context.Background(), chain, escrow)
+	if err != nil {
+		return nil, err
+	}
+	clients[key] = c
+	return c, nil
+}
+
+func newClient(ctx context.Context, chain *ChainConfig, escrowAddress common.Address) (*BlockchainClient, error) {
+	adminPrivateKey := os.Getenv("ADMIN_WALLET_PRIVATE_KEY")
+	if adminPrivateKey == "" {
+		return nil, fmt.Errorf("ADMIN_WALLET_PRIVATE_KEY not set")
+	}
+
+	// Connect through the failover transport
+	ethClient, transport, err := DialFailover(ctx, chain.RPCURLs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Ethereum client: %w", err)
+	}
 
-		// Create admin auth
-		adminAuth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
-		if err != nil {
-			err = fmt.Errorf("failed to create admin auth: %w", err)
-			return
+	// Skip endpoints that are down right now
+	if len(chain.RPCURLs) > 1 {
+		healthCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		healthy := transport.CheckHealth(healthCtx)
+		cancel()
+		if healthy == 0 {
+			return nil, fmt.Errorf("no healthy RPC endpoint")
 		}
+	}
 
-		// Set gas parameters
-		adminAuth.GasLimit = 500000 // Default gas limit
+	// Get chain ID and make sure the RPC serves the configured chain
+	chainID, err := ethClient.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	if chainID.Int64() != chain.ChainID {
+		return nil, fmt.Errorf("RPC for chain %d reports chain ID %s", chain.ChainID, chainID)
+	}
 
-		// Parse contract addresses
-		tokenAddress := common.HexToAddress(tokenAddr)
-		escrowAddress := common.HexToAddress(escrowAddr)
+	// Parse admin private key
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(adminPrivateKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse admin private key: %w", err)
+	}
 
-		// Create contract instances
-		token, err := contracts.NewXZToken(tokenAddress, ethClient)
-		if err != nil {
-			err = fmt.Errorf("failed to create token contract instance: %w", err)
-			return
-		}
+	// Create admin auth
+	adminAuth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create admin auth: %w", err)
+	}
 
-		escrow, err := contracts.NewTaskEscrow(escrowAddress, ethClient)
-		if err != nil {
-			err = fmt.Errorf("failed to create escrow contract instance: %w", err)
-			return
-		}
+	// Set gas parameters
+	adminAuth.GasLimit = 500000 // Default gas limit
 
-		client = &BlockchainClient{
-			Client:        ethClient,
-			Token:         token,
-			Escrow:        escrow,
-			AdminAuth:     adminAuth,
-			ChainID:       chainID,
-			TokenAddress:  tokenAddress,
-			EscrowAddress: escrowAddress,
-		}
-	})
+	// Create contract instances
+	tokenAddress := common.HexToAddress(chain.TokenAddress)
+	token, err := contracts.NewXZToken(tokenAddress, ethClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token contract instance: %w", err)
+	}
+
+	escrow, err := contracts.NewTaskEscrow(escrowAddress, ethClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create escrow contract instance: %w", err)
+	}
 
-	return client, err
+	escrowV2, err := detectEscrowV2(ctx, escrowAddress, ethClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlockchainClient{
+		Client:        ethClient,
+		Token:         token,
+		Escrow:        escrow,
+		EscrowV2:      escrowV2,
+		AdminAuth:     adminAuth,
+		ChainID:       chainID,
+		TokenAddress:  tokenAddress,
+		EscrowAddress: escrowAddress,
+		Transport:     transport,
+		Chain:         chain,
+	}, nil
 }
 
-// GetClient returns the singleton blockchain client
+// GetClient returns the client for new tasks if it is already initialized
 func GetClient() *BlockchainClient {
-	return client
+	r, err := LoadRegistry()
+	if err != nil {
+		return nil
+	}
+	chain := r.Chain(r.DefaultChainID)
+
+	clientMu.Lock()
+	defer clientMu.Unlock()
+	return clients[fmt.Sprintf("%d:%s", chain.ChainID, common.HexToAddress(chain.EscrowAddress).Hex())]
 }
 
 // GetBalance returns XZT balance for an address
@@ -151,7 +214,7 @@ func (c *BlockchainClient) Transfer(to string, amount *big.Int) (string, error)
 	}
 
 	// Wait for transaction to be mined
-	receipt, err := bind.WaitMined(context.Background(), c.Client, tx)
+	receipt, err := c.waitMined(context.Background(), tx)
 	if err != nil {
 		return "", fmt.Errorf("failed to wait for transaction: %w", err)
 	}
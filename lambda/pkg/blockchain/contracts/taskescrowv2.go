@@ -0,0 +1,3825 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package contracts
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// TaskEscrowV2MetaData contains all meta data concerning the TaskEscrowV2 contract.
+var TaskEscrowV2MetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_token\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"_withdrawPeriodLimit\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"inputs\":[],\"name\":\"ECDSAInvalidSignature\",\"type\":\"error\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"length\",\"type\":\"uint256\"}],\"name\":\"ECDSAInvalidSignatureLength\",\"type\":\"error\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"s\",\"type\":\"bytes32\"}],\"name\":\"ECDSAInvalidSignatureS\",\"type\":\"error\"},{\"inputs\":[],\"name\":\"InvalidShortString\",\"type\":\"error\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"OwnableInvalidOwner\",\"type\":\"error\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"OwnableUnauthorizedAccount\",\"type\":\"error\"},{\"inputs\":[],\"name\":\"ReentrancyGuardReentrantCall\",\"type\":\"error\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"str\",\"type\":\"string\"}],\"name\":\"StringTooLong\",\"type\":\"error\"},{\"anonymous\":false,\"inputs\":[],\"name\":\"EIP712DomainChanged\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"executor\",\"type\":\"address\"}],\"name\":\"ExecutorSet\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"executor\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"totalPaid\",\"type\":\"uint256\"}],\"name\":\"MilestonePaid\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"index\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"MilestoneReleased\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"previousOwner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"newOwner\",\"type\":\"address\"}],\"name\":\"OwnershipTransferred\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"executorAmount\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"creatorRefund\",\"type\":\"uint256\"}],\"name\":\"TaskCancelled\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"creator\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"executor\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"TaskCreated\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"creator\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"newTotal\",\"type\":\"uint256\"}],\"name\":\"TaskRefunded\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"creator\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"newTotal\",\"type\":\"uint256\"}],\"name\":\"TaskToppedUp\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"member\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"TeamMemberPaid\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"address[]\",\"name\":\"members\",\"type\":\"address[]\"},{\"indexed\":false,\"internalType\":\"uint16[]\",\"name\":\"shareBps\",\"type\":\"uint16[]\"}],\"name\":\"TeamSet\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"withdrawalId\",\"type\":\"uint256\"}],\"name\":\"WithdrawalCancelled\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"withdrawalId\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"WithdrawalExecuted\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"withdrawalId\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"executableAt\",\"type\":\"uint256\"}],\"name\":\"WithdrawalProposed\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"BPS_DENOMINATOR\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"CANCEL_TASK_TYPEHASH\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"CHANGE_EXECUTOR_TYPEHASH\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"DOMAIN_SEPARATOR\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"MAX_BATCH_SIZE\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"MAX_MILESTONES\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"MAX_TEAM_SIZE\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"RELEASE_MILESTONE_TYPEHASH\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"RESIGN_EXECUTOR_TYPEHASH\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"TOP_UP_TYPEHASH\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"WITHDRAW_DELAY\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"WITHDRAW_PERIOD\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"withdrawalId\",\"type\":\"uint256\"}],\"name\":\"cancelEmergencyWithdraw\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"executorAmount\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"deadline\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"creatorSignature\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"executorSignature\",\"type\":\"bytes\"}],\"name\":\"cancelTask\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"executor\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"deadline\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"creatorSignature\",\"type\":\"bytes\"}],\"name\":\"changeExecutor\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"creator\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"executor\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"},{\"internalType\":\"uint16[]\",\"name\":\"milestoneBps\",\"type\":\"uint16[]\"}],\"name\":\"createTask\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"creator\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"executor\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"},{\"internalType\":\"uint16[]\",\"name\":\"milestoneBps\",\"type\":\"uint16[]\"},{\"internalType\":\"uint256\",\"name\":\"deadline\",\"type\":\"uint256\"},{\"internalType\":\"uint8\",\"name\":\"v\",\"type\":\"uint8\"},{\"internalType\":\"bytes32\",\"name\":\"r\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"s\",\"type\":\"bytes32\"}],\"name\":\"createTaskWithPermit\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"creator\",\"type\":\"address\"},{\"internalType\":\"uint256[]\",\"name\":\"amounts\",\"type\":\"uint256[]\"},{\"internalType\":\"uint16[][]\",\"name\":\"milestoneBps\",\"type\":\"uint16[][]\"}],\"name\":\"createTasks\",\"outputs\":[{\"internalType\":\"uint256[]\",\"name\":\"\",\"type\":\"uint256[]\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"creator\",\"type\":\"address\"},{\"internalType\":\"uint256[]\",\"name\":\"amounts\",\"type\":\"uint256[]\"},{\"internalType\":\"uint16[][]\",\"name\":\"milestoneBps\",\"type\":\"uint16[][]\"},{\"internalType\":\"uint256\",\"name\":\"deadline\",\"type\":\"uint256\"},{\"internalType\":\"uint8\",\"name\":\"v\",\"type\":\"uint8\"},{\"internalType\":\"bytes32\",\"name\":\"r\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"s\",\"type\":\"bytes32\"}],\"name\":\"createTasksWithPermit\",\"outputs\":[{\"internalType\":\"uint256[]\",\"name\":\"\",\"type\":\"uint256[]\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"eip712Domain\",\"outputs\":[{\"internalType\":\"bytes1\",\"name\":\"fields\",\"type\":\"bytes1\"},{\"internalType\":\"string\",\"name\":\"name\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"version\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"chainId\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"verifyingContract\",\"type\":\"address\"},{\"internalType\":\"bytes32\",\"name\":\"salt\",\"type\":\"bytes32\"},{\"internalType\":\"uint256[]\",\"name\":\"extensions\",\"type\":\"uint256[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"withdrawalId\",\"type\":\"uint256\"}],\"name\":\"executeEmergencyWithdraw\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"}],\"name\":\"getMilestones\",\"outputs\":[{\"internalType\":\"uint16[]\",\"name\":\"milestoneBps\",\"type\":\"uint16[]\"},{\"internalType\":\"uint256\",\"name\":\"releasedMask\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"}],\"name\":\"getRemainingAmount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"}],\"name\":\"getTask\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"creator\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"executor\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"totalAmount\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"paidAmount\",\"type\":\"uint256\"},{\"internalType\":\"bool\",\"name\":\"cancelled\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"}],\"name\":\"getTeam\",\"outputs\":[{\"internalType\":\"address[]\",\"name\":\"members\",\"type\":\"address[]\"},{\"internalType\":\"uint16[]\",\"name\":\"shareBps\",\"type\":\"uint16[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"index\",\"type\":\"uint256\"}],\"name\":\"milestoneAmount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"nextTaskId\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"nextWithdrawalId\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"owner\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"proposeEmergencyWithdraw\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"refundPartial\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"index\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"deadline\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"creatorSignature\",\"type\":\"bytes\"}],\"name\":\"releaseMilestone\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"releasedMilestones\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"renounceOwnership\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"deadline\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"executorSignature\",\"type\":\"bytes\"}],\"name\":\"resignExecutor\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"executor\",\"type\":\"address\"}],\"name\":\"setExecutor\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"internalType\":\"address[]\",\"name\":\"members\",\"type\":\"address[]\"},{\"internalType\":\"uint16[]\",\"name\":\"shareBps\",\"type\":\"uint16[]\"}],\"name\":\"setExecutorTeam\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"taskNonces\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"tasks\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"creator\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"executor\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"totalAmount\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"paidAmount\",\"type\":\"uint256\"},{\"internalType\":\"bool\",\"name\":\"cancelled\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"}],\"name\":\"teamHash\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"token\",\"outputs\":[{\"internalType\":\"contractIERC20\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"deadline\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"creatorSignature\",\"type\":\"bytes\"}],\"name\":\"topUp\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"taskId\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"deadline\",\"type\":\"uint256\"},{\"internalType\":\"uint8\",\"name\":\"v\",\"type\":\"uint8\"},{\"internalType\":\"bytes32\",\"name\":\"r\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"s\",\"type\":\"bytes32\"}],\"name\":\"topUpWithPermit\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"totalOutstanding\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"newOwner\",\"type\":\"address\"}],\"name\":\"transferOwnership\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"withdrawPeriodLimit\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"withdrawPeriodStart\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"withdrawableExcess\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"withdrawals\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"executableAt\",\"type\":\"uint256\"},{\"internalType\":\"bool\",\"name\":\"executed\",\"type\":\"bool\"},{\"internalType\":\"bool\",\"name\":\"cancelled\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"withdrawnInPeriod\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// TaskEscrowV2ABI is the input ABI used to generate the binding from.
+// Deprecated: Use TaskEscrowV2MetaData.ABI instead.
+var TaskEscrowV2ABI = TaskEscrowV2MetaData.ABI
+
+// TaskEscrowV2 is an auto generated Go binding around an Ethereum contract.
+type TaskEscrowV2 struct {
+	TaskEscrowV2Caller     // Read-only binding to the contract
+	TaskEscrowV2Transactor // Write-only binding to the contract
+	TaskEscrowV2Filterer   // Log filterer for contract events
+}
+
+// TaskEscrowV2Caller is an auto generated read-only Go binding around an Ethereum contract.
+type TaskEscrowV2Caller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// TaskEscrowV2Transactor is an auto generated write-only Go binding around an Ethereum contract.
+type TaskEscrowV2Transactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// TaskEscrowV2Filterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type TaskEscrowV2Filterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// TaskEscrowV2Session is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type TaskEscrowV2Session struct {
+	Contract     *TaskEscrowV2     // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// TaskEscrowV2CallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type TaskEscrowV2CallerSession struct {
+	Contract *TaskEscrowV2Caller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts       // Call options to use throughout this session
+}
+
+// TaskEscrowV2TransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type TaskEscrowV2TransactorSession struct {
+	Contract     *TaskEscrowV2Transactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts       // Transaction auth options to use throughout this session
+}
+
+// TaskEscrowV2Raw is an auto generated low-level Go binding around an Ethereum contract.
+type TaskEscrowV2Raw struct {
+	Contract *TaskEscrowV2 // Generic contract binding to access the raw methods on
+}
+
+// TaskEscrowV2CallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type TaskEscrowV2CallerRaw struct {
+	Contract *TaskEscrowV2Caller // Generic read-only contract binding to access the raw methods on
+}
+
+// TaskEscrowV2TransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type TaskEscrowV2TransactorRaw struct {
+	Contract *TaskEscrowV2Transactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewTaskEscrowV2 creates a new instance of TaskEscrowV2, bound to a specific deployed contract.
+func NewTaskEscrowV2(address common.Address, backend bind.ContractBackend) (*TaskEscrowV2, error) {
+	contract, err := bindTaskEscrowV2(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskEscrowV2{TaskEscrowV2Caller: TaskEscrowV2Caller{contract: contract}, TaskEscrowV2Transactor: TaskEscrowV2Transactor{contract: contract}, TaskEscrowV2Filterer: TaskEscrowV2Filterer{contract: contract}}, nil
+}
+
+// NewTaskEscrowV2Caller creates a new read-only instance of TaskEscrowV2, bound to a specific deployed contract.
+func NewTaskEscrowV2Caller(address common.Address, caller bind.ContractCaller) (*TaskEscrowV2Caller, error) {
+	contract, err := bindTaskEscrowV2(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskEscrowV2Caller{contract: contract}, nil
+}
+
+// NewTaskEscrowV2Transactor creates a new write-only instance of TaskEscrowV2, bound to a specific deployed contract.
+func NewTaskEscrowV2Transactor(address common.Address, transactor bind.ContractTransactor) (*TaskEscrowV2Transactor, error) {
+	contract, err := bindTaskEscrowV2(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskEscrowV2Transactor{contract: contract}, nil
+}
+
+// NewTaskEscrowV2Filterer creates a new log filterer instance of TaskEscrowV2, bound to a specific deployed contract.
+func NewTaskEscrowV2Filterer(address common.Address, filterer bind.ContractFilterer) (*TaskEscrowV2Filterer, error) {
+	contract, err := bindTaskEscrowV2(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskEscrowV2Filterer{contract: contract}, nil
+}
+
+// bindTaskEscrowV2 binds a generic wrapper to an already deployed contract.
+func bindTaskEscrowV2(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := TaskEscrowV2MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_TaskEscrowV2 *TaskEscrowV2Raw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _TaskEscrowV2.Contract.TaskEscrowV2Caller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_TaskEscrowV2 *TaskEscrowV2Raw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.TaskEscrowV2Transactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_TaskEscrowV2 *TaskEscrowV2Raw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.TaskEscrowV2Transactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_TaskEscrowV2 *TaskEscrowV2CallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _TaskEscrowV2.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_TaskEscrowV2 *TaskEscrowV2TransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_TaskEscrowV2 *TaskEscrowV2TransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.contract.Transact(opts, method, params...)
+}
+
+// BPSDENOMINATOR is a free data retrieval call binding the contract method 0xe1a45218.
+//
+// Solidity: function BPS_DENOMINATOR() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) BPSDENOMINATOR(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "BPS_DENOMINATOR")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// BPSDENOMINATOR is a free data retrieval call binding the contract method 0xe1a45218.
+//
+// Solidity: function BPS_DENOMINATOR() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) BPSDENOMINATOR() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.BPSDENOMINATOR(&_TaskEscrowV2.CallOpts)
+}
+
+// BPSDENOMINATOR is a free data retrieval call binding the contract method 0xe1a45218.
+//
+// Solidity: function BPS_DENOMINATOR() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) BPSDENOMINATOR() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.BPSDENOMINATOR(&_TaskEscrowV2.CallOpts)
+}
+
+// CANCELTASKTYPEHASH is a free data retrieval call binding the contract method 0xb9b44131.
+//
+// Solidity: function CANCEL_TASK_TYPEHASH() view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) CANCELTASKTYPEHASH(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "CANCEL_TASK_TYPEHASH")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// CANCELTASKTYPEHASH is a free data retrieval call binding the contract method 0xb9b44131.
+//
+// Solidity: function CANCEL_TASK_TYPEHASH() view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2Session) CANCELTASKTYPEHASH() ([32]byte, error) {
+	return _TaskEscrowV2.Contract.CANCELTASKTYPEHASH(&_TaskEscrowV2.CallOpts)
+}
+
+// CANCELTASKTYPEHASH is a free data retrieval call binding the contract method 0xb9b44131.
+//
+// Solidity: function CANCEL_TASK_TYPEHASH() view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) CANCELTASKTYPEHASH() ([32]byte, error) {
+	return _TaskEscrowV2.Contract.CANCELTASKTYPEHASH(&_TaskEscrowV2.CallOpts)
+}
+
+// CHANGEEXECUTORTYPEHASH is a free data retrieval call binding the contract method 0xa9621b2a.
+//
+// Solidity: function CHANGE_EXECUTOR_TYPEHASH() view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) CHANGEEXECUTORTYPEHASH(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "CHANGE_EXECUTOR_TYPEHASH")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// CHANGEEXECUTORTYPEHASH is a free data retrieval call binding the contract method 0xa9621b2a.
+//
+// Solidity: function CHANGE_EXECUTOR_TYPEHASH() view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2Session) CHANGEEXECUTORTYPEHASH() ([32]byte, error) {
+	return _TaskEscrowV2.Contract.CHANGEEXECUTORTYPEHASH(&_TaskEscrowV2.CallOpts)
+}
+
+// CHANGEEXECUTORTYPEHASH is a free data retrieval call binding the contract method 0xa9621b2a.
+//
+// Solidity: function CHANGE_EXECUTOR_TYPEHASH() view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) CHANGEEXECUTORTYPEHASH() ([32]byte, error) {
+	return _TaskEscrowV2.Contract.CHANGEEXECUTORTYPEHASH(&_TaskEscrowV2.CallOpts)
+}
+
+// DOMAINSEPARATOR is a free data retrieval call binding the contract method 0x3644e515.
+//
+// Solidity: function DOMAIN_SEPARATOR() view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) DOMAINSEPARATOR(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "DOMAIN_SEPARATOR")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// DOMAINSEPARATOR is a free data retrieval call binding the contract method 0x3644e515.
+//
+// Solidity: function DOMAIN_SEPARATOR() view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2Session) DOMAINSEPARATOR() ([32]byte, error) {
+	return _TaskEscrowV2.Contract.DOMAINSEPARATOR(&_TaskEscrowV2.CallOpts)
+}
+
+// DOMAINSEPARATOR is a free data retrieval call binding the contract method 0x3644e515.
+//
+// Solidity: function DOMAIN_SEPARATOR() view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) DOMAINSEPARATOR() ([32]byte, error) {
+	return _TaskEscrowV2.Contract.DOMAINSEPARATOR(&_TaskEscrowV2.CallOpts)
+}
+
+// MAXBATCHSIZE is a free data retrieval call binding the contract method 0xcfdbf254.
+//
+// Solidity: function MAX_BATCH_SIZE() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) MAXBATCHSIZE(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "MAX_BATCH_SIZE")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// MAXBATCHSIZE is a free data retrieval call binding the contract method 0xcfdbf254.
+//
+// Solidity: function MAX_BATCH_SIZE() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) MAXBATCHSIZE() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.MAXBATCHSIZE(&_TaskEscrowV2.CallOpts)
+}
+
+// MAXBATCHSIZE is a free data retrieval call binding the contract method 0xcfdbf254.
+//
+// Solidity: function MAX_BATCH_SIZE() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) MAXBATCHSIZE() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.MAXBATCHSIZE(&_TaskEscrowV2.CallOpts)
+}
+
+// MAXMILESTONES is a free data retrieval call binding the contract method 0x4c05abeb.
+//
+// Solidity: function MAX_MILESTONES() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) MAXMILESTONES(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "MAX_MILESTONES")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// MAXMILESTONES is a free data retrieval call binding the contract method 0x4c05abeb.
+//
+// Solidity: function MAX_MILESTONES() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) MAXMILESTONES() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.MAXMILESTONES(&_TaskEscrowV2.CallOpts)
+}
+
+// MAXMILESTONES is a free data retrieval call binding the contract method 0x4c05abeb.
+//
+// Solidity: function MAX_MILESTONES() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) MAXMILESTONES() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.MAXMILESTONES(&_TaskEscrowV2.CallOpts)
+}
+
+// MAXTEAMSIZE is a free data retrieval call binding the contract method 0xd4b9c129.
+//
+// Solidity: function MAX_TEAM_SIZE() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) MAXTEAMSIZE(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "MAX_TEAM_SIZE")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// MAXTEAMSIZE is a free data retrieval call binding the contract method 0xd4b9c129.
+//
+// Solidity: function MAX_TEAM_SIZE() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) MAXTEAMSIZE() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.MAXTEAMSIZE(&_TaskEscrowV2.CallOpts)
+}
+
+// MAXTEAMSIZE is a free data retrieval call binding the contract method 0xd4b9c129.
+//
+// Solidity: function MAX_TEAM_SIZE() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) MAXTEAMSIZE() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.MAXTEAMSIZE(&_TaskEscrowV2.CallOpts)
+}
+
+// RELEASEMILESTONETYPEHASH is a free data retrieval call binding the contract method 0x3d68ef63.
+//
+// Solidity: function RELEASE_MILESTONE_TYPEHASH() view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) RELEASEMILESTONETYPEHASH(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "RELEASE_MILESTONE_TYPEHASH")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// RELEASEMILESTONETYPEHASH is a free data retrieval call binding the contract method 0x3d68ef63.
+//
+// Solidity: function RELEASE_MILESTONE_TYPEHASH() view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2Session) RELEASEMILESTONETYPEHASH() ([32]byte, error) {
+	return _TaskEscrowV2.Contract.RELEASEMILESTONETYPEHASH(&_TaskEscrowV2.CallOpts)
+}
+
+// RELEASEMILESTONETYPEHASH is a free data retrieval call binding the contract method 0x3d68ef63.
+//
+// Solidity: function RELEASE_MILESTONE_TYPEHASH() view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) RELEASEMILESTONETYPEHASH() ([32]byte, error) {
+	return _TaskEscrowV2.Contract.RELEASEMILESTONETYPEHASH(&_TaskEscrowV2.CallOpts)
+}
+
+// RESIGNEXECUTORTYPEHASH is a free data retrieval call binding the contract method 0x0ed947cb.
+//
+// Solidity: function RESIGN_EXECUTOR_TYPEHASH() view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) RESIGNEXECUTORTYPEHASH(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "RESIGN_EXECUTOR_TYPEHASH")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// RESIGNEXECUTORTYPEHASH is a free data retrieval call binding the contract method 0x0ed947cb.
+//
+// Solidity: function RESIGN_EXECUTOR_TYPEHASH() view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2Session) RESIGNEXECUTORTYPEHASH() ([32]byte, error) {
+	return _TaskEscrowV2.Contract.RESIGNEXECUTORTYPEHASH(&_TaskEscrowV2.CallOpts)
+}
+
+// RESIGNEXECUTORTYPEHASH is a free data retrieval call binding the contract method 0x0ed947cb.
+//
+// Solidity: function RESIGN_EXECUTOR_TYPEHASH() view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) RESIGNEXECUTORTYPEHASH() ([32]byte, error) {
+	return _TaskEscrowV2.Contract.RESIGNEXECUTORTYPEHASH(&_TaskEscrowV2.CallOpts)
+}
+
+// TOPUPTYPEHASH is a free data retrieval call binding the contract method 0xdb6dea31.
+//
+// Solidity: function TOP_UP_TYPEHASH() view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) TOPUPTYPEHASH(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "TOP_UP_TYPEHASH")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// TOPUPTYPEHASH is a free data retrieval call binding the contract method 0xdb6dea31.
+//
+// Solidity: function TOP_UP_TYPEHASH() view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2Session) TOPUPTYPEHASH() ([32]byte, error) {
+	return _TaskEscrowV2.Contract.TOPUPTYPEHASH(&_TaskEscrowV2.CallOpts)
+}
+
+// TOPUPTYPEHASH is a free data retrieval call binding the contract method 0xdb6dea31.
+//
+// Solidity: function TOP_UP_TYPEHASH() view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) TOPUPTYPEHASH() ([32]byte, error) {
+	return _TaskEscrowV2.Contract.TOPUPTYPEHASH(&_TaskEscrowV2.CallOpts)
+}
+
+// WITHDRAWDELAY is a free data retrieval call binding the contract method 0x0d5e5fff.
+//
+// Solidity: function WITHDRAW_DELAY() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) WITHDRAWDELAY(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "WITHDRAW_DELAY")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// WITHDRAWDELAY is a free data retrieval call binding the contract method 0x0d5e5fff.
+//
+// Solidity: function WITHDRAW_DELAY() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) WITHDRAWDELAY() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.WITHDRAWDELAY(&_TaskEscrowV2.CallOpts)
+}
+
+// WITHDRAWDELAY is a free data retrieval call binding the contract method 0x0d5e5fff.
+//
+// Solidity: function WITHDRAW_DELAY() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) WITHDRAWDELAY() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.WITHDRAWDELAY(&_TaskEscrowV2.CallOpts)
+}
+
+// WITHDRAWPERIOD is a free data retrieval call binding the contract method 0xea8bd8f7.
+//
+// Solidity: function WITHDRAW_PERIOD() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) WITHDRAWPERIOD(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "WITHDRAW_PERIOD")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// WITHDRAWPERIOD is a free data retrieval call binding the contract method 0xea8bd8f7.
+//
+// Solidity: function WITHDRAW_PERIOD() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) WITHDRAWPERIOD() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.WITHDRAWPERIOD(&_TaskEscrowV2.CallOpts)
+}
+
+// WITHDRAWPERIOD is a free data retrieval call binding the contract method 0xea8bd8f7.
+//
+// Solidity: function WITHDRAW_PERIOD() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) WITHDRAWPERIOD() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.WITHDRAWPERIOD(&_TaskEscrowV2.CallOpts)
+}
+
+// Eip712Domain is a free data retrieval call binding the contract method 0x84b0196e.
+//
+// Solidity: function eip712Domain() view returns(bytes1 fields, string name, string version, uint256 chainId, address verifyingContract, bytes32 salt, uint256[] extensions)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) Eip712Domain(opts *bind.CallOpts) (struct {
+	Fields            [1]byte
+	Name              string
+	Version           string
+	ChainId           *big.Int
+	VerifyingContract common.Address
+	Salt              [32]byte
+	Extensions        []*big.Int
+}, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "eip712Domain")
+
+	outstruct := new(struct {
+		Fields            [1]byte
+		Name              string
+		Version           string
+		ChainId           *big.Int
+		VerifyingContract common.Address
+		Salt              [32]byte
+		Extensions        []*big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Fields = *abi.ConvertType(out[0], new([1]byte)).(*[1]byte)
+	outstruct.Name = *abi.ConvertType(out[1], new(string)).(*string)
+	outstruct.Version = *abi.ConvertType(out[2], new(string)).(*string)
+	outstruct.ChainId = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	outstruct.VerifyingContract = *abi.ConvertType(out[4], new(common.Address)).(*common.Address)
+	outstruct.Salt = *abi.ConvertType(out[5], new([32]byte)).(*[32]byte)
+	outstruct.Extensions = *abi.ConvertType(out[6], new([]*big.Int)).(*[]*big.Int)
+
+	return *outstruct, err
+
+}
+
+// Eip712Domain is a free data retrieval call binding the contract method 0x84b0196e.
+//
+// Solidity: function eip712Domain() view returns(bytes1 fields, string name, string version, uint256 chainId, address verifyingContract, bytes32 salt, uint256[] extensions)
+func (_TaskEscrowV2 *TaskEscrowV2Session) Eip712Domain() (struct {
+	Fields            [1]byte
+	Name              string
+	Version           string
+	ChainId           *big.Int
+	VerifyingContract common.Address
+	Salt              [32]byte
+	Extensions        []*big.Int
+}, error) {
+	return _TaskEscrowV2.Contract.Eip712Domain(&_TaskEscrowV2.CallOpts)
+}
+
+// Eip712Domain is a free data retrieval call binding the contract method 0x84b0196e.
+//
+// Solidity: function eip712Domain() view returns(bytes1 fields, string name, string version, uint256 chainId, address verifyingContract, bytes32 salt, uint256[] extensions)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) Eip712Domain() (struct {
+	Fields            [1]byte
+	Name              string
+	Version           string
+	ChainId           *big.Int
+	VerifyingContract common.Address
+	Salt              [32]byte
+	Extensions        []*big.Int
+}, error) {
+	return _TaskEscrowV2.Contract.Eip712Domain(&_TaskEscrowV2.CallOpts)
+}
+
+// GetMilestones is a free data retrieval call binding the contract method 0x42c549c0.
+//
+// Solidity: function getMilestones(uint256 taskId) view returns(uint16[] milestoneBps, uint256 releasedMask)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) GetMilestones(opts *bind.CallOpts, taskId *big.Int) (struct {
+	MilestoneBps []uint16
+	ReleasedMask *big.Int
+}, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "getMilestones", taskId)
+
+	outstruct := new(struct {
+		MilestoneBps []uint16
+		ReleasedMask *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.MilestoneBps = *abi.ConvertType(out[0], new([]uint16)).(*[]uint16)
+	outstruct.ReleasedMask = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// GetMilestones is a free data retrieval call binding the contract method 0x42c549c0.
+//
+// Solidity: function getMilestones(uint256 taskId) view returns(uint16[] milestoneBps, uint256 releasedMask)
+func (_TaskEscrowV2 *TaskEscrowV2Session) GetMilestones(taskId *big.Int) (struct {
+	MilestoneBps []uint16
+	ReleasedMask *big.Int
+}, error) {
+	return _TaskEscrowV2.Contract.GetMilestones(&_TaskEscrowV2.CallOpts, taskId)
+}
+
+// GetMilestones is a free data retrieval call binding the contract method 0x42c549c0.
+//
+// Solidity: function getMilestones(uint256 taskId) view returns(uint16[] milestoneBps, uint256 releasedMask)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) GetMilestones(taskId *big.Int) (struct {
+	MilestoneBps []uint16
+	ReleasedMask *big.Int
+}, error) {
+	return _TaskEscrowV2.Contract.GetMilestones(&_TaskEscrowV2.CallOpts, taskId)
+}
+
+// GetRemainingAmount is a free data retrieval call binding the contract method 0xf6252ff2.
+//
+// Solidity: function getRemainingAmount(uint256 taskId) view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) GetRemainingAmount(opts *bind.CallOpts, taskId *big.Int) (*big.Int, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "getRemainingAmount", taskId)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetRemainingAmount is a free data retrieval call binding the contract method 0xf6252ff2.
+//
+// Solidity: function getRemainingAmount(uint256 taskId) view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) GetRemainingAmount(taskId *big.Int) (*big.Int, error) {
+	return _TaskEscrowV2.Contract.GetRemainingAmount(&_TaskEscrowV2.CallOpts, taskId)
+}
+
+// GetRemainingAmount is a free data retrieval call binding the contract method 0xf6252ff2.
+//
+// Solidity: function getRemainingAmount(uint256 taskId) view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) GetRemainingAmount(taskId *big.Int) (*big.Int, error) {
+	return _TaskEscrowV2.Contract.GetRemainingAmount(&_TaskEscrowV2.CallOpts, taskId)
+}
+
+// GetTask is a free data retrieval call binding the contract method 0x1d65e77e.
+//
+// Solidity: function getTask(uint256 taskId) view returns(address creator, address executor, uint256 totalAmount, uint256 paidAmount, bool cancelled)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) GetTask(opts *bind.CallOpts, taskId *big.Int) (struct {
+	Creator     common.Address
+	Executor    common.Address
+	TotalAmount *big.Int
+	PaidAmount  *big.Int
+	Cancelled   bool
+}, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "getTask", taskId)
+
+	outstruct := new(struct {
+		Creator     common.Address
+		Executor    common.Address
+		TotalAmount *big.Int
+		PaidAmount  *big.Int
+		Cancelled   bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Creator = *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	outstruct.Executor = *abi.ConvertType(out[1], new(common.Address)).(*common.Address)
+	outstruct.TotalAmount = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.PaidAmount = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	outstruct.Cancelled = *abi.ConvertType(out[4], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// GetTask is a free data retrieval call binding the contract method 0x1d65e77e.
+//
+// Solidity: function getTask(uint256 taskId) view returns(address creator, address executor, uint256 totalAmount, uint256 paidAmount, bool cancelled)
+func (_TaskEscrowV2 *TaskEscrowV2Session) GetTask(taskId *big.Int) (struct {
+	Creator     common.Address
+	Executor    common.Address
+	TotalAmount *big.Int
+	PaidAmount  *big.Int
+	Cancelled   bool
+}, error) {
+	return _TaskEscrowV2.Contract.GetTask(&_TaskEscrowV2.CallOpts, taskId)
+}
+
+// GetTask is a free data retrieval call binding the contract method 0x1d65e77e.
+//
+// Solidity: function getTask(uint256 taskId) view returns(address creator, address executor, uint256 totalAmount, uint256 paidAmount, bool cancelled)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) GetTask(taskId *big.Int) (struct {
+	Creator     common.Address
+	Executor    common.Address
+	TotalAmount *big.Int
+	PaidAmount  *big.Int
+	Cancelled   bool
+}, error) {
+	return _TaskEscrowV2.Contract.GetTask(&_TaskEscrowV2.CallOpts, taskId)
+}
+
+// GetTeam is a free data retrieval call binding the contract method 0x008e0f1b.
+//
+// Solidity: function getTeam(uint256 taskId) view returns(address[] members, uint16[] shareBps)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) GetTeam(opts *bind.CallOpts, taskId *big.Int) (struct {
+	Members  []common.Address
+	ShareBps []uint16
+}, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "getTeam", taskId)
+
+	outstruct := new(struct {
+		Members  []common.Address
+		ShareBps []uint16
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Members = *abi.ConvertType(out[0], new([]common.Address)).(*[]common.Address)
+	outstruct.ShareBps = *abi.ConvertType(out[1], new([]uint16)).(*[]uint16)
+
+	return *outstruct, err
+
+}
+
+// GetTeam is a free data retrieval call binding the contract method 0x008e0f1b.
+//
+// Solidity: function getTeam(uint256 taskId) view returns(address[] members, uint16[] shareBps)
+func (_TaskEscrowV2 *TaskEscrowV2Session) GetTeam(taskId *big.Int) (struct {
+	Members  []common.Address
+	ShareBps []uint16
+}, error) {
+	return _TaskEscrowV2.Contract.GetTeam(&_TaskEscrowV2.CallOpts, taskId)
+}
+
+// GetTeam is a free data retrieval call binding the contract method 0x008e0f1b.
+//
+// Solidity: function getTeam(uint256 taskId) view returns(address[] members, uint16[] shareBps)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) GetTeam(taskId *big.Int) (struct {
+	Members  []common.Address
+	ShareBps []uint16
+}, error) {
+	return _TaskEscrowV2.Contract.GetTeam(&_TaskEscrowV2.CallOpts, taskId)
+}
+
+// MilestoneAmount is a free data retrieval call binding the contract method 0x47eb77c0.
+//
+// Solidity: function milestoneAmount(uint256 taskId, uint256 index) view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) MilestoneAmount(opts *bind.CallOpts, taskId *big.Int, index *big.Int) (*big.Int, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "milestoneAmount", taskId, index)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// MilestoneAmount is a free data retrieval call binding the contract method 0x47eb77c0.
+//
+// Solidity: function milestoneAmount(uint256 taskId, uint256 index) view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) MilestoneAmount(taskId *big.Int, index *big.Int) (*big.Int, error) {
+	return _TaskEscrowV2.Contract.MilestoneAmount(&_TaskEscrowV2.CallOpts, taskId, index)
+}
+
+// MilestoneAmount is a free data retrieval call binding the contract method 0x47eb77c0.
+//
+// Solidity: function milestoneAmount(uint256 taskId, uint256 index) view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) MilestoneAmount(taskId *big.Int, index *big.Int) (*big.Int, error) {
+	return _TaskEscrowV2.Contract.MilestoneAmount(&_TaskEscrowV2.CallOpts, taskId, index)
+}
+
+// NextTaskId is a free data retrieval call binding the contract method 0xfdc3d8d7.
+//
+// Solidity: function nextTaskId() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) NextTaskId(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "nextTaskId")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// NextTaskId is a free data retrieval call binding the contract method 0xfdc3d8d7.
+//
+// Solidity: function nextTaskId() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) NextTaskId() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.NextTaskId(&_TaskEscrowV2.CallOpts)
+}
+
+// NextTaskId is a free data retrieval call binding the contract method 0xfdc3d8d7.
+//
+// Solidity: function nextTaskId() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) NextTaskId() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.NextTaskId(&_TaskEscrowV2.CallOpts)
+}
+
+// NextWithdrawalId is a free data retrieval call binding the contract method 0x4a9122e3.
+//
+// Solidity: function nextWithdrawalId() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) NextWithdrawalId(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "nextWithdrawalId")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// NextWithdrawalId is a free data retrieval call binding the contract method 0x4a9122e3.
+//
+// Solidity: function nextWithdrawalId() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) NextWithdrawalId() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.NextWithdrawalId(&_TaskEscrowV2.CallOpts)
+}
+
+// NextWithdrawalId is a free data retrieval call binding the contract method 0x4a9122e3.
+//
+// Solidity: function nextWithdrawalId() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) NextWithdrawalId() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.NextWithdrawalId(&_TaskEscrowV2.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "owner")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_TaskEscrowV2 *TaskEscrowV2Session) Owner() (common.Address, error) {
+	return _TaskEscrowV2.Contract.Owner(&_TaskEscrowV2.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) Owner() (common.Address, error) {
+	return _TaskEscrowV2.Contract.Owner(&_TaskEscrowV2.CallOpts)
+}
+
+// ReleasedMilestones is a free data retrieval call binding the contract method 0x2b1ac13a.
+//
+// Solidity: function releasedMilestones(uint256 ) view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) ReleasedMilestones(opts *bind.CallOpts, arg0 *big.Int) (*big.Int, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "releasedMilestones", arg0)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// ReleasedMilestones is a free data retrieval call binding the contract method 0x2b1ac13a.
+//
+// Solidity: function releasedMilestones(uint256 ) view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) ReleasedMilestones(arg0 *big.Int) (*big.Int, error) {
+	return _TaskEscrowV2.Contract.ReleasedMilestones(&_TaskEscrowV2.CallOpts, arg0)
+}
+
+// ReleasedMilestones is a free data retrieval call binding the contract method 0x2b1ac13a.
+//
+// Solidity: function releasedMilestones(uint256 ) view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) ReleasedMilestones(arg0 *big.Int) (*big.Int, error) {
+	return _TaskEscrowV2.Contract.ReleasedMilestones(&_TaskEscrowV2.CallOpts, arg0)
+}
+
+// TaskNonces is a free data retrieval call binding the contract method 0x8cacc8f5.
+//
+// Solidity: function taskNonces(uint256 ) view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) TaskNonces(opts *bind.CallOpts, arg0 *big.Int) (*big.Int, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "taskNonces", arg0)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// TaskNonces is a free data retrieval call binding the contract method 0x8cacc8f5.
+//
+// Solidity: function taskNonces(uint256 ) view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) TaskNonces(arg0 *big.Int) (*big.Int, error) {
+	return _TaskEscrowV2.Contract.TaskNonces(&_TaskEscrowV2.CallOpts, arg0)
+}
+
+// TaskNonces is a free data retrieval call binding the contract method 0x8cacc8f5.
+//
+// Solidity: function taskNonces(uint256 ) view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) TaskNonces(arg0 *big.Int) (*big.Int, error) {
+	return _TaskEscrowV2.Contract.TaskNonces(&_TaskEscrowV2.CallOpts, arg0)
+}
+
+// Tasks is a free data retrieval call binding the contract method 0x8d977672.
+//
+// Solidity: function tasks(uint256 ) view returns(address creator, address executor, uint256 totalAmount, uint256 paidAmount, bool cancelled)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) Tasks(opts *bind.CallOpts, arg0 *big.Int) (struct {
+	Creator     common.Address
+	Executor    common.Address
+	TotalAmount *big.Int
+	PaidAmount  *big.Int
+	Cancelled   bool
+}, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "tasks", arg0)
+
+	outstruct := new(struct {
+		Creator     common.Address
+		Executor    common.Address
+		TotalAmount *big.Int
+		PaidAmount  *big.Int
+		Cancelled   bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Creator = *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	outstruct.Executor = *abi.ConvertType(out[1], new(common.Address)).(*common.Address)
+	outstruct.TotalAmount = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.PaidAmount = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	outstruct.Cancelled = *abi.ConvertType(out[4], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// Tasks is a free data retrieval call binding the contract method 0x8d977672.
+//
+// Solidity: function tasks(uint256 ) view returns(address creator, address executor, uint256 totalAmount, uint256 paidAmount, bool cancelled)
+func (_TaskEscrowV2 *TaskEscrowV2Session) Tasks(arg0 *big.Int) (struct {
+	Creator     common.Address
+	Executor    common.Address
+	TotalAmount *big.Int
+	PaidAmount  *big.Int
+	Cancelled   bool
+}, error) {
+	return _TaskEscrowV2.Contract.Tasks(&_TaskEscrowV2.CallOpts, arg0)
+}
+
+// Tasks is a free data retrieval call binding the contract method 0x8d977672.
+//
+// Solidity: function tasks(uint256 ) view returns(address creator, address executor, uint256 totalAmount, uint256 paidAmount, bool cancelled)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) Tasks(arg0 *big.Int) (struct {
+	Creator     common.Address
+	Executor    common.Address
+	TotalAmount *big.Int
+	PaidAmount  *big.Int
+	Cancelled   bool
+}, error) {
+	return _TaskEscrowV2.Contract.Tasks(&_TaskEscrowV2.CallOpts, arg0)
+}
+
+// TeamHash is a free data retrieval call binding the contract method 0x5525f9ee.
+//
+// Solidity: function teamHash(uint256 taskId) view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) TeamHash(opts *bind.CallOpts, taskId *big.Int) ([32]byte, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "teamHash", taskId)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// TeamHash is a free data retrieval call binding the contract method 0x5525f9ee.
+//
+// Solidity: function teamHash(uint256 taskId) view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2Session) TeamHash(taskId *big.Int) ([32]byte, error) {
+	return _TaskEscrowV2.Contract.TeamHash(&_TaskEscrowV2.CallOpts, taskId)
+}
+
+// TeamHash is a free data retrieval call binding the contract method 0x5525f9ee.
+//
+// Solidity: function teamHash(uint256 taskId) view returns(bytes32)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) TeamHash(taskId *big.Int) ([32]byte, error) {
+	return _TaskEscrowV2.Contract.TeamHash(&_TaskEscrowV2.CallOpts, taskId)
+}
+
+// Token is a free data retrieval call binding the contract method 0xfc0c546a.
+//
+// Solidity: function token() view returns(address)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) Token(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "token")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Token is a free data retrieval call binding the contract method 0xfc0c546a.
+//
+// Solidity: function token() view returns(address)
+func (_TaskEscrowV2 *TaskEscrowV2Session) Token() (common.Address, error) {
+	return _TaskEscrowV2.Contract.Token(&_TaskEscrowV2.CallOpts)
+}
+
+// Token is a free data retrieval call binding the contract method 0xfc0c546a.
+//
+// Solidity: function token() view returns(address)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) Token() (common.Address, error) {
+	return _TaskEscrowV2.Contract.Token(&_TaskEscrowV2.CallOpts)
+}
+
+// TotalOutstanding is a free data retrieval call binding the contract method 0x16078d04.
+//
+// Solidity: function totalOutstanding() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) TotalOutstanding(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "totalOutstanding")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// TotalOutstanding is a free data retrieval call binding the contract method 0x16078d04.
+//
+// Solidity: function totalOutstanding() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) TotalOutstanding() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.TotalOutstanding(&_TaskEscrowV2.CallOpts)
+}
+
+// TotalOutstanding is a free data retrieval call binding the contract method 0x16078d04.
+//
+// Solidity: function totalOutstanding() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) TotalOutstanding() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.TotalOutstanding(&_TaskEscrowV2.CallOpts)
+}
+
+// WithdrawPeriodLimit is a free data retrieval call binding the contract method 0xc9347a88.
+//
+// Solidity: function withdrawPeriodLimit() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) WithdrawPeriodLimit(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "withdrawPeriodLimit")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// WithdrawPeriodLimit is a free data retrieval call binding the contract method 0xc9347a88.
+//
+// Solidity: function withdrawPeriodLimit() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) WithdrawPeriodLimit() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.WithdrawPeriodLimit(&_TaskEscrowV2.CallOpts)
+}
+
+// WithdrawPeriodLimit is a free data retrieval call binding the contract method 0xc9347a88.
+//
+// Solidity: function withdrawPeriodLimit() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) WithdrawPeriodLimit() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.WithdrawPeriodLimit(&_TaskEscrowV2.CallOpts)
+}
+
+// WithdrawPeriodStart is a free data retrieval call binding the contract method 0x8e725614.
+//
+// Solidity: function withdrawPeriodStart() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) WithdrawPeriodStart(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "withdrawPeriodStart")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// WithdrawPeriodStart is a free data retrieval call binding the contract method 0x8e725614.
+//
+// Solidity: function withdrawPeriodStart() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) WithdrawPeriodStart() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.WithdrawPeriodStart(&_TaskEscrowV2.CallOpts)
+}
+
+// WithdrawPeriodStart is a free data retrieval call binding the contract method 0x8e725614.
+//
+// Solidity: function withdrawPeriodStart() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) WithdrawPeriodStart() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.WithdrawPeriodStart(&_TaskEscrowV2.CallOpts)
+}
+
+// WithdrawableExcess is a free data retrieval call binding the contract method 0x69d0b172.
+//
+// Solidity: function withdrawableExcess() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) WithdrawableExcess(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "withdrawableExcess")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// WithdrawableExcess is a free data retrieval call binding the contract method 0x69d0b172.
+//
+// Solidity: function withdrawableExcess() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) WithdrawableExcess() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.WithdrawableExcess(&_TaskEscrowV2.CallOpts)
+}
+
+// WithdrawableExcess is a free data retrieval call binding the contract method 0x69d0b172.
+//
+// Solidity: function withdrawableExcess() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) WithdrawableExcess() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.WithdrawableExcess(&_TaskEscrowV2.CallOpts)
+}
+
+// Withdrawals is a free data retrieval call binding the contract method 0x5cc07076.
+//
+// Solidity: function withdrawals(uint256 ) view returns(address to, uint256 amount, uint256 executableAt, bool executed, bool cancelled)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) Withdrawals(opts *bind.CallOpts, arg0 *big.Int) (struct {
+	To           common.Address
+	Amount       *big.Int
+	ExecutableAt *big.Int
+	Executed     bool
+	Cancelled    bool
+}, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "withdrawals", arg0)
+
+	outstruct := new(struct {
+		To           common.Address
+		Amount       *big.Int
+		ExecutableAt *big.Int
+		Executed     bool
+		Cancelled    bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.To = *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	outstruct.Amount = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	outstruct.ExecutableAt = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.Executed = *abi.ConvertType(out[3], new(bool)).(*bool)
+	outstruct.Cancelled = *abi.ConvertType(out[4], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// Withdrawals is a free data retrieval call binding the contract method 0x5cc07076.
+//
+// Solidity: function withdrawals(uint256 ) view returns(address to, uint256 amount, uint256 executableAt, bool executed, bool cancelled)
+func (_TaskEscrowV2 *TaskEscrowV2Session) Withdrawals(arg0 *big.Int) (struct {
+	To           common.Address
+	Amount       *big.Int
+	ExecutableAt *big.Int
+	Executed     bool
+	Cancelled    bool
+}, error) {
+	return _TaskEscrowV2.Contract.Withdrawals(&_TaskEscrowV2.CallOpts, arg0)
+}
+
+// Withdrawals is a free data retrieval call binding the contract method 0x5cc07076.
+//
+// Solidity: function withdrawals(uint256 ) view returns(address to, uint256 amount, uint256 executableAt, bool executed, bool cancelled)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) Withdrawals(arg0 *big.Int) (struct {
+	To           common.Address
+	Amount       *big.Int
+	ExecutableAt *big.Int
+	Executed     bool
+	Cancelled    bool
+}, error) {
+	return _TaskEscrowV2.Contract.Withdrawals(&_TaskEscrowV2.CallOpts, arg0)
+}
+
+// WithdrawnInPeriod is a free data retrieval call binding the contract method 0x6242283e.
+//
+// Solidity: function withdrawnInPeriod() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Caller) WithdrawnInPeriod(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _TaskEscrowV2.contract.Call(opts, &out, "withdrawnInPeriod")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// WithdrawnInPeriod is a free data retrieval call binding the contract method 0x6242283e.
+//
+// Solidity: function withdrawnInPeriod() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) WithdrawnInPeriod() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.WithdrawnInPeriod(&_TaskEscrowV2.CallOpts)
+}
+
+// WithdrawnInPeriod is a free data retrieval call binding the contract method 0x6242283e.
+//
+// Solidity: function withdrawnInPeriod() view returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2CallerSession) WithdrawnInPeriod() (*big.Int, error) {
+	return _TaskEscrowV2.Contract.WithdrawnInPeriod(&_TaskEscrowV2.CallOpts)
+}
+
+// CancelEmergencyWithdraw is a paid mutator transaction binding the contract method 0x7aae1bb2.
+//
+// Solidity: function cancelEmergencyWithdraw(uint256 withdrawalId) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Transactor) CancelEmergencyWithdraw(opts *bind.TransactOpts, withdrawalId *big.Int) (*types.Transaction, error) {
+	return _TaskEscrowV2.contract.Transact(opts, "cancelEmergencyWithdraw", withdrawalId)
+}
+
+// CancelEmergencyWithdraw is a paid mutator transaction binding the contract method 0x7aae1bb2.
+//
+// Solidity: function cancelEmergencyWithdraw(uint256 withdrawalId) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Session) CancelEmergencyWithdraw(withdrawalId *big.Int) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.CancelEmergencyWithdraw(&_TaskEscrowV2.TransactOpts, withdrawalId)
+}
+
+// CancelEmergencyWithdraw is a paid mutator transaction binding the contract method 0x7aae1bb2.
+//
+// Solidity: function cancelEmergencyWithdraw(uint256 withdrawalId) returns()
+func (_TaskEscrowV2 *TaskEscrowV2TransactorSession) CancelEmergencyWithdraw(withdrawalId *big.Int) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.CancelEmergencyWithdraw(&_TaskEscrowV2.TransactOpts, withdrawalId)
+}
+
+// CancelTask is a paid mutator transaction binding the contract method 0x2929fd29.
+//
+// Solidity: function cancelTask(uint256 taskId, uint256 executorAmount, uint256 deadline, bytes creatorSignature, bytes executorSignature) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Transactor) CancelTask(opts *bind.TransactOpts, taskId *big.Int, executorAmount *big.Int, deadline *big.Int, creatorSignature []byte, executorSignature []byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.contract.Transact(opts, "cancelTask", taskId, executorAmount, deadline, creatorSignature, executorSignature)
+}
+
+// CancelTask is a paid mutator transaction binding the contract method 0x2929fd29.
+//
+// Solidity: function cancelTask(uint256 taskId, uint256 executorAmount, uint256 deadline, bytes creatorSignature, bytes executorSignature) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Session) CancelTask(taskId *big.Int, executorAmount *big.Int, deadline *big.Int, creatorSignature []byte, executorSignature []byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.CancelTask(&_TaskEscrowV2.TransactOpts, taskId, executorAmount, deadline, creatorSignature, executorSignature)
+}
+
+// CancelTask is a paid mutator transaction binding the contract method 0x2929fd29.
+//
+// Solidity: function cancelTask(uint256 taskId, uint256 executorAmount, uint256 deadline, bytes creatorSignature, bytes executorSignature) returns()
+func (_TaskEscrowV2 *TaskEscrowV2TransactorSession) CancelTask(taskId *big.Int, executorAmount *big.Int, deadline *big.Int, creatorSignature []byte, executorSignature []byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.CancelTask(&_TaskEscrowV2.TransactOpts, taskId, executorAmount, deadline, creatorSignature, executorSignature)
+}
+
+// ChangeExecutor is a paid mutator transaction binding the contract method 0x12f3e477.
+//
+// Solidity: function changeExecutor(uint256 taskId, address executor, uint256 deadline, bytes creatorSignature) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Transactor) ChangeExecutor(opts *bind.TransactOpts, taskId *big.Int, executor common.Address, deadline *big.Int, creatorSignature []byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.contract.Transact(opts, "changeExecutor", taskId, executor, deadline, creatorSignature)
+}
+
+// ChangeExecutor is a paid mutator transaction binding the contract method 0x12f3e477.
+//
+// Solidity: function changeExecutor(uint256 taskId, address executor, uint256 deadline, bytes creatorSignature) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Session) ChangeExecutor(taskId *big.Int, executor common.Address, deadline *big.Int, creatorSignature []byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.ChangeExecutor(&_TaskEscrowV2.TransactOpts, taskId, executor, deadline, creatorSignature)
+}
+
+// ChangeExecutor is a paid mutator transaction binding the contract method 0x12f3e477.
+//
+// Solidity: function changeExecutor(uint256 taskId, address executor, uint256 deadline, bytes creatorSignature) returns()
+func (_TaskEscrowV2 *TaskEscrowV2TransactorSession) ChangeExecutor(taskId *big.Int, executor common.Address, deadline *big.Int, creatorSignature []byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.ChangeExecutor(&_TaskEscrowV2.TransactOpts, taskId, executor, deadline, creatorSignature)
+}
+
+// CreateTask is a paid mutator transaction binding the contract method 0xbb375301.
+//
+// Solidity: function createTask(address creator, address executor, uint256 amount, uint16[] milestoneBps) returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Transactor) CreateTask(opts *bind.TransactOpts, creator common.Address, executor common.Address, amount *big.Int, milestoneBps []uint16) (*types.Transaction, error) {
+	return _TaskEscrowV2.contract.Transact(opts, "createTask", creator, executor, amount, milestoneBps)
+}
+
+// CreateTask is a paid mutator transaction binding the contract method 0xbb375301.
+//
+// Solidity: function createTask(address creator, address executor, uint256 amount, uint16[] milestoneBps) returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) CreateTask(creator common.Address, executor common.Address, amount *big.Int, milestoneBps []uint16) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.CreateTask(&_TaskEscrowV2.TransactOpts, creator, executor, amount, milestoneBps)
+}
+
+// CreateTask is a paid mutator transaction binding the contract method 0xbb375301.
+//
+// Solidity: function createTask(address creator, address executor, uint256 amount, uint16[] milestoneBps) returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2TransactorSession) CreateTask(creator common.Address, executor common.Address, amount *big.Int, milestoneBps []uint16) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.CreateTask(&_TaskEscrowV2.TransactOpts, creator, executor, amount, milestoneBps)
+}
+
+// CreateTaskWithPermit is a paid mutator transaction binding the contract method 0xbfdf3348.
+//
+// Solidity: function createTaskWithPermit(address creator, address executor, uint256 amount, uint16[] milestoneBps, uint256 deadline, uint8 v, bytes32 r, bytes32 s) returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Transactor) CreateTaskWithPermit(opts *bind.TransactOpts, creator common.Address, executor common.Address, amount *big.Int, milestoneBps []uint16, deadline *big.Int, v uint8, r [32]byte, s [32]byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.contract.Transact(opts, "createTaskWithPermit", creator, executor, amount, milestoneBps, deadline, v, r, s)
+}
+
+// CreateTaskWithPermit is a paid mutator transaction binding the contract method 0xbfdf3348.
+//
+// Solidity: function createTaskWithPermit(address creator, address executor, uint256 amount, uint16[] milestoneBps, uint256 deadline, uint8 v, bytes32 r, bytes32 s) returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) CreateTaskWithPermit(creator common.Address, executor common.Address, amount *big.Int, milestoneBps []uint16, deadline *big.Int, v uint8, r [32]byte, s [32]byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.CreateTaskWithPermit(&_TaskEscrowV2.TransactOpts, creator, executor, amount, milestoneBps, deadline, v, r, s)
+}
+
+// CreateTaskWithPermit is a paid mutator transaction binding the contract method 0xbfdf3348.
+//
+// Solidity: function createTaskWithPermit(address creator, address executor, uint256 amount, uint16[] milestoneBps, uint256 deadline, uint8 v, bytes32 r, bytes32 s) returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2TransactorSession) CreateTaskWithPermit(creator common.Address, executor common.Address, amount *big.Int, milestoneBps []uint16, deadline *big.Int, v uint8, r [32]byte, s [32]byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.CreateTaskWithPermit(&_TaskEscrowV2.TransactOpts, creator, executor, amount, milestoneBps, deadline, v, r, s)
+}
+
+// CreateTasks is a paid mutator transaction binding the contract method 0xcadd6728.
+//
+// Solidity: function createTasks(address creator, uint256[] amounts, uint16[][] milestoneBps) returns(uint256[])
+func (_TaskEscrowV2 *TaskEscrowV2Transactor) CreateTasks(opts *bind.TransactOpts, creator common.Address, amounts []*big.Int, milestoneBps [][]uint16) (*types.Transaction, error) {
+	return _TaskEscrowV2.contract.Transact(opts, "createTasks", creator, amounts, milestoneBps)
+}
+
+// CreateTasks is a paid mutator transaction binding the contract method 0xcadd6728.
+//
+// Solidity: function createTasks(address creator, uint256[] amounts, uint16[][] milestoneBps) returns(uint256[])
+func (_TaskEscrowV2 *TaskEscrowV2Session) CreateTasks(creator common.Address, amounts []*big.Int, milestoneBps [][]uint16) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.CreateTasks(&_TaskEscrowV2.TransactOpts, creator, amounts, milestoneBps)
+}
+
+// CreateTasks is a paid mutator transaction binding the contract method 0xcadd6728.
+//
+// Solidity: function createTasks(address creator, uint256[] amounts, uint16[][] milestoneBps) returns(uint256[])
+func (_TaskEscrowV2 *TaskEscrowV2TransactorSession) CreateTasks(creator common.Address, amounts []*big.Int, milestoneBps [][]uint16) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.CreateTasks(&_TaskEscrowV2.TransactOpts, creator, amounts, milestoneBps)
+}
+
+// CreateTasksWithPermit is a paid mutator transaction binding the contract method 0x86a909b6.
+//
+// Solidity: function createTasksWithPermit(address creator, uint256[] amounts, uint16[][] milestoneBps, uint256 deadline, uint8 v, bytes32 r, bytes32 s) returns(uint256[])
+func (_TaskEscrowV2 *TaskEscrowV2Transactor) CreateTasksWithPermit(opts *bind.TransactOpts, creator common.Address, amounts []*big.Int, milestoneBps [][]uint16, deadline *big.Int, v uint8, r [32]byte, s [32]byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.contract.Transact(opts, "createTasksWithPermit", creator, amounts, milestoneBps, deadline, v, r, s)
+}
+
+// CreateTasksWithPermit is a paid mutator transaction binding the contract method 0x86a909b6.
+//
+// Solidity: function createTasksWithPermit(address creator, uint256[] amounts, uint16[][] milestoneBps, uint256 deadline, uint8 v, bytes32 r, bytes32 s) returns(uint256[])
+func (_TaskEscrowV2 *TaskEscrowV2Session) CreateTasksWithPermit(creator common.Address, amounts []*big.Int, milestoneBps [][]uint16, deadline *big.Int, v uint8, r [32]byte, s [32]byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.CreateTasksWithPermit(&_TaskEscrowV2.TransactOpts, creator, amounts, milestoneBps, deadline, v, r, s)
+}
+
+// CreateTasksWithPermit is a paid mutator transaction binding the contract method 0x86a909b6.
+//
+// Solidity: function createTasksWithPermit(address creator, uint256[] amounts, uint16[][] milestoneBps, uint256 deadline, uint8 v, bytes32 r, bytes32 s) returns(uint256[])
+func (_TaskEscrowV2 *TaskEscrowV2TransactorSession) CreateTasksWithPermit(creator common.Address, amounts []*big.Int, milestoneBps [][]uint16, deadline *big.Int, v uint8, r [32]byte, s [32]byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.CreateTasksWithPermit(&_TaskEscrowV2.TransactOpts, creator, amounts, milestoneBps, deadline, v, r, s)
+}
+
+// ExecuteEmergencyWithdraw is a paid mutator transaction binding the contract method 0x582ee98d.
+//
+// Solidity: function executeEmergencyWithdraw(uint256 withdrawalId) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Transactor) ExecuteEmergencyWithdraw(opts *bind.TransactOpts, withdrawalId *big.Int) (*types.Transaction, error) {
+	return _TaskEscrowV2.contract.Transact(opts, "executeEmergencyWithdraw", withdrawalId)
+}
+
+// ExecuteEmergencyWithdraw is a paid mutator transaction binding the contract method 0x582ee98d.
+//
+// Solidity: function executeEmergencyWithdraw(uint256 withdrawalId) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Session) ExecuteEmergencyWithdraw(withdrawalId *big.Int) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.ExecuteEmergencyWithdraw(&_TaskEscrowV2.TransactOpts, withdrawalId)
+}
+
+// ExecuteEmergencyWithdraw is a paid mutator transaction binding the contract method 0x582ee98d.
+//
+// Solidity: function executeEmergencyWithdraw(uint256 withdrawalId) returns()
+func (_TaskEscrowV2 *TaskEscrowV2TransactorSession) ExecuteEmergencyWithdraw(withdrawalId *big.Int) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.ExecuteEmergencyWithdraw(&_TaskEscrowV2.TransactOpts, withdrawalId)
+}
+
+// ProposeEmergencyWithdraw is a paid mutator transaction binding the contract method 0xf8a76724.
+//
+// Solidity: function proposeEmergencyWithdraw(address to, uint256 amount) returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Transactor) ProposeEmergencyWithdraw(opts *bind.TransactOpts, to common.Address, amount *big.Int) (*types.Transaction, error) {
+	return _TaskEscrowV2.contract.Transact(opts, "proposeEmergencyWithdraw", to, amount)
+}
+
+// ProposeEmergencyWithdraw is a paid mutator transaction binding the contract method 0xf8a76724.
+//
+// Solidity: function proposeEmergencyWithdraw(address to, uint256 amount) returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2Session) ProposeEmergencyWithdraw(to common.Address, amount *big.Int) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.ProposeEmergencyWithdraw(&_TaskEscrowV2.TransactOpts, to, amount)
+}
+
+// ProposeEmergencyWithdraw is a paid mutator transaction binding the contract method 0xf8a76724.
+//
+// Solidity: function proposeEmergencyWithdraw(address to, uint256 amount) returns(uint256)
+func (_TaskEscrowV2 *TaskEscrowV2TransactorSession) ProposeEmergencyWithdraw(to common.Address, amount *big.Int) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.ProposeEmergencyWithdraw(&_TaskEscrowV2.TransactOpts, to, amount)
+}
+
+// RefundPartial is a paid mutator transaction binding the contract method 0xe9ee6485.
+//
+// Solidity: function refundPartial(uint256 taskId, uint256 amount) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Transactor) RefundPartial(opts *bind.TransactOpts, taskId *big.Int, amount *big.Int) (*types.Transaction, error) {
+	return _TaskEscrowV2.contract.Transact(opts, "refundPartial", taskId, amount)
+}
+
+// RefundPartial is a paid mutator transaction binding the contract method 0xe9ee6485.
+//
+// Solidity: function refundPartial(uint256 taskId, uint256 amount) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Session) RefundPartial(taskId *big.Int, amount *big.Int) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.RefundPartial(&_TaskEscrowV2.TransactOpts, taskId, amount)
+}
+
+// RefundPartial is a paid mutator transaction binding the contract method 0xe9ee6485.
+//
+// Solidity: function refundPartial(uint256 taskId, uint256 amount) returns()
+func (_TaskEscrowV2 *TaskEscrowV2TransactorSession) RefundPartial(taskId *big.Int, amount *big.Int) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.RefundPartial(&_TaskEscrowV2.TransactOpts, taskId, amount)
+}
+
+// ReleaseMilestone is a paid mutator transaction binding the contract method 0x086913b1.
+//
+// Solidity: function releaseMilestone(uint256 taskId, uint256 index, uint256 deadline, bytes creatorSignature) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Transactor) ReleaseMilestone(opts *bind.TransactOpts, taskId *big.Int, index *big.Int, deadline *big.Int, creatorSignature []byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.contract.Transact(opts, "releaseMilestone", taskId, index, deadline, creatorSignature)
+}
+
+// ReleaseMilestone is a paid mutator transaction binding the contract method 0x086913b1.
+//
+// Solidity: function releaseMilestone(uint256 taskId, uint256 index, uint256 deadline, bytes creatorSignature) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Session) ReleaseMilestone(taskId *big.Int, index *big.Int, deadline *big.Int, creatorSignature []byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.ReleaseMilestone(&_TaskEscrowV2.TransactOpts, taskId, index, deadline, creatorSignature)
+}
+
+// ReleaseMilestone is a paid mutator transaction binding the contract method 0x086913b1.
+//
+// Solidity: function releaseMilestone(uint256 taskId, uint256 index, uint256 deadline, bytes creatorSignature) returns()
+func (_TaskEscrowV2 *TaskEscrowV2TransactorSession) ReleaseMilestone(taskId *big.Int, index *big.Int, deadline *big.Int, creatorSignature []byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.ReleaseMilestone(&_TaskEscrowV2.TransactOpts, taskId, index, deadline, creatorSignature)
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_TaskEscrowV2 *TaskEscrowV2Transactor) RenounceOwnership(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _TaskEscrowV2.contract.Transact(opts, "renounceOwnership")
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_TaskEscrowV2 *TaskEscrowV2Session) RenounceOwnership() (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.RenounceOwnership(&_TaskEscrowV2.TransactOpts)
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_TaskEscrowV2 *TaskEscrowV2TransactorSession) RenounceOwnership() (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.RenounceOwnership(&_TaskEscrowV2.TransactOpts)
+}
+
+// ResignExecutor is a paid mutator transaction binding the contract method 0x5677cf47.
+//
+// Solidity: function resignExecutor(uint256 taskId, uint256 deadline, bytes executorSignature) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Transactor) ResignExecutor(opts *bind.TransactOpts, taskId *big.Int, deadline *big.Int, executorSignature []byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.contract.Transact(opts, "resignExecutor", taskId, deadline, executorSignature)
+}
+
+// ResignExecutor is a paid mutator transaction binding the contract method 0x5677cf47.
+//
+// Solidity: function resignExecutor(uint256 taskId, uint256 deadline, bytes executorSignature) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Session) ResignExecutor(taskId *big.Int, deadline *big.Int, executorSignature []byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.ResignExecutor(&_TaskEscrowV2.TransactOpts, taskId, deadline, executorSignature)
+}
+
+// ResignExecutor is a paid mutator transaction binding the contract method 0x5677cf47.
+//
+// Solidity: function resignExecutor(uint256 taskId, uint256 deadline, bytes executorSignature) returns()
+func (_TaskEscrowV2 *TaskEscrowV2TransactorSession) ResignExecutor(taskId *big.Int, deadline *big.Int, executorSignature []byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.ResignExecutor(&_TaskEscrowV2.TransactOpts, taskId, deadline, executorSignature)
+}
+
+// SetExecutor is a paid mutator transaction binding the contract method 0xc37874cb.
+//
+// Solidity: function setExecutor(uint256 taskId, address executor) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Transactor) SetExecutor(opts *bind.TransactOpts, taskId *big.Int, executor common.Address) (*types.Transaction, error) {
+	return _TaskEscrowV2.contract.Transact(opts, "setExecutor", taskId, executor)
+}
+
+// SetExecutor is a paid mutator transaction binding the contract method 0xc37874cb.
+//
+// Solidity: function setExecutor(uint256 taskId, address executor) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Session) SetExecutor(taskId *big.Int, executor common.Address) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.SetExecutor(&_TaskEscrowV2.TransactOpts, taskId, executor)
+}
+
+// SetExecutor is a paid mutator transaction binding the contract method 0xc37874cb.
+//
+// Solidity: function setExecutor(uint256 taskId, address executor) returns()
+func (_TaskEscrowV2 *TaskEscrowV2TransactorSession) SetExecutor(taskId *big.Int, executor common.Address) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.SetExecutor(&_TaskEscrowV2.TransactOpts, taskId, executor)
+}
+
+// SetExecutorTeam is a paid mutator transaction binding the contract method 0x36f790d5.
+//
+// Solidity: function setExecutorTeam(uint256 taskId, address[] members, uint16[] shareBps) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Transactor) SetExecutorTeam(opts *bind.TransactOpts, taskId *big.Int, members []common.Address, shareBps []uint16) (*types.Transaction, error) {
+	return _TaskEscrowV2.contract.Transact(opts, "setExecutorTeam", taskId, members, shareBps)
+}
+
+// SetExecutorTeam is a paid mutator transaction binding the contract method 0x36f790d5.
+//
+// Solidity: function setExecutorTeam(uint256 taskId, address[] members, uint16[] shareBps) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Session) SetExecutorTeam(taskId *big.Int, members []common.Address, shareBps []uint16) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.SetExecutorTeam(&_TaskEscrowV2.TransactOpts, taskId, members, shareBps)
+}
+
+// SetExecutorTeam is a paid mutator transaction binding the contract method 0x36f790d5.
+//
+// Solidity: function setExecutorTeam(uint256 taskId, address[] members, uint16[] shareBps) returns()
+func (_TaskEscrowV2 *TaskEscrowV2TransactorSession) SetExecutorTeam(taskId *big.Int, members []common.Address, shareBps []uint16) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.SetExecutorTeam(&_TaskEscrowV2.TransactOpts, taskId, members, shareBps)
+}
+
+// TopUp is a paid mutator transaction binding the contract method 0xf34ce2fd.
+//
+// Solidity: function topUp(uint256 taskId, uint256 amount, uint256 deadline, bytes creatorSignature) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Transactor) TopUp(opts *bind.TransactOpts, taskId *big.Int, amount *big.Int, deadline *big.Int, creatorSignature []byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.contract.Transact(opts, "topUp", taskId, amount, deadline, creatorSignature)
+}
+
+// TopUp is a paid mutator transaction binding the contract method 0xf34ce2fd.
+//
+// Solidity: function topUp(uint256 taskId, uint256 amount, uint256 deadline, bytes creatorSignature) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Session) TopUp(taskId *big.Int, amount *big.Int, deadline *big.Int, creatorSignature []byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.TopUp(&_TaskEscrowV2.TransactOpts, taskId, amount, deadline, creatorSignature)
+}
+
+// TopUp is a paid mutator transaction binding the contract method 0xf34ce2fd.
+//
+// Solidity: function topUp(uint256 taskId, uint256 amount, uint256 deadline, bytes creatorSignature) returns()
+func (_TaskEscrowV2 *TaskEscrowV2TransactorSession) TopUp(taskId *big.Int, amount *big.Int, deadline *big.Int, creatorSignature []byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.TopUp(&_TaskEscrowV2.TransactOpts, taskId, amount, deadline, creatorSignature)
+}
+
+// TopUpWithPermit is a paid mutator transaction binding the contract method 0x001ff990.
+//
+// Solidity: function topUpWithPermit(uint256 taskId, uint256 amount, uint256 deadline, uint8 v, bytes32 r, bytes32 s) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Transactor) TopUpWithPermit(opts *bind.TransactOpts, taskId *big.Int, amount *big.Int, deadline *big.Int, v uint8, r [32]byte, s [32]byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.contract.Transact(opts, "topUpWithPermit", taskId, amount, deadline, v, r, s)
+}
+
+// TopUpWithPermit is a paid mutator transaction binding the contract method 0x001ff990.
+//
+// Solidity: function topUpWithPermit(uint256 taskId, uint256 amount, uint256 deadline, uint8 v, bytes32 r, bytes32 s) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Session) TopUpWithPermit(taskId *big.Int, amount *big.Int, deadline *big.Int, v uint8, r [32]byte, s [32]byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.TopUpWithPermit(&_TaskEscrowV2.TransactOpts, taskId, amount, deadline, v, r, s)
+}
+
+// TopUpWithPermit is a paid mutator transaction binding the contract method 0x001ff990.
+//
+// Solidity: function topUpWithPermit(uint256 taskId, uint256 amount, uint256 deadline, uint8 v, bytes32 r, bytes32 s) returns()
+func (_TaskEscrowV2 *TaskEscrowV2TransactorSession) TopUpWithPermit(taskId *big.Int, amount *big.Int, deadline *big.Int, v uint8, r [32]byte, s [32]byte) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.TopUpWithPermit(&_TaskEscrowV2.TransactOpts, taskId, amount, deadline, v, r, s)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Transactor) TransferOwnership(opts *bind.TransactOpts, newOwner common.Address) (*types.Transaction, error) {
+	return _TaskEscrowV2.contract.Transact(opts, "transferOwnership", newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_TaskEscrowV2 *TaskEscrowV2Session) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.TransferOwnership(&_TaskEscrowV2.TransactOpts, newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_TaskEscrowV2 *TaskEscrowV2TransactorSession) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
+	return _TaskEscrowV2.Contract.TransferOwnership(&_TaskEscrowV2.TransactOpts, newOwner)
+}
+
+// TaskEscrowV2EIP712DomainChangedIterator is returned from FilterEIP712DomainChanged and is used to iterate over the raw logs and unpacked data for EIP712DomainChanged events raised by the TaskEscrowV2 contract.
+type TaskEscrowV2EIP712DomainChangedIterator struct {
+	Event *TaskEscrowV2EIP712DomainChanged // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *TaskEscrowV2EIP712DomainChangedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(TaskEscrowV2EIP712DomainChanged)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(TaskEscrowV2EIP712DomainChanged)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *TaskEscrowV2EIP712DomainChangedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *TaskEscrowV2EIP712DomainChangedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// TaskEscrowV2EIP712DomainChanged represents a EIP712DomainChanged event raised by the TaskEscrowV2 contract.
+type TaskEscrowV2EIP712DomainChanged struct {
+	Raw types.Log // Blockchain specific contextual infos
+}
+
+// FilterEIP712DomainChanged is a free log retrieval operation binding the contract event 0x0a6387c9ea3628b88a633bb4f3b151770f70085117a15f9bf3787cda53f13d31.
+//
+// Solidity: event EIP712DomainChanged()
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) FilterEIP712DomainChanged(opts *bind.FilterOpts) (*TaskEscrowV2EIP712DomainChangedIterator, error) {
+
+	logs, sub, err := _TaskEscrowV2.contract.FilterLogs(opts, "EIP712DomainChanged")
+	if err != nil {
+		return nil, err
+	}
+	return &TaskEscrowV2EIP712DomainChangedIterator{contract: _TaskEscrowV2.contract, event: "EIP712DomainChanged", logs: logs, sub: sub}, nil
+}
+
+// WatchEIP712DomainChanged is a free log subscription operation binding the contract event 0x0a6387c9ea3628b88a633bb4f3b151770f70085117a15f9bf3787cda53f13d31.
+//
+// Solidity: event EIP712DomainChanged()
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) WatchEIP712DomainChanged(opts *bind.WatchOpts, sink chan<- *TaskEscrowV2EIP712DomainChanged) (event.Subscription, error) {
+
+	logs, sub, err := _TaskEscrowV2.contract.WatchLogs(opts, "EIP712DomainChanged")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(TaskEscrowV2EIP712DomainChanged)
+				if err := _TaskEscrowV2.contract.UnpackLog(event, "EIP712DomainChanged", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseEIP712DomainChanged is a log parse operation binding the contract event 0x0a6387c9ea3628b88a633bb4f3b151770f70085117a15f9bf3787cda53f13d31.
+//
+// Solidity: event EIP712DomainChanged()
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) ParseEIP712DomainChanged(log types.Log) (*TaskEscrowV2EIP712DomainChanged, error) {
+	event := new(TaskEscrowV2EIP712DomainChanged)
+	if err := _TaskEscrowV2.contract.UnpackLog(event, "EIP712DomainChanged", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// TaskEscrowV2ExecutorSetIterator is returned from FilterExecutorSet and is used to iterate over the raw logs and unpacked data for ExecutorSet events raised by the TaskEscrowV2 contract.
+type TaskEscrowV2ExecutorSetIterator struct {
+	Event *TaskEscrowV2ExecutorSet // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *TaskEscrowV2ExecutorSetIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(TaskEscrowV2ExecutorSet)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(TaskEscrowV2ExecutorSet)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *TaskEscrowV2ExecutorSetIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *TaskEscrowV2ExecutorSetIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// TaskEscrowV2ExecutorSet represents a ExecutorSet event raised by the TaskEscrowV2 contract.
+type TaskEscrowV2ExecutorSet struct {
+	TaskId   *big.Int
+	Executor common.Address
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterExecutorSet is a free log retrieval operation binding the contract event 0x5a7f91ad5127433cb5cf0dad01983c2100378fe0ccfb87cf6c9f007816c84a04.
+//
+// Solidity: event ExecutorSet(uint256 indexed taskId, address indexed executor)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) FilterExecutorSet(opts *bind.FilterOpts, taskId []*big.Int, executor []common.Address) (*TaskEscrowV2ExecutorSetIterator, error) {
+
+	var taskIdRule []interface{}
+	for _, taskIdItem := range taskId {
+		taskIdRule = append(taskIdRule, taskIdItem)
+	}
+	var executorRule []interface{}
+	for _, executorItem := range executor {
+		executorRule = append(executorRule, executorItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.FilterLogs(opts, "ExecutorSet", taskIdRule, executorRule)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskEscrowV2ExecutorSetIterator{contract: _TaskEscrowV2.contract, event: "ExecutorSet", logs: logs, sub: sub}, nil
+}
+
+// WatchExecutorSet is a free log subscription operation binding the contract event 0x5a7f91ad5127433cb5cf0dad01983c2100378fe0ccfb87cf6c9f007816c84a04.
+//
+// Solidity: event ExecutorSet(uint256 indexed taskId, address indexed executor)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) WatchExecutorSet(opts *bind.WatchOpts, sink chan<- *TaskEscrowV2ExecutorSet, taskId []*big.Int, executor []common.Address) (event.Subscription, error) {
+
+	var taskIdRule []interface{}
+	for _, taskIdItem := range taskId {
+		taskIdRule = append(taskIdRule, taskIdItem)
+	}
+	var executorRule []interface{}
+	for _, executorItem := range executor {
+		executorRule = append(executorRule, executorItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.WatchLogs(opts, "ExecutorSet", taskIdRule, executorRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(TaskEscrowV2ExecutorSet)
+				if err := _TaskEscrowV2.contract.UnpackLog(event, "ExecutorSet", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseExecutorSet is a log parse operation binding the contract event 0x5a7f91ad5127433cb5cf0dad01983c2100378fe0ccfb87cf6c9f007816c84a04.
+//
+// Solidity: event ExecutorSet(uint256 indexed taskId, address indexed executor)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) ParseExecutorSet(log types.Log) (*TaskEscrowV2ExecutorSet, error) {
+	event := new(TaskEscrowV2ExecutorSet)
+	if err := _TaskEscrowV2.contract.UnpackLog(event, "ExecutorSet", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// TaskEscrowV2MilestonePaidIterator is returned from FilterMilestonePaid and is used to iterate over the raw logs and unpacked data for MilestonePaid events raised by the TaskEscrowV2 contract.
+type TaskEscrowV2MilestonePaidIterator struct {
+	Event *TaskEscrowV2MilestonePaid // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *TaskEscrowV2MilestonePaidIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(TaskEscrowV2MilestonePaid)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(TaskEscrowV2MilestonePaid)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *TaskEscrowV2MilestonePaidIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *TaskEscrowV2MilestonePaidIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// TaskEscrowV2MilestonePaid represents a MilestonePaid event raised by the TaskEscrowV2 contract.
+type TaskEscrowV2MilestonePaid struct {
+	TaskId    *big.Int
+	Executor  common.Address
+	Amount    *big.Int
+	TotalPaid *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterMilestonePaid is a free log retrieval operation binding the contract event 0xa5c2138f4ee89547657e692c9d954668da150bf271d1e1382addcb9bb4233c37.
+//
+// Solidity: event MilestonePaid(uint256 indexed taskId, address indexed executor, uint256 amount, uint256 totalPaid)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) FilterMilestonePaid(opts *bind.FilterOpts, taskId []*big.Int, executor []common.Address) (*TaskEscrowV2MilestonePaidIterator, error) {
+
+	var taskIdRule []interface{}
+	for _, taskIdItem := range taskId {
+		taskIdRule = append(taskIdRule, taskIdItem)
+	}
+	var executorRule []interface{}
+	for _, executorItem := range executor {
+		executorRule = append(executorRule, executorItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.FilterLogs(opts, "MilestonePaid", taskIdRule, executorRule)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskEscrowV2MilestonePaidIterator{contract: _TaskEscrowV2.contract, event: "MilestonePaid", logs: logs, sub: sub}, nil
+}
+
+// WatchMilestonePaid is a free log subscription operation binding the contract event 0xa5c2138f4ee89547657e692c9d954668da150bf271d1e1382addcb9bb4233c37.
+//
+// Solidity: event MilestonePaid(uint256 indexed taskId, address indexed executor, uint256 amount, uint256 totalPaid)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) WatchMilestonePaid(opts *bind.WatchOpts, sink chan<- *TaskEscrowV2MilestonePaid, taskId []*big.Int, executor []common.Address) (event.Subscription, error) {
+
+	var taskIdRule []interface{}
+	for _, taskIdItem := range taskId {
+		taskIdRule = append(taskIdRule, taskIdItem)
+	}
+	var executorRule []interface{}
+	for _, executorItem := range executor {
+		executorRule = append(executorRule, executorItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.WatchLogs(opts, "MilestonePaid", taskIdRule, executorRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(TaskEscrowV2MilestonePaid)
+				if err := _TaskEscrowV2.contract.UnpackLog(event, "MilestonePaid", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseMilestonePaid is a log parse operation binding the contract event 0xa5c2138f4ee89547657e692c9d954668da150bf271d1e1382addcb9bb4233c37.
+//
+// Solidity: event MilestonePaid(uint256 indexed taskId, address indexed executor, uint256 amount, uint256 totalPaid)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) ParseMilestonePaid(log types.Log) (*TaskEscrowV2MilestonePaid, error) {
+	event := new(TaskEscrowV2MilestonePaid)
+	if err := _TaskEscrowV2.contract.UnpackLog(event, "MilestonePaid", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// TaskEscrowV2MilestoneReleasedIterator is returned from FilterMilestoneReleased and is used to iterate over the raw logs and unpacked data for MilestoneReleased events raised by the TaskEscrowV2 contract.
+type TaskEscrowV2MilestoneReleasedIterator struct {
+	Event *TaskEscrowV2MilestoneReleased // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *TaskEscrowV2MilestoneReleasedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(TaskEscrowV2MilestoneReleased)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(TaskEscrowV2MilestoneReleased)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *TaskEscrowV2MilestoneReleasedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *TaskEscrowV2MilestoneReleasedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// TaskEscrowV2MilestoneReleased represents a MilestoneReleased event raised by the TaskEscrowV2 contract.
+type TaskEscrowV2MilestoneReleased struct {
+	TaskId *big.Int
+	Index  *big.Int
+	Amount *big.Int
+	Raw    types.Log // Blockchain specific contextual infos
+}
+
+// FilterMilestoneReleased is a free log retrieval operation binding the contract event 0xd33fcd27cbfb73a2d9058c968b560ccb306500bf536133802048489ecc7355e9.
+//
+// Solidity: event MilestoneReleased(uint256 indexed taskId, uint256 indexed index, uint256 amount)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) FilterMilestoneReleased(opts *bind.FilterOpts, taskId []*big.Int, index []*big.Int) (*TaskEscrowV2MilestoneReleasedIterator, error) {
+
+	var taskIdRule []interface{}
+	for _, taskIdItem := range taskId {
+		taskIdRule = append(taskIdRule, taskIdItem)
+	}
+	var indexRule []interface{}
+	for _, indexItem := range index {
+		indexRule = append(indexRule, indexItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.FilterLogs(opts, "MilestoneReleased", taskIdRule, indexRule)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskEscrowV2MilestoneReleasedIterator{contract: _TaskEscrowV2.contract, event: "MilestoneReleased", logs: logs, sub: sub}, nil
+}
+
+// WatchMilestoneReleased is a free log subscription operation binding the contract event 0xd33fcd27cbfb73a2d9058c968b560ccb306500bf536133802048489ecc7355e9.
+//
+// Solidity: event MilestoneReleased(uint256 indexed taskId, uint256 indexed index, uint256 amount)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) WatchMilestoneReleased(opts *bind.WatchOpts, sink chan<- *TaskEscrowV2MilestoneReleased, taskId []*big.Int, index []*big.Int) (event.Subscription, error) {
+
+	var taskIdRule []interface{}
+	for _, taskIdItem := range taskId {
+		taskIdRule = append(taskIdRule, taskIdItem)
+	}
+	var indexRule []interface{}
+	for _, indexItem := range index {
+		indexRule = append(indexRule, indexItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.WatchLogs(opts, "MilestoneReleased", taskIdRule, indexRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(TaskEscrowV2MilestoneReleased)
+				if err := _TaskEscrowV2.contract.UnpackLog(event, "MilestoneReleased", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseMilestoneReleased is a log parse operation binding the contract event 0xd33fcd27cbfb73a2d9058c968b560ccb306500bf536133802048489ecc7355e9.
+//
+// Solidity: event MilestoneReleased(uint256 indexed taskId, uint256 indexed index, uint256 amount)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) ParseMilestoneReleased(log types.Log) (*TaskEscrowV2MilestoneReleased, error) {
+	event := new(TaskEscrowV2MilestoneReleased)
+	if err := _TaskEscrowV2.contract.UnpackLog(event, "MilestoneReleased", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// TaskEscrowV2OwnershipTransferredIterator is returned from FilterOwnershipTransferred and is used to iterate over the raw logs and unpacked data for OwnershipTransferred events raised by the TaskEscrowV2 contract.
+type TaskEscrowV2OwnershipTransferredIterator struct {
+	Event *TaskEscrowV2OwnershipTransferred // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *TaskEscrowV2OwnershipTransferredIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(TaskEscrowV2OwnershipTransferred)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(TaskEscrowV2OwnershipTransferred)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *TaskEscrowV2OwnershipTransferredIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *TaskEscrowV2OwnershipTransferredIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// TaskEscrowV2OwnershipTransferred represents a OwnershipTransferred event raised by the TaskEscrowV2 contract.
+type TaskEscrowV2OwnershipTransferred struct {
+	PreviousOwner common.Address
+	NewOwner      common.Address
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterOwnershipTransferred is a free log retrieval operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) FilterOwnershipTransferred(opts *bind.FilterOpts, previousOwner []common.Address, newOwner []common.Address) (*TaskEscrowV2OwnershipTransferredIterator, error) {
+
+	var previousOwnerRule []interface{}
+	for _, previousOwnerItem := range previousOwner {
+		previousOwnerRule = append(previousOwnerRule, previousOwnerItem)
+	}
+	var newOwnerRule []interface{}
+	for _, newOwnerItem := range newOwner {
+		newOwnerRule = append(newOwnerRule, newOwnerItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.FilterLogs(opts, "OwnershipTransferred", previousOwnerRule, newOwnerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskEscrowV2OwnershipTransferredIterator{contract: _TaskEscrowV2.contract, event: "OwnershipTransferred", logs: logs, sub: sub}, nil
+}
+
+// WatchOwnershipTransferred is a free log subscription operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) WatchOwnershipTransferred(opts *bind.WatchOpts, sink chan<- *TaskEscrowV2OwnershipTransferred, previousOwner []common.Address, newOwner []common.Address) (event.Subscription, error) {
+
+	var previousOwnerRule []interface{}
+	for _, previousOwnerItem := range previousOwner {
+		previousOwnerRule = append(previousOwnerRule, previousOwnerItem)
+	}
+	var newOwnerRule []interface{}
+	for _, newOwnerItem := range newOwner {
+		newOwnerRule = append(newOwnerRule, newOwnerItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.WatchLogs(opts, "OwnershipTransferred", previousOwnerRule, newOwnerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(TaskEscrowV2OwnershipTransferred)
+				if err := _TaskEscrowV2.contract.UnpackLog(event, "OwnershipTransferred", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOwnershipTransferred is a log parse operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) ParseOwnershipTransferred(log types.Log) (*TaskEscrowV2OwnershipTransferred, error) {
+	event := new(TaskEscrowV2OwnershipTransferred)
+	if err := _TaskEscrowV2.contract.UnpackLog(event, "OwnershipTransferred", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// TaskEscrowV2TaskCancelledIterator is returned from FilterTaskCancelled and is used to iterate over the raw logs and unpacked data for TaskCancelled events raised by the TaskEscrowV2 contract.
+type TaskEscrowV2TaskCancelledIterator struct {
+	Event *TaskEscrowV2TaskCancelled // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *TaskEscrowV2TaskCancelledIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(TaskEscrowV2TaskCancelled)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(TaskEscrowV2TaskCancelled)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *TaskEscrowV2TaskCancelledIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *TaskEscrowV2TaskCancelledIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// TaskEscrowV2TaskCancelled represents a TaskCancelled event raised by the TaskEscrowV2 contract.
+type TaskEscrowV2TaskCancelled struct {
+	TaskId         *big.Int
+	ExecutorAmount *big.Int
+	CreatorRefund  *big.Int
+	Raw            types.Log // Blockchain specific contextual infos
+}
+
+// FilterTaskCancelled is a free log retrieval operation binding the contract event 0x99a4b4cbd1e738b902f770df96bb758050edc0dcd3531a19ba389de9dd89553a.
+//
+// Solidity: event TaskCancelled(uint256 indexed taskId, uint256 executorAmount, uint256 creatorRefund)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) FilterTaskCancelled(opts *bind.FilterOpts, taskId []*big.Int) (*TaskEscrowV2TaskCancelledIterator, error) {
+
+	var taskIdRule []interface{}
+	for _, taskIdItem := range taskId {
+		taskIdRule = append(taskIdRule, taskIdItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.FilterLogs(opts, "TaskCancelled", taskIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskEscrowV2TaskCancelledIterator{contract: _TaskEscrowV2.contract, event: "TaskCancelled", logs: logs, sub: sub}, nil
+}
+
+// WatchTaskCancelled is a free log subscription operation binding the contract event 0x99a4b4cbd1e738b902f770df96bb758050edc0dcd3531a19ba389de9dd89553a.
+//
+// Solidity: event TaskCancelled(uint256 indexed taskId, uint256 executorAmount, uint256 creatorRefund)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) WatchTaskCancelled(opts *bind.WatchOpts, sink chan<- *TaskEscrowV2TaskCancelled, taskId []*big.Int) (event.Subscription, error) {
+
+	var taskIdRule []interface{}
+	for _, taskIdItem := range taskId {
+		taskIdRule = append(taskIdRule, taskIdItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.WatchLogs(opts, "TaskCancelled", taskIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(TaskEscrowV2TaskCancelled)
+				if err := _TaskEscrowV2.contract.UnpackLog(event, "TaskCancelled", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseTaskCancelled is a log parse operation binding the contract event 0x99a4b4cbd1e738b902f770df96bb758050edc0dcd3531a19ba389de9dd89553a.
+//
+// Solidity: event TaskCancelled(uint256 indexed taskId, uint256 executorAmount, uint256 creatorRefund)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) ParseTaskCancelled(log types.Log) (*TaskEscrowV2TaskCancelled, error) {
+	event := new(TaskEscrowV2TaskCancelled)
+	if err := _TaskEscrowV2.contract.UnpackLog(event, "TaskCancelled", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// TaskEscrowV2TaskCreatedIterator is returned from FilterTaskCreated and is used to iterate over the raw logs and unpacked data for TaskCreated events raised by the TaskEscrowV2 contract.
+type TaskEscrowV2TaskCreatedIterator struct {
+	Event *TaskEscrowV2TaskCreated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *TaskEscrowV2TaskCreatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(TaskEscrowV2TaskCreated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(TaskEscrowV2TaskCreated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *TaskEscrowV2TaskCreatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *TaskEscrowV2TaskCreatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// TaskEscrowV2TaskCreated represents a TaskCreated event raised by the TaskEscrowV2 contract.
+type TaskEscrowV2TaskCreated struct {
+	TaskId   *big.Int
+	Creator  common.Address
+	Executor common.Address
+	Amount   *big.Int
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterTaskCreated is a free log retrieval operation binding the contract event 0xf3efa663e8763e3719e2bdc58b7fdc03d43b6fcec97b7bcf371e6a4ea8704488.
+//
+// Solidity: event TaskCreated(uint256 indexed taskId, address indexed creator, address indexed executor, uint256 amount)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) FilterTaskCreated(opts *bind.FilterOpts, taskId []*big.Int, creator []common.Address, executor []common.Address) (*TaskEscrowV2TaskCreatedIterator, error) {
+
+	var taskIdRule []interface{}
+	for _, taskIdItem := range taskId {
+		taskIdRule = append(taskIdRule, taskIdItem)
+	}
+	var creatorRule []interface{}
+	for _, creatorItem := range creator {
+		creatorRule = append(creatorRule, creatorItem)
+	}
+	var executorRule []interface{}
+	for _, executorItem := range executor {
+		executorRule = append(executorRule, executorItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.FilterLogs(opts, "TaskCreated", taskIdRule, creatorRule, executorRule)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskEscrowV2TaskCreatedIterator{contract: _TaskEscrowV2.contract, event: "TaskCreated", logs: logs, sub: sub}, nil
+}
+
+// WatchTaskCreated is a free log subscription operation binding the contract event 0xf3efa663e8763e3719e2bdc58b7fdc03d43b6fcec97b7bcf371e6a4ea8704488.
+//
+// Solidity: event TaskCreated(uint256 indexed taskId, address indexed creator, address indexed executor, uint256 amount)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) WatchTaskCreated(opts *bind.WatchOpts, sink chan<- *TaskEscrowV2TaskCreated, taskId []*big.Int, creator []common.Address, executor []common.Address) (event.Subscription, error) {
+
+	var taskIdRule []interface{}
+	for _, taskIdItem := range taskId {
+		taskIdRule = append(taskIdRule, taskIdItem)
+	}
+	var creatorRule []interface{}
+	for _, creatorItem := range creator {
+		creatorRule = append(creatorRule, creatorItem)
+	}
+	var executorRule []interface{}
+	for _, executorItem := range executor {
+		executorRule = append(executorRule, executorItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.WatchLogs(opts, "TaskCreated", taskIdRule, creatorRule, executorRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(TaskEscrowV2TaskCreated)
+				if err := _TaskEscrowV2.contract.UnpackLog(event, "TaskCreated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseTaskCreated is a log parse operation binding the contract event 0xf3efa663e8763e3719e2bdc58b7fdc03d43b6fcec97b7bcf371e6a4ea8704488.
+//
+// Solidity: event TaskCreated(uint256 indexed taskId, address indexed creator, address indexed executor, uint256 amount)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) ParseTaskCreated(log types.Log) (*TaskEscrowV2TaskCreated, error) {
+	event := new(TaskEscrowV2TaskCreated)
+	if err := _TaskEscrowV2.contract.UnpackLog(event, "TaskCreated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// TaskEscrowV2TaskRefundedIterator is returned from FilterTaskRefunded and is used to iterate over the raw logs and unpacked data for TaskRefunded events raised by the TaskEscrowV2 contract.
+type TaskEscrowV2TaskRefundedIterator struct {
+	Event *TaskEscrowV2TaskRefunded // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *TaskEscrowV2TaskRefundedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(TaskEscrowV2TaskRefunded)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(TaskEscrowV2TaskRefunded)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *TaskEscrowV2TaskRefundedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *TaskEscrowV2TaskRefundedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// TaskEscrowV2TaskRefunded represents a TaskRefunded event raised by the TaskEscrowV2 contract.
+type TaskEscrowV2TaskRefunded struct {
+	TaskId   *big.Int
+	Creator  common.Address
+	Amount   *big.Int
+	NewTotal *big.Int
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterTaskRefunded is a free log retrieval operation binding the contract event 0xad9c966e2de1d6cfbe30a248a49e8cfaab780b42372e3426e0f949e1254d989d.
+//
+// Solidity: event TaskRefunded(uint256 indexed taskId, address indexed creator, uint256 amount, uint256 newTotal)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) FilterTaskRefunded(opts *bind.FilterOpts, taskId []*big.Int, creator []common.Address) (*TaskEscrowV2TaskRefundedIterator, error) {
+
+	var taskIdRule []interface{}
+	for _, taskIdItem := range taskId {
+		taskIdRule = append(taskIdRule, taskIdItem)
+	}
+	var creatorRule []interface{}
+	for _, creatorItem := range creator {
+		creatorRule = append(creatorRule, creatorItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.FilterLogs(opts, "TaskRefunded", taskIdRule, creatorRule)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskEscrowV2TaskRefundedIterator{contract: _TaskEscrowV2.contract, event: "TaskRefunded", logs: logs, sub: sub}, nil
+}
+
+// WatchTaskRefunded is a free log subscription operation binding the contract event 0xad9c966e2de1d6cfbe30a248a49e8cfaab780b42372e3426e0f949e1254d989d.
+//
+// Solidity: event TaskRefunded(uint256 indexed taskId, address indexed creator, uint256 amount, uint256 newTotal)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) WatchTaskRefunded(opts *bind.WatchOpts, sink chan<- *TaskEscrowV2TaskRefunded, taskId []*big.Int, creator []common.Address) (event.Subscription, error) {
+
+	var taskIdRule []interface{}
+	for _, taskIdItem := range taskId {
+		taskIdRule = append(taskIdRule, taskIdItem)
+	}
+	var creatorRule []interface{}
+	for _, creatorItem := range creator {
+		creatorRule = append(creatorRule, creatorItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.WatchLogs(opts, "TaskRefunded", taskIdRule, creatorRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(TaskEscrowV2TaskRefunded)
+				if err := _TaskEscrowV2.contract.UnpackLog(event, "TaskRefunded", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseTaskRefunded is a log parse operation binding the contract event 0xad9c966e2de1d6cfbe30a248a49e8cfaab780b42372e3426e0f949e1254d989d.
+//
+// Solidity: event TaskRefunded(uint256 indexed taskId, address indexed creator, uint256 amount, uint256 newTotal)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) ParseTaskRefunded(log types.Log) (*TaskEscrowV2TaskRefunded, error) {
+	event := new(TaskEscrowV2TaskRefunded)
+	if err := _TaskEscrowV2.contract.UnpackLog(event, "TaskRefunded", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// TaskEscrowV2TaskToppedUpIterator is returned from FilterTaskToppedUp and is used to iterate over the raw logs and unpacked data for TaskToppedUp events raised by the TaskEscrowV2 contract.
+type TaskEscrowV2TaskToppedUpIterator struct {
+	Event *TaskEscrowV2TaskToppedUp // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *TaskEscrowV2TaskToppedUpIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(TaskEscrowV2TaskToppedUp)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(TaskEscrowV2TaskToppedUp)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *TaskEscrowV2TaskToppedUpIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *TaskEscrowV2TaskToppedUpIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// TaskEscrowV2TaskToppedUp represents a TaskToppedUp event raised by the TaskEscrowV2 contract.
+type TaskEscrowV2TaskToppedUp struct {
+	TaskId   *big.Int
+	Creator  common.Address
+	Amount   *big.Int
+	NewTotal *big.Int
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterTaskToppedUp is a free log retrieval operation binding the contract event 0x15a39c184375fd2b5711313608f22c3f610b4cd2b5f706af9c77d8272427e20d.
+//
+// Solidity: event TaskToppedUp(uint256 indexed taskId, address indexed creator, uint256 amount, uint256 newTotal)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) FilterTaskToppedUp(opts *bind.FilterOpts, taskId []*big.Int, creator []common.Address) (*TaskEscrowV2TaskToppedUpIterator, error) {
+
+	var taskIdRule []interface{}
+	for _, taskIdItem := range taskId {
+		taskIdRule = append(taskIdRule, taskIdItem)
+	}
+	var creatorRule []interface{}
+	for _, creatorItem := range creator {
+		creatorRule = append(creatorRule, creatorItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.FilterLogs(opts, "TaskToppedUp", taskIdRule, creatorRule)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskEscrowV2TaskToppedUpIterator{contract: _TaskEscrowV2.contract, event: "TaskToppedUp", logs: logs, sub: sub}, nil
+}
+
+// WatchTaskToppedUp is a free log subscription operation binding the contract event 0x15a39c184375fd2b5711313608f22c3f610b4cd2b5f706af9c77d8272427e20d.
+//
+// Solidity: event TaskToppedUp(uint256 indexed taskId, address indexed creator, uint256 amount, uint256 newTotal)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) WatchTaskToppedUp(opts *bind.WatchOpts, sink chan<- *TaskEscrowV2TaskToppedUp, taskId []*big.Int, creator []common.Address) (event.Subscription, error) {
+
+	var taskIdRule []interface{}
+	for _, taskIdItem := range taskId {
+		taskIdRule = append(taskIdRule, taskIdItem)
+	}
+	var creatorRule []interface{}
+	for _, creatorItem := range creator {
+		creatorRule = append(creatorRule, creatorItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.WatchLogs(opts, "TaskToppedUp", taskIdRule, creatorRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(TaskEscrowV2TaskToppedUp)
+				if err := _TaskEscrowV2.contract.UnpackLog(event, "TaskToppedUp", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseTaskToppedUp is a log parse operation binding the contract event 0x15a39c184375fd2b5711313608f22c3f610b4cd2b5f706af9c77d8272427e20d.
+//
+// Solidity: event TaskToppedUp(uint256 indexed taskId, address indexed creator, uint256 amount, uint256 newTotal)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) ParseTaskToppedUp(log types.Log) (*TaskEscrowV2TaskToppedUp, error) {
+	event := new(TaskEscrowV2TaskToppedUp)
+	if err := _TaskEscrowV2.contract.UnpackLog(event, "TaskToppedUp", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// TaskEscrowV2TeamMemberPaidIterator is returned from FilterTeamMemberPaid and is used to iterate over the raw logs and unpacked data for TeamMemberPaid events raised by the TaskEscrowV2 contract.
+type TaskEscrowV2TeamMemberPaidIterator struct {
+	Event *TaskEscrowV2TeamMemberPaid // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *TaskEscrowV2TeamMemberPaidIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(TaskEscrowV2TeamMemberPaid)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(TaskEscrowV2TeamMemberPaid)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *TaskEscrowV2TeamMemberPaidIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *TaskEscrowV2TeamMemberPaidIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// TaskEscrowV2TeamMemberPaid represents a TeamMemberPaid event raised by the TaskEscrowV2 contract.
+type TaskEscrowV2TeamMemberPaid struct {
+	TaskId *big.Int
+	Member common.Address
+	Amount *big.Int
+	Raw    types.Log // Blockchain specific contextual infos
+}
+
+// FilterTeamMemberPaid is a free log retrieval operation binding the contract event 0x09b041c44e7611b83b651f8f2e0e765871484ec7537f3f8e476acf61752ecb2f.
+//
+// Solidity: event TeamMemberPaid(uint256 indexed taskId, address indexed member, uint256 amount)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) FilterTeamMemberPaid(opts *bind.FilterOpts, taskId []*big.Int, member []common.Address) (*TaskEscrowV2TeamMemberPaidIterator, error) {
+
+	var taskIdRule []interface{}
+	for _, taskIdItem := range taskId {
+		taskIdRule = append(taskIdRule, taskIdItem)
+	}
+	var memberRule []interface{}
+	for _, memberItem := range member {
+		memberRule = append(memberRule, memberItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.FilterLogs(opts, "TeamMemberPaid", taskIdRule, memberRule)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskEscrowV2TeamMemberPaidIterator{contract: _TaskEscrowV2.contract, event: "TeamMemberPaid", logs: logs, sub: sub}, nil
+}
+
+// WatchTeamMemberPaid is a free log subscription operation binding the contract event 0x09b041c44e7611b83b651f8f2e0e765871484ec7537f3f8e476acf61752ecb2f.
+//
+// Solidity: event TeamMemberPaid(uint256 indexed taskId, address indexed member, uint256 amount)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) WatchTeamMemberPaid(opts *bind.WatchOpts, sink chan<- *TaskEscrowV2TeamMemberPaid, taskId []*big.Int, member []common.Address) (event.Subscription, error) {
+
+	var taskIdRule []interface{}
+	for _, taskIdItem := range taskId {
+		taskIdRule = append(taskIdRule, taskIdItem)
+	}
+	var memberRule []interface{}
+	for _, memberItem := range member {
+		memberRule = append(memberRule, memberItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.WatchLogs(opts, "TeamMemberPaid", taskIdRule, memberRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(TaskEscrowV2TeamMemberPaid)
+				if err := _TaskEscrowV2.contract.UnpackLog(event, "TeamMemberPaid", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseTeamMemberPaid is a log parse operation binding the contract event 0x09b041c44e7611b83b651f8f2e0e765871484ec7537f3f8e476acf61752ecb2f.
+//
+// Solidity: event TeamMemberPaid(uint256 indexed taskId, address indexed member, uint256 amount)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) ParseTeamMemberPaid(log types.Log) (*TaskEscrowV2TeamMemberPaid, error) {
+	event := new(TaskEscrowV2TeamMemberPaid)
+	if err := _TaskEscrowV2.contract.UnpackLog(event, "TeamMemberPaid", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// TaskEscrowV2TeamSetIterator is returned from FilterTeamSet and is used to iterate over the raw logs and unpacked data for TeamSet events raised by the TaskEscrowV2 contract.
+type TaskEscrowV2TeamSetIterator struct {
+	Event *TaskEscrowV2TeamSet // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *TaskEscrowV2TeamSetIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(TaskEscrowV2TeamSet)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(TaskEscrowV2TeamSet)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *TaskEscrowV2TeamSetIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *TaskEscrowV2TeamSetIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// TaskEscrowV2TeamSet represents a TeamSet event raised by the TaskEscrowV2 contract.
+type TaskEscrowV2TeamSet struct {
+	TaskId   *big.Int
+	Members  []common.Address
+	ShareBps []uint16
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterTeamSet is a free log retrieval operation binding the contract event 0xd56029cda02c923b121865de66bb37e54ba4ad15fc00f68141991843c15a6d66.
+//
+// Solidity: event TeamSet(uint256 indexed taskId, address[] members, uint16[] shareBps)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) FilterTeamSet(opts *bind.FilterOpts, taskId []*big.Int) (*TaskEscrowV2TeamSetIterator, error) {
+
+	var taskIdRule []interface{}
+	for _, taskIdItem := range taskId {
+		taskIdRule = append(taskIdRule, taskIdItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.FilterLogs(opts, "TeamSet", taskIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskEscrowV2TeamSetIterator{contract: _TaskEscrowV2.contract, event: "TeamSet", logs: logs, sub: sub}, nil
+}
+
+// WatchTeamSet is a free log subscription operation binding the contract event 0xd56029cda02c923b121865de66bb37e54ba4ad15fc00f68141991843c15a6d66.
+//
+// Solidity: event TeamSet(uint256 indexed taskId, address[] members, uint16[] shareBps)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) WatchTeamSet(opts *bind.WatchOpts, sink chan<- *TaskEscrowV2TeamSet, taskId []*big.Int) (event.Subscription, error) {
+
+	var taskIdRule []interface{}
+	for _, taskIdItem := range taskId {
+		taskIdRule = append(taskIdRule, taskIdItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.WatchLogs(opts, "TeamSet", taskIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(TaskEscrowV2TeamSet)
+				if err := _TaskEscrowV2.contract.UnpackLog(event, "TeamSet", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseTeamSet is a log parse operation binding the contract event 0xd56029cda02c923b121865de66bb37e54ba4ad15fc00f68141991843c15a6d66.
+//
+// Solidity: event TeamSet(uint256 indexed taskId, address[] members, uint16[] shareBps)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) ParseTeamSet(log types.Log) (*TaskEscrowV2TeamSet, error) {
+	event := new(TaskEscrowV2TeamSet)
+	if err := _TaskEscrowV2.contract.UnpackLog(event, "TeamSet", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// TaskEscrowV2WithdrawalCancelledIterator is returned from FilterWithdrawalCancelled and is used to iterate over the raw logs and unpacked data for WithdrawalCancelled events raised by the TaskEscrowV2 contract.
+type TaskEscrowV2WithdrawalCancelledIterator struct {
+	Event *TaskEscrowV2WithdrawalCancelled // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *TaskEscrowV2WithdrawalCancelledIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(TaskEscrowV2WithdrawalCancelled)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(TaskEscrowV2WithdrawalCancelled)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *TaskEscrowV2WithdrawalCancelledIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *TaskEscrowV2WithdrawalCancelledIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// TaskEscrowV2WithdrawalCancelled represents a WithdrawalCancelled event raised by the TaskEscrowV2 contract.
+type TaskEscrowV2WithdrawalCancelled struct {
+	WithdrawalId *big.Int
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterWithdrawalCancelled is a free log retrieval operation binding the contract event 0xe8de1e631ea541ac8e6cb398aafb71b991eb58d489298f7bc93ba7e17fa2042b.
+//
+// Solidity: event WithdrawalCancelled(uint256 indexed withdrawalId)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) FilterWithdrawalCancelled(opts *bind.FilterOpts, withdrawalId []*big.Int) (*TaskEscrowV2WithdrawalCancelledIterator, error) {
+
+	var withdrawalIdRule []interface{}
+	for _, withdrawalIdItem := range withdrawalId {
+		withdrawalIdRule = append(withdrawalIdRule, withdrawalIdItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.FilterLogs(opts, "WithdrawalCancelled", withdrawalIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskEscrowV2WithdrawalCancelledIterator{contract: _TaskEscrowV2.contract, event: "WithdrawalCancelled", logs: logs, sub: sub}, nil
+}
+
+// WatchWithdrawalCancelled is a free log subscription operation binding the contract event 0xe8de1e631ea541ac8e6cb398aafb71b991eb58d489298f7bc93ba7e17fa2042b.
+//
+// Solidity: event WithdrawalCancelled(uint256 indexed withdrawalId)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) WatchWithdrawalCancelled(opts *bind.WatchOpts, sink chan<- *TaskEscrowV2WithdrawalCancelled, withdrawalId []*big.Int) (event.Subscription, error) {
+
+	var withdrawalIdRule []interface{}
+	for _, withdrawalIdItem := range withdrawalId {
+		withdrawalIdRule = append(withdrawalIdRule, withdrawalIdItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.WatchLogs(opts, "WithdrawalCancelled", withdrawalIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(TaskEscrowV2WithdrawalCancelled)
+				if err := _TaskEscrowV2.contract.UnpackLog(event, "WithdrawalCancelled", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseWithdrawalCancelled is a log parse operation binding the contract event 0xe8de1e631ea541ac8e6cb398aafb71b991eb58d489298f7bc93ba7e17fa2042b.
+//
+// Solidity: event WithdrawalCancelled(uint256 indexed withdrawalId)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) ParseWithdrawalCancelled(log types.Log) (*TaskEscrowV2WithdrawalCancelled, error) {
+	event := new(TaskEscrowV2WithdrawalCancelled)
+	if err := _TaskEscrowV2.contract.UnpackLog(event, "WithdrawalCancelled", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// TaskEscrowV2WithdrawalExecutedIterator is returned from FilterWithdrawalExecuted and is used to iterate over the raw logs and unpacked data for WithdrawalExecuted events raised by the TaskEscrowV2 contract.
+type TaskEscrowV2WithdrawalExecutedIterator struct {
+	Event *TaskEscrowV2WithdrawalExecuted // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *TaskEscrowV2WithdrawalExecutedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(TaskEscrowV2WithdrawalExecuted)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(TaskEscrowV2WithdrawalExecuted)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *TaskEscrowV2WithdrawalExecutedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *TaskEscrowV2WithdrawalExecutedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// TaskEscrowV2WithdrawalExecuted represents a WithdrawalExecuted event raised by the TaskEscrowV2 contract.
+type TaskEscrowV2WithdrawalExecuted struct {
+	WithdrawalId *big.Int
+	To           common.Address
+	Amount       *big.Int
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterWithdrawalExecuted is a free log retrieval operation binding the contract event 0xd6cddb3d69146e96ebc2c87b1b3dd0b20ee2d3b0eadf134e011afb434a3e56e6.
+//
+// Solidity: event WithdrawalExecuted(uint256 indexed withdrawalId, address indexed to, uint256 amount)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) FilterWithdrawalExecuted(opts *bind.FilterOpts, withdrawalId []*big.Int, to []common.Address) (*TaskEscrowV2WithdrawalExecutedIterator, error) {
+
+	var withdrawalIdRule []interface{}
+	for _, withdrawalIdItem := range withdrawalId {
+		withdrawalIdRule = append(withdrawalIdRule, withdrawalIdItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.FilterLogs(opts, "WithdrawalExecuted", withdrawalIdRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskEscrowV2WithdrawalExecutedIterator{contract: _TaskEscrowV2.contract, event: "WithdrawalExecuted", logs: logs, sub: sub}, nil
+}
+
+// WatchWithdrawalExecuted is a free log subscription operation binding the contract event 0xd6cddb3d69146e96ebc2c87b1b3dd0b20ee2d3b0eadf134e011afb434a3e56e6.
+//
+// Solidity: event WithdrawalExecuted(uint256 indexed withdrawalId, address indexed to, uint256 amount)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) WatchWithdrawalExecuted(opts *bind.WatchOpts, sink chan<- *TaskEscrowV2WithdrawalExecuted, withdrawalId []*big.Int, to []common.Address) (event.Subscription, error) {
+
+	var withdrawalIdRule []interface{}
+	for _, withdrawalIdItem := range withdrawalId {
+		withdrawalIdRule = append(withdrawalIdRule, withdrawalIdItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.WatchLogs(opts, "WithdrawalExecuted", withdrawalIdRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(TaskEscrowV2WithdrawalExecuted)
+				if err := _TaskEscrowV2.contract.UnpackLog(event, "WithdrawalExecuted", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseWithdrawalExecuted is a log parse operation binding the contract event 0xd6cddb3d69146e96ebc2c87b1b3dd0b20ee2d3b0eadf134e011afb434a3e56e6.
+//
+// Solidity: event WithdrawalExecuted(uint256 indexed withdrawalId, address indexed to, uint256 amount)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) ParseWithdrawalExecuted(log types.Log) (*TaskEscrowV2WithdrawalExecuted, error) {
+	event := new(TaskEscrowV2WithdrawalExecuted)
+	if err := _TaskEscrowV2.contract.UnpackLog(event, "WithdrawalExecuted", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// TaskEscrowV2WithdrawalProposedIterator is returned from FilterWithdrawalProposed and is used to iterate over the raw logs and unpacked data for WithdrawalProposed events raised by the TaskEscrowV2 contract.
+type TaskEscrowV2WithdrawalProposedIterator struct {
+	Event *TaskEscrowV2WithdrawalProposed // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *TaskEscrowV2WithdrawalProposedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(TaskEscrowV2WithdrawalProposed)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(TaskEscrowV2WithdrawalProposed)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *TaskEscrowV2WithdrawalProposedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *TaskEscrowV2WithdrawalProposedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// TaskEscrowV2WithdrawalProposed represents a WithdrawalProposed event raised by the TaskEscrowV2 contract.
+type TaskEscrowV2WithdrawalProposed struct {
+	WithdrawalId *big.Int
+	To           common.Address
+	Amount       *big.Int
+	ExecutableAt *big.Int
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterWithdrawalProposed is a free log retrieval operation binding the contract event 0x3d99f2b957efa07c33416289f6d194ff0bd0edc548d013e30cf5968a2d1ea6e5.
+//
+// Solidity: event WithdrawalProposed(uint256 indexed withdrawalId, address indexed to, uint256 amount, uint256 executableAt)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) FilterWithdrawalProposed(opts *bind.FilterOpts, withdrawalId []*big.Int, to []common.Address) (*TaskEscrowV2WithdrawalProposedIterator, error) {
+
+	var withdrawalIdRule []interface{}
+	for _, withdrawalIdItem := range withdrawalId {
+		withdrawalIdRule = append(withdrawalIdRule, withdrawalIdItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.FilterLogs(opts, "WithdrawalProposed", withdrawalIdRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskEscrowV2WithdrawalProposedIterator{contract: _TaskEscrowV2.contract, event: "WithdrawalProposed", logs: logs, sub: sub}, nil
+}
+
+// WatchWithdrawalProposed is a free log subscription operation binding the contract event 0x3d99f2b957efa07c33416289f6d194ff0bd0edc548d013e30cf5968a2d1ea6e5.
+//
+// Solidity: event WithdrawalProposed(uint256 indexed withdrawalId, address indexed to, uint256 amount, uint256 executableAt)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) WatchWithdrawalProposed(opts *bind.WatchOpts, sink chan<- *TaskEscrowV2WithdrawalProposed, withdrawalId []*big.Int, to []common.Address) (event.Subscription, error) {
+
+	var withdrawalIdRule []interface{}
+	for _, withdrawalIdItem := range withdrawalId {
+		withdrawalIdRule = append(withdrawalIdRule, withdrawalIdItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _TaskEscrowV2.contract.WatchLogs(opts, "WithdrawalProposed", withdrawalIdRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(TaskEscrowV2WithdrawalProposed)
+				if err := _TaskEscrowV2.contract.UnpackLog(event, "WithdrawalProposed", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseWithdrawalProposed is a log parse operation binding the contract event 0x3d99f2b957efa07c33416289f6d194ff0bd0edc548d013e30cf5968a2d1ea6e5.
+//
+// Solidity: event WithdrawalProposed(uint256 indexed withdrawalId, address indexed to, uint256 amount, uint256 executableAt)
+func (_TaskEscrowV2 *TaskEscrowV2Filterer) ParseWithdrawalProposed(log types.Log) (*TaskEscrowV2WithdrawalProposed, error) {
+	event := new(TaskEscrowV2WithdrawalProposed)
+	if err := _TaskEscrowV2.contract.UnpackLog(event, "WithdrawalProposed", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
@@ -7,10 +7,12 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
-// CreateTask creates a new task and locks XZT in escrow
-func (c *BlockchainClient) CreateTask(creatorAddress string, amount *big.Int) (uint64, string, error) {
+// CreateTask creates a new task and locks XZT in escrow. A v2 escrow stores
+// milestoneBps as the task's payment schedule; v1 ignores it.
+func (c *BlockchainClient) CreateTask(creatorAddress string, amount *big.Int, milestoneBps []uint16) (uint64, string, error) {
 	creator := common.HexToAddress(creatorAddress)
 	executor := common.HexToAddress("0x0000000000000000000000000000000000000000") // No executor yet
 
@@ -18,13 +20,19 @@ func (c *BlockchainClient) CreateTask(creatorAddress string, amount *big.Int) (u
 	// Note: In production, this should be done once per user during registration
 
 	// Create task
-	tx, err := c.Escrow.CreateTask(c.AdminAuth, creator, executor, amount)
+	var tx *types.Transaction
+	var err error
+	if c.EscrowV2 != nil {
+		tx, err = c.EscrowV2.CreateTask(c.AdminAuth, creator, executor, amount, milestoneBps)
+	} else {
+		tx, err = c.Escrow.CreateTask(c.AdminAuth, creator, executor, amount)
+	}
 	if err != nil {
 		return 0, "", fmt.Errorf("failed to create task: %w", err)
 	}
 
 	// Wait for transaction
-	receipt, err := bind.WaitMined(context.Background(), c.Client, tx)
+	receipt, err := c.waitMined(context.Background(), tx)
 	if err != nil {
 		return 0, "", fmt.Errorf("failed to wait for transaction: %w", err)
 	}
@@ -33,19 +41,132 @@ func (c *BlockchainClient) CreateTask(creatorAddress string, amount *big.Int) (u
 		return 0, "", fmt.Errorf("transaction failed")
 	}
 
-	// Parse TaskCreated event to get task ID
-	// For simplicity, we'll return the next task ID - 1
-	// In production, parse the event logs
-	nextTaskID, err := c.Escrow.NextTaskId(&bind.CallOpts{})
+	taskID, err := c.taskIDFromReceipt(receipt)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return taskID, tx.Hash().Hex(), nil
+}
+
+// CreateTaskWithPermit creates a task and locks XZT in one transaction,
+// using the creator's EIP-2612 permit instead of a prior approve
+func (c *BlockchainClient) CreateTaskWithPermit(creatorAddress string, amount *big.Int, milestoneBps []uint16, permit *PermitSignature) (uint64, string, error) {
+	creator := common.HexToAddress(creatorAddress)
+	executor := common.HexToAddress("0x0000000000000000000000000000000000000000") // No executor yet
+
+	var tx *types.Transaction
+	var err error
+	if c.EscrowV2 != nil {
+		tx, err = c.EscrowV2.CreateTaskWithPermit(c.AdminAuth, creator, executor, amount, milestoneBps, permit.Deadline, permit.V, permit.R, permit.S)
+	} else {
+		tx, err = c.Escrow.CreateTaskWithPermit(c.AdminAuth, creator, executor, amount, permit.Deadline, permit.V, permit.R, permit.S)
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to create task: %w", err)
+	}
+
+	receipt, err := c.waitMined(context.Background(), tx)
 	if err != nil {
-		return 0, "", fmt.Errorf("failed to get next task ID: %w", err)
+		return 0, "", fmt.Errorf("failed to wait for transaction: %w", err)
 	}
 
-	taskID := nextTaskID.Uint64() - 1
+	if receipt.Status == 0 {
+		return 0, "", fmt.Errorf("transaction failed")
+	}
+
+	taskID, err := c.taskIDFromReceipt(receipt)
+	if err != nil {
+		return 0, "", err
+	}
 
 	return taskID, tx.Hash().Hex(), nil
 }
 
+// MaxBatchSize matches TaskEscrowV2.MAX_BATCH_SIZE
+const MaxBatchSize = 25
+
+// CreateTasks creates several tasks for one creator in one transaction,
+// locking their total with a single transfer (v2 escrow). permit may be nil
+// when the allowance is already in place; otherwise it covers the total.
+// Task IDs are returned in the order of amounts.
+func (c *BlockchainClient) CreateTasks(creatorAddress string, amounts []*big.Int, milestoneBps [][]uint16, permit *PermitSignature) ([]uint64, string, error) {
+	if c.EscrowV2 == nil {
+		return nil, "", ErrEscrowV2Required
+	}
+	creator := common.HexToAddress(creatorAddress)
+
+	// A batch outgrows AdminAuth's fixed gas limit after a few tasks
+	tx, err := c.withEstimatedGas(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		if permit != nil {
+			return c.EscrowV2.CreateTasksWithPermit(opts, creator, amounts, milestoneBps, permit.Deadline, permit.V, permit.R, permit.S)
+		}
+		return c.EscrowV2.CreateTasks(opts, creator, amounts, milestoneBps)
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create tasks: %w", err)
+	}
+
+	receipt, err := c.waitMined(context.Background(), tx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to wait for transaction: %w", err)
+	}
+
+	if receipt.Status == 0 {
+		return nil, "", fmt.Errorf("transaction failed")
+	}
+
+	var taskIDs []uint64
+	for _, log := range receipt.Logs {
+		if log.Address != c.EscrowAddress {
+			continue
+		}
+		if event, err := c.Escrow.ParseTaskCreated(*log); err == nil {
+			taskIDs = append(taskIDs, event.TaskId.Uint64())
+		}
+	}
+	if len(taskIDs) != len(amounts) {
+		return nil, "", fmt.Errorf("expected %d TaskCreated events in transaction %s, found %d", len(amounts), tx.Hash().Hex(), len(taskIDs))
+	}
+
+	return taskIDs, tx.Hash().Hex(), nil
+}
+
+// gasMarginPercent is added to a gas estimate, which the state can outgrow
+// between estimating and mining
+const gasMarginPercent = 20
+
+// withEstimatedGas sends an admin transaction with its estimated gas plus
+// gasMarginPercent instead of AdminAuth's fixed limit. The estimate fails
+// like the transaction would, before anything is sent.
+func (c *BlockchainClient) withEstimatedGas(send func(opts *bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	opts := *c.AdminAuth
+	opts.GasLimit = 0 // Estimate
+	opts.NoSend = true
+	estimate, err := send(&opts)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.GasLimit = estimate.Gas() + estimate.Gas()*gasMarginPercent/100
+	opts.NoSend = false
+	return send(&opts)
+}
+
+// taskIDFromReceipt reads the task ID from the TaskCreated event
+func (c *BlockchainClient) taskIDFromReceipt(receipt *types.Receipt) (uint64, error) {
+	for _, log := range receipt.Logs {
+		if log.Address != c.EscrowAddress {
+			continue
+		}
+		event, err := c.Escrow.ParseTaskCreated(*log)
+		if err == nil {
+			return event.TaskId.Uint64(), nil
+		}
+	}
+	return 0, fmt.Errorf("TaskCreated event not found in transaction %s", receipt.TxHash.Hex())
+}
+
 // SetExecutor sets the executor for a task
 func (c *BlockchainClient) SetExecutor(taskID uint64, executorAddress string) (string, error) {
 	executor := common.HexToAddress(executorAddress)
@@ -56,7 +177,31 @@ func (c *BlockchainClient) SetExecutor(taskID uint64, executorAddress string) (s
 		return "", fmt.Errorf("failed to set executor: %w", err)
 	}
 
-	receipt, err := bind.WaitMined(context.Background(), c.Client, tx)
+	receipt, err := c.waitMined(context.Background(), tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for transaction: %w", err)
+	}
+
+	if receipt.Status == 0 {
+		return "", fmt.Errorf("transaction failed")
+	}
+
+	return tx.Hash().Hex(), nil
+}
+
+// RefundPartial returns part of a task's locked amount to its creator before an executor is set (v2 escrow)
+func (c *BlockchainClient) RefundPartial(taskID uint64, amount *big.Int) (string, error) {
+	if c.EscrowV2 == nil {
+		return "", ErrEscrowV2Required
+	}
+	taskIDBig := big.NewInt(int64(taskID))
+
+	tx, err := c.EscrowV2.RefundPartial(c.AdminAuth, taskIDBig, amount)
+	if err != nil {
+		return "", fmt.Errorf("failed to refund task: %w", err)
+	}
+
+	receipt, err := c.waitMined(context.Background(), tx)
 	if err != nil {
 		return "", fmt.Errorf("failed to wait for transaction: %w", err)
 	}
@@ -68,8 +213,37 @@ func (c *BlockchainClient) SetExecutor(taskID uint64, executorAddress string) (s
 	return tx.Hash().Hex(), nil
 }
 
-// PayMilestone pays a milestone to the executor
+// TopUpWithPermit locks amount more XZT from the creator for a task before an
+// executor is set, authorized by the creator's permit for amount (v2 escrow).
+// Without a permit use TopUpSigned.
+func (c *BlockchainClient) TopUpWithPermit(taskID uint64, amount *big.Int, permit *PermitSignature) (string, error) {
+	if c.EscrowV2 == nil {
+		return "", ErrEscrowV2Required
+	}
+	taskIDBig := big.NewInt(int64(taskID))
+
+	tx, err := c.EscrowV2.TopUpWithPermit(c.AdminAuth, taskIDBig, amount, permit.Deadline, permit.V, permit.R, permit.S)
+	if err != nil {
+		return "", fmt.Errorf("failed to top up task: %w", err)
+	}
+
+	receipt, err := c.waitMined(context.Background(), tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for transaction: %w", err)
+	}
+
+	if receipt.Status == 0 {
+		return "", fmt.Errorf("transaction failed")
+	}
+
+	return tx.Hash().Hex(), nil
+}
+
+// PayMilestone pays a milestone to the executor (v1 escrow; v2 uses ReleaseMilestoneSigned)
 func (c *BlockchainClient) PayMilestone(taskID uint64, amount *big.Int) (string, error) {
+	if c.EscrowV2 != nil {
+		return "", ErrSignatureRequired
+	}
 	taskIDBig := big.NewInt(int64(taskID))
 
 	tx, err := c.Escrow.PayMilestone(c.AdminAuth, taskIDBig, amount)
@@ -77,7 +251,7 @@ func (c *BlockchainClient) PayMilestone(taskID uint64, amount *big.Int) (string,
 		return "", fmt.Errorf("failed to pay milestone: %w", err)
 	}
 
-	receipt, err := bind.WaitMined(context.Background(), c.Client, tx)
+	receipt, err := c.waitMined(context.Background(), tx)
 	if err != nil {
 		return "", fmt.Errorf("failed to wait for transaction: %w", err)
 	}
@@ -89,8 +263,11 @@ func (c *BlockchainClient) PayMilestone(taskID uint64, amount *big.Int) (string,
 	return tx.Hash().Hex(), nil
 }
 
-// CancelTask cancels a task with refund distribution
+// CancelTask cancels a task with refund distribution (v1 escrow; v2 uses CancelTaskSigned)
 func (c *BlockchainClient) CancelTask(taskID uint64, executorAmount *big.Int) (string, error) {
+	if c.EscrowV2 != nil {
+		return "", ErrSignatureRequired
+	}
 	taskIDBig := big.NewInt(int64(taskID))
 
 	tx, err := c.Escrow.CancelTask(c.AdminAuth, taskIDBig, executorAmount)
@@ -98,7 +275,7 @@ func (c *BlockchainClient) CancelTask(taskID uint64, executorAmount *big.Int) (s
 		return "", fmt.Errorf("failed to cancel task: %w", err)
 	}
 
-	receipt, err := bind.WaitMined(context.Background(), c.Client, tx)
+	receipt, err := c.waitMined(context.Background(), tx)
 	if err != nil {
 		return "", fmt.Errorf("failed to wait for transaction: %w", err)
 	}
@@ -0,0 +1,155 @@
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCreateTaskWithPermit(t *testing.T) {
+	for _, escrow := range []string{"TaskEscrow", "TaskEscrowV2"} {
+		t.Run(escrow, func(t *testing.T) {
+			chain := newTestChain(t, escrow)
+			creator := address(chain.creator)
+			chain.fund(t, creator, xzt(100))
+
+			deadline := big.NewInt(chain.now(t) + 3600)
+			permit, err := chain.SignPermit(chain.creator, xzt(60), deadline)
+			if err != nil {
+				t.Fatal(err)
+			}
+			taskID, _, err := chain.CreateTaskWithPermit(creator.Hex(), xzt(60), []uint16{4000, 6000}, permit)
+			if err != nil {
+				t.Fatalf("CreateTaskWithPermit: %v", err)
+			}
+
+			taskCreator, _, total, paid, cancelled, err := chain.GetTask(taskID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if taskCreator != creator.Hex() || total.Cmp(xzt(60)) != 0 || paid.Sign() != 0 || cancelled {
+				t.Fatalf("task = (%s, %s, %s, %v), want (%s, %s, 0, false)", taskCreator, total, paid, cancelled, creator.Hex(), xzt(60))
+			}
+			assertBalance(t, chain, creator, xzt(40))
+			assertBalance(t, chain, chain.EscrowAddress, xzt(60))
+
+			// The permit is spent: no allowance is left over
+			allowance, err := chain.Token.Allowance(&bind.CallOpts{}, creator, chain.EscrowAddress)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if allowance.Sign() != 0 {
+				t.Fatalf("allowance %s left after the task, want 0", allowance)
+			}
+
+			if chain.IsEscrowV2() {
+				bps, released, err := chain.Milestones(context.Background(), taskID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(bps) != 2 || bps[0] != 4000 || bps[1] != 6000 || released[0] || released[1] {
+					t.Fatalf("milestones = %v released %v, want [4000 6000] unreleased", bps, released)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateTaskWithFrontRunPermit(t *testing.T) {
+	chain := newTestChain(t, "TaskEscrowV2")
+	creator := address(chain.creator)
+	chain.fund(t, creator, xzt(100))
+
+	deadline := big.NewInt(chain.now(t) + 3600)
+	permit, err := chain.SignPermit(chain.creator, xzt(60), deadline)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Someone relays the permit first; the allowance it set still funds the task
+	if _, err := chain.SubmitPermit(creator, xzt(60), permit); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := chain.CreateTaskWithPermit(creator.Hex(), xzt(60), []uint16{10000}, permit); err != nil {
+		t.Fatalf("CreateTaskWithPermit after front-run: %v", err)
+	}
+	assertBalance(t, chain, chain.EscrowAddress, xzt(60))
+}
+
+func TestCreateTaskWithBadPermit(t *testing.T) {
+	chain := newTestChain(t, "TaskEscrowV2")
+	creator := address(chain.creator)
+	chain.fund(t, creator, xzt(100))
+
+	expired, err := chain.SignPermit(chain.creator, xzt(60), big.NewInt(chain.now(t)-60))
+	if err != nil {
+		t.Fatal(err)
+	}
+	short, err := chain.SignPermit(chain.creator, xzt(30), big.NewInt(chain.now(t)+3600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Signed by the executor for the creator's tokens
+	forged, err := chain.SignPermit(chain.executor, xzt(60), big.NewInt(chain.now(t)+3600))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, permit := range map[string]*PermitSignature{"expired": expired, "short": short, "forged": forged} {
+		if _, _, err := chain.CreateTaskWithPermit(creator.Hex(), xzt(60), []uint16{10000}, permit); err == nil {
+			t.Errorf("%s permit: task created", name)
+		}
+	}
+	assertBalance(t, chain, creator, xzt(100))
+}
+
+func TestCreateTasksAtMaxBatchSize(t *testing.T) {
+	chain := newTestChain(t, "TaskEscrowV2")
+	creator := address(chain.creator)
+	chain.fund(t, creator, xzt(10*MaxBatchSize))
+
+	amounts := make([]*big.Int, MaxBatchSize)
+	schedules := make([][]uint16, MaxBatchSize)
+	for i := range amounts {
+		amounts[i] = xzt(10)
+		schedules[i] = []uint16{3000, 5000, 2000}
+	}
+	permit, err := chain.SignPermit(chain.creator, xzt(10*MaxBatchSize), big.NewInt(chain.now(t)+3600))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	taskIDs, _, err := chain.CreateTasks(creator.Hex(), amounts, schedules, permit)
+	if err != nil {
+		t.Fatalf("CreateTasks: %v", err)
+	}
+	if len(taskIDs) != MaxBatchSize {
+		t.Fatalf("%d task IDs, want %d", len(taskIDs), MaxBatchSize)
+	}
+	for _, taskID := range taskIDs {
+		_, _, total, _, _, err := chain.GetTask(taskID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total.Cmp(xzt(10)) != 0 {
+			t.Fatalf("task %d total = %s, want %s", taskID, total, xzt(10))
+		}
+	}
+	assertBalance(t, chain, creator, big.NewInt(0))
+	assertBalance(t, chain, chain.EscrowAddress, xzt(10*MaxBatchSize))
+}
+
+func assertBalance(t *testing.T, chain *testChain, owner common.Address, want *big.Int) {
+	t.Helper()
+
+	balance, err := chain.GetBalance(owner.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Cmp(want) != 0 {
+		t.Fatalf("balance of %s = %s, want %s", owner.Hex(), balance, want)
+	}
+}
@@ -0,0 +1,490 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/x-zero/xz-wallet/pkg/blockchain/contracts"
+)
+
+// EIP-712 domain of TaskEscrowV2
+const (
+	EscrowDomainName    = "TaskEscrow"
+	EscrowDomainVersion = "2"
+)
+
+var (
+	eip712DomainTypeHash = crypto.Keccak256Hash([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+
+	// ErrEscrowV2Required is returned by signed actions on a v1 escrow
+	ErrEscrowV2Required = errors.New("escrow does not support signed task actions")
+	// ErrSignatureRequired is returned by unsigned actions on a v2 escrow
+	ErrSignatureRequired = errors.New("escrow v2 requires a signed authorization")
+)
+
+// Escrow actions a task participant signs for TaskEscrowV2
+const (
+	EscrowActionReleaseMilestone = "ReleaseMilestone"
+	EscrowActionCancelTask       = "CancelTask"
+	EscrowActionResignExecutor   = "ResignExecutor"
+	EscrowActionTopUp            = "TopUp"
+)
+
+// EscrowAuthorization is one signed task action. For ReleaseMilestone, Index
+// is signed and Amount is the slice it pays (informational); for CancelTask,
+// Amount is the executor's share and is signed. Executor and Team (see
+// TeamHash) are the task's payees when the action was built; the escrow only
+// accepts the signature while they are unchanged. ResignExecutor signs only
+// the executor, and Amount is zero; TopUp signs only Amount, the XZT added.
+type EscrowAuthorization struct {
+	Action   string
+	TaskID   *big.Int
+	Index    *big.Int
+	Amount   *big.Int
+	Executor common.Address
+	Team     common.Hash
+	Nonce    *big.Int
+	Deadline *big.Int
+}
+
+// signedField is one field of the signed struct with its EIP-712 encoding
+type signedField struct {
+	Name  string
+	Type  string
+	Word  []byte      // 32-byte encoded value
+	Value interface{} // Value in the typed data message
+}
+
+// signedFields lists the fields of the action's signed struct in order
+func (a *EscrowAuthorization) signedFields() []signedField {
+	uint256 := func(name string, value *big.Int) signedField {
+		return signedField{name, "uint256", common.LeftPadBytes(value.Bytes(), 32), value.String()}
+	}
+	executor := signedField{"executor", "address", common.LeftPadBytes(a.Executor.Bytes(), 32), a.Executor.Hex()}
+	team := signedField{"team", "bytes32", a.Team.Bytes(), a.Team.Hex()}
+
+	fields := []signedField{uint256("taskId", a.TaskID)}
+	switch a.Action {
+	case EscrowActionReleaseMilestone:
+		fields = append(fields, uint256("index", a.Index), executor, team)
+	case EscrowActionCancelTask:
+		fields = append(fields, uint256("executorAmount", a.Amount), executor, team)
+	case EscrowActionResignExecutor:
+		fields = append(fields, executor)
+	case EscrowActionTopUp:
+		fields = append(fields, uint256("amount", a.Amount))
+	}
+	return append(fields, uint256("nonce", a.Nonce), uint256("deadline", a.Deadline))
+}
+
+// detectEscrowV2 binds the escrow as v2 if its EIP-712 domain says so. A
+// reverted eip712Domain call (v1 has no such function) or an address without
+// code means v1. Any other error is returned, so the client is not cached and
+// a flaky RPC does not pin the escrow to v1.
+func detectEscrowV2(ctx context.Context, address common.Address, backend bind.ContractBackend) (*contracts.TaskEscrowV2, error) {
+	escrow, err := contracts.NewTaskEscrowV2(address, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create escrow v2 contract instance: %w", err)
+	}
+
+	domain, err := escrow.Eip712Domain(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		if isExecutionReverted(err) || errors.Is(err, bind.ErrNoCode) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to detect escrow version: %w", err)
+	}
+	if domain.Name != EscrowDomainName || domain.Version != EscrowDomainVersion {
+		return nil, nil
+	}
+	return escrow, nil
+}
+
+// isExecutionReverted reports whether a call failed because the contract
+// reverted, as opposed to the RPC failing. Nodes report reverts as JSON-RPC
+// error 3 or as -32000 with an "execution reverted" message.
+func isExecutionReverted(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == 3 {
+		return true
+	}
+	return strings.Contains(err.Error(), "execution reverted")
+}
+
+// IsEscrowV2 reports whether milestone payments and cancels need signatures
+func (c *BlockchainClient) IsEscrowV2() bool {
+	return c.EscrowV2 != nil
+}
+
+// EscrowDomainSeparator returns the v2 escrow's EIP-712 domain separator
+func (c *BlockchainClient) EscrowDomainSeparator() common.Hash {
+	return crypto.Keccak256Hash(
+		eip712DomainTypeHash.Bytes(),
+		crypto.Keccak256([]byte(EscrowDomainName)),
+		crypto.Keccak256([]byte(EscrowDomainVersion)),
+		common.LeftPadBytes(c.ChainID.Bytes(), 32),
+		common.LeftPadBytes(c.EscrowAddress.Bytes(), 32),
+	)
+}
+
+// TaskNonce returns the nonce the next signed action on the task must use
+func (c *BlockchainClient) TaskNonce(ctx context.Context, taskID uint64) (*big.Int, error) {
+	if c.EscrowV2 == nil {
+		return nil, ErrEscrowV2Required
+	}
+	nonce, err := c.EscrowV2.TaskNonces(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(taskID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task nonce: %w", err)
+	}
+	return nonce, nil
+}
+
+// taskPayees reads the task's executor (zero if none) and team hash from the escrow
+func (c *BlockchainClient) taskPayees(ctx context.Context, taskID uint64) (common.Address, common.Hash, error) {
+	task, err := c.Escrow.GetTask(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(taskID))
+	if err != nil {
+		return common.Address{}, common.Hash{}, fmt.Errorf("failed to get task: %w", err)
+	}
+	team, err := c.taskTeamHash(ctx, taskID)
+	if err != nil {
+		return common.Address{}, common.Hash{}, err
+	}
+	return task.Executor, team, nil
+}
+
+// NewReleaseAuthorization builds the creator's authorization to release
+// milestone index to the current payees, reading the task nonce, executor,
+// team and slice from the escrow
+func (c *BlockchainClient) NewReleaseAuthorization(ctx context.Context, taskID uint64, index int, deadline *big.Int) (*EscrowAuthorization, error) {
+	nonce, err := c.TaskNonce(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	executor, team, err := c.taskPayees(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if executor == (common.Address{}) {
+		return nil, fmt.Errorf("task has no executor on chain")
+	}
+	amount, err := c.MilestoneAmount(ctx, taskID, index)
+	if err != nil {
+		return nil, err
+	}
+	return &EscrowAuthorization{
+		Action:   EscrowActionReleaseMilestone,
+		TaskID:   new(big.Int).SetUint64(taskID),
+		Index:    big.NewInt(int64(index)),
+		Amount:   amount,
+		Executor: executor,
+		Team:     team,
+		Nonce:    nonce,
+		Deadline: deadline,
+	}, nil
+}
+
+// NewCancelAuthorization builds the authorization to cancel the task and pay
+// executorAmount of the remainder to the current payees, reading the task
+// nonce, executor and team from the escrow
+func (c *BlockchainClient) NewCancelAuthorization(ctx context.Context, taskID uint64, executorAmount, deadline *big.Int) (*EscrowAuthorization, error) {
+	nonce, err := c.TaskNonce(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	executor, team, err := c.taskPayees(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return &EscrowAuthorization{
+		Action:   EscrowActionCancelTask,
+		TaskID:   new(big.Int).SetUint64(taskID),
+		Amount:   executorAmount,
+		Executor: executor,
+		Team:     team,
+		Nonce:    nonce,
+		Deadline: deadline,
+	}, nil
+}
+
+// NewResignAuthorization builds the executor's authorization to give up a
+// task before anything is paid, reading the task nonce and executor from the
+// escrow
+func (c *BlockchainClient) NewResignAuthorization(ctx context.Context, taskID uint64, deadline *big.Int) (*EscrowAuthorization, error) {
+	nonce, err := c.TaskNonce(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	executor, team, err := c.taskPayees(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if executor == (common.Address{}) {
+		return nil, fmt.Errorf("task has no executor on chain")
+	}
+	return &EscrowAuthorization{
+		Action:   EscrowActionResignExecutor,
+		TaskID:   new(big.Int).SetUint64(taskID),
+		Amount:   big.NewInt(0),
+		Executor: executor,
+		Team:     team,
+		Nonce:    nonce,
+		Deadline: deadline,
+	}, nil
+}
+
+// NewTopUpAuthorization builds the creator's authorization to lock amount
+// more XZT for a task without an executor, reading the task nonce from the
+// escrow
+func (c *BlockchainClient) NewTopUpAuthorization(ctx context.Context, taskID uint64, amount, deadline *big.Int) (*EscrowAuthorization, error) {
+	nonce, err := c.TaskNonce(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	executor, team, err := c.taskPayees(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if executor != (common.Address{}) {
+		return nil, fmt.Errorf("task already has an executor on chain")
+	}
+	return &EscrowAuthorization{
+		Action:   EscrowActionTopUp,
+		TaskID:   new(big.Int).SetUint64(taskID),
+		Amount:   amount,
+		Executor: executor,
+		Team:     team,
+		Nonce:    nonce,
+		Deadline: deadline,
+	}, nil
+}
+
+// Digest returns the EIP-712 digest the signers sign
+func (c *BlockchainClient) Digest(auth *EscrowAuthorization) common.Hash {
+	fields := auth.signedFields()
+	members := make([]string, len(fields))
+	words := make([][]byte, len(fields)+1)
+	for i, field := range fields {
+		members[i] = field.Type + " " + field.Name
+		words[i+1] = field.Word
+	}
+	words[0] = crypto.Keccak256([]byte(auth.Action + "(" + strings.Join(members, ",") + ")"))
+	structHash := crypto.Keccak256Hash(words...)
+	domainSeparator := c.EscrowDomainSeparator()
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domainSeparator.Bytes(), structHash.Bytes())
+}
+
+// TypedData returns the action as eth_signTypedData_v4 input for wallets
+func (c *BlockchainClient) TypedData(auth *EscrowAuthorization) apitypes.TypedData {
+	var types []apitypes.Type
+	message := apitypes.TypedDataMessage{}
+	for _, field := range auth.signedFields() {
+		types = append(types, apitypes.Type{Name: field.Name, Type: field.Type})
+		message[field.Name] = field.Value
+	}
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			auth.Action: types,
+		},
+		PrimaryType: auth.Action,
+		Domain: apitypes.TypedDataDomain{
+			Name:              EscrowDomainName,
+			Version:           EscrowDomainVersion,
+			ChainId:           (*math.HexOrDecimal256)(c.ChainID),
+			VerifyingContract: c.EscrowAddress.Hex(),
+		},
+		Message: message,
+	}
+}
+
+// ParseSignature decodes a 65-byte hex signature, normalizing v to 27/28
+func ParseSignature(value string) ([]byte, error) {
+	sig, err := hexutil.Decode(value)
+	if err != nil || len(sig) != 65 {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	if sig[64] < 27 {
+		sig[64] += 27 // Some wallets return 0/1
+	}
+	return sig, nil
+}
+
+// VerifySignature checks that sig over digest was made by expected
+func VerifySignature(digest common.Hash, sig []byte, expected common.Address) error {
+	if len(sig) != 65 {
+		return fmt.Errorf("invalid signature length")
+	}
+	normalized := make([]byte, 65)
+	copy(normalized, sig)
+	if normalized[64] >= 27 {
+		normalized[64] -= 27
+	}
+
+	pub, err := crypto.SigToPub(digest.Bytes(), normalized)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	if signer := crypto.PubkeyToAddress(*pub); signer != expected {
+		return fmt.Errorf("signature is from %s, expected %s", signer.Hex(), expected.Hex())
+	}
+	return nil
+}
+
+// VerifyEscrowSignatures checks the signatures against the task's on-chain
+// creator and executor before the admin spends gas relaying them. A resign
+// is signed by the executor alone.
+func (c *BlockchainClient) VerifyEscrowSignatures(ctx context.Context, auth *EscrowAuthorization, creatorSig, executorSig []byte) error {
+	if auth.Deadline.Int64() < time.Now().Unix() {
+		return fmt.Errorf("authorization expired")
+	}
+
+	task, err := c.Escrow.GetTask(&bind.CallOpts{Context: ctx}, auth.TaskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+	if task.Executor != auth.Executor {
+		return fmt.Errorf("task executor changed to %s since the authorization was built", task.Executor.Hex())
+	}
+	team, err := c.taskTeamHash(ctx, auth.TaskID.Uint64())
+	if err != nil {
+		return err
+	}
+	if team != auth.Team {
+		return fmt.Errorf("task team changed since the authorization was built")
+	}
+
+	digest := c.Digest(auth)
+	if auth.Action != EscrowActionResignExecutor {
+		if err := VerifySignature(digest, creatorSig, task.Creator); err != nil {
+			return fmt.Errorf("creator signature: %w", err)
+		}
+	}
+	if auth.Action == EscrowActionResignExecutor || (auth.Action == EscrowActionCancelTask && auth.Amount.Sign() > 0) {
+		if err := VerifySignature(digest, executorSig, task.Executor); err != nil {
+			return fmt.Errorf("executor signature: %w", err)
+		}
+	}
+	return nil
+}
+
+// SignEscrowAuthorization signs the action with a participant's key (scripts and tests)
+func (c *BlockchainClient) SignEscrowAuthorization(privateKey *ecdsa.PrivateKey, auth *EscrowAuthorization) ([]byte, error) {
+	sig, err := crypto.Sign(c.Digest(auth).Bytes(), privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign escrow authorization: %w", err)
+	}
+	sig[64] += 27
+	return sig, nil
+}
+
+// ReleaseMilestoneSigned relays a creator-signed milestone release from the admin wallet
+func (c *BlockchainClient) ReleaseMilestoneSigned(ctx context.Context, auth *EscrowAuthorization, creatorSig []byte) (string, error) {
+	if c.EscrowV2 == nil {
+		return "", ErrEscrowV2Required
+	}
+
+	tx, err := c.EscrowV2.ReleaseMilestone(c.AdminAuth, auth.TaskID, auth.Index, auth.Deadline, creatorSig)
+	if err != nil {
+		return "", fmt.Errorf("failed to release milestone: %w", err)
+	}
+
+	receipt, err := c.waitMined(ctx, tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for transaction: %w", err)
+	}
+
+	if receipt.Status == 0 {
+		return "", fmt.Errorf("transaction failed")
+	}
+
+	return tx.Hash().Hex(), nil
+}
+
+// CancelTaskSigned relays a signed cancel from the admin wallet. executorSig
+// may be nil when the executor gets nothing.
+func (c *BlockchainClient) CancelTaskSigned(ctx context.Context, auth *EscrowAuthorization, creatorSig, executorSig []byte) (string, error) {
+	if c.EscrowV2 == nil {
+		return "", ErrEscrowV2Required
+	}
+
+	tx, err := c.EscrowV2.CancelTask(c.AdminAuth, auth.TaskID, auth.Amount, auth.Deadline, creatorSig, executorSig)
+	if err != nil {
+		return "", fmt.Errorf("failed to cancel task: %w", err)
+	}
+
+	receipt, err := c.waitMined(ctx, tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for transaction: %w", err)
+	}
+
+	if receipt.Status == 0 {
+		return "", fmt.Errorf("transaction failed")
+	}
+
+	return tx.Hash().Hex(), nil
+}
+
+// ResignExecutorSigned relays an executor-signed resignation from the admin
+// wallet, leaving the task without an executor or team
+func (c *BlockchainClient) ResignExecutorSigned(ctx context.Context, auth *EscrowAuthorization, executorSig []byte) (string, error) {
+	if c.EscrowV2 == nil {
+		return "", ErrEscrowV2Required
+	}
+
+	tx, err := c.EscrowV2.ResignExecutor(c.AdminAuth, auth.TaskID, auth.Deadline, executorSig)
+	if err != nil {
+		return "", fmt.Errorf("failed to resign executor: %w", err)
+	}
+
+	receipt, err := c.waitMined(ctx, tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for transaction: %w", err)
+	}
+
+	if receipt.Status == 0 {
+		return "", fmt.Errorf("transaction failed")
+	}
+
+	return tx.Hash().Hex(), nil
+}
+
+// TopUpSigned relays a creator-signed top-up from the admin wallet. The
+// creator's allowance must already cover the amount.
+func (c *BlockchainClient) TopUpSigned(ctx context.Context, auth *EscrowAuthorization, creatorSig []byte) (string, error) {
+	if c.EscrowV2 == nil {
+		return "", ErrEscrowV2Required
+	}
+
+	tx, err := c.EscrowV2.TopUp(c.AdminAuth, auth.TaskID, auth.Amount, auth.Deadline, creatorSig)
+	if err != nil {
+		return "", fmt.Errorf("failed to top up task: %w", err)
+	}
+
+	receipt, err := c.waitMined(ctx, tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for transaction: %w", err)
+	}
+
+	if receipt.Status == 0 {
+		return "", fmt.Errorf("transaction failed")
+	}
+
+	return tx.Hash().Hex(), nil
+}
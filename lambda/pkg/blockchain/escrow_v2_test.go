@@ -0,0 +1,607 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// newOpenTask creates a v2 task of 100 XZT paid in two halves, without an
+// executor
+func newOpenTask(t *testing.T, chain *testChain) uint64 {
+	t.Helper()
+
+	creator := address(chain.creator)
+	chain.fund(t, creator, xzt(100))
+	permit, err := chain.SignPermit(chain.creator, xzt(100), big.NewInt(chain.now(t)+3600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	taskID, _, err := chain.CreateTaskWithPermit(creator.Hex(), xzt(100), []uint16{5000, 5000}, permit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return taskID
+}
+
+// newAssignedTask creates a v2 task of 100 XZT paid in two halves and sets
+// the executor
+func newAssignedTask(t *testing.T, chain *testChain) uint64 {
+	t.Helper()
+
+	taskID := newOpenTask(t, chain)
+	if _, err := chain.SetExecutor(taskID, address(chain.executor).Hex()); err != nil {
+		t.Fatal(err)
+	}
+	return taskID
+}
+
+// validDeadline passes both the escrow's block-time check and
+// VerifyEscrowSignatures' wall-clock check
+func validDeadline() *big.Int {
+	return big.NewInt(time.Now().Add(time.Hour).Unix())
+}
+
+func TestReleaseMilestoneSigned(t *testing.T) {
+	chain := newTestChain(t, "TaskEscrowV2")
+	ctx := context.Background()
+	taskID := newAssignedTask(t, chain)
+
+	auth, err := chain.NewReleaseAuthorization(ctx, taskID, 0, validDeadline())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth.Executor != address(chain.executor) {
+		t.Fatalf("authorization executor %s, want %s", auth.Executor.Hex(), address(chain.executor).Hex())
+	}
+	sig, err := chain.SignEscrowAuthorization(chain.creator, auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.VerifyEscrowSignatures(ctx, auth, sig, nil); err != nil {
+		t.Fatalf("VerifyEscrowSignatures: %v", err)
+	}
+	if _, err := chain.ReleaseMilestoneSigned(ctx, auth, sig); err != nil {
+		t.Fatalf("ReleaseMilestoneSigned: %v", err)
+	}
+	assertBalance(t, chain, address(chain.executor), xzt(50))
+
+	// The nonce is spent, so the same signature cannot release again
+	if _, err := chain.ReleaseMilestoneSigned(ctx, auth, sig); err == nil {
+		t.Fatal("replayed release succeeded")
+	}
+	assertBalance(t, chain, address(chain.executor), xzt(50))
+}
+
+func TestReleaseMilestoneRejectsBadSignatures(t *testing.T) {
+	cases := []struct {
+		name  string
+		sign  func(chain *testChain, auth *EscrowAuthorization) ([]byte, error)
+		check bool // VerifyEscrowSignatures also rejects it
+	}{
+		{
+			name: "wrong signer",
+			sign: func(chain *testChain, auth *EscrowAuthorization) ([]byte, error) {
+				return chain.SignEscrowAuthorization(chain.executor, auth)
+			},
+			check: true,
+		},
+		{
+			name: "other executor",
+			sign: func(chain *testChain, auth *EscrowAuthorization) ([]byte, error) {
+				// The creator signed a release to someone else; relaying it
+				// for the actual executor must not pay
+				other := *auth
+				other.Executor = address(chain.admin)
+				return chain.SignEscrowAuthorization(chain.creator, &other)
+			},
+			check: true,
+		},
+		{
+			name: "expired",
+			sign: func(chain *testChain, auth *EscrowAuthorization) ([]byte, error) {
+				auth.Deadline = big.NewInt(0)
+				return chain.SignEscrowAuthorization(chain.creator, auth)
+			},
+			check: true,
+		},
+		{
+			name: "expired on chain",
+			sign: func(chain *testChain, auth *EscrowAuthorization) ([]byte, error) {
+				// Still in the future by the wall clock, but past in block time
+				auth.Deadline = big.NewInt(chain.now(t) - 1)
+				return chain.SignEscrowAuthorization(chain.creator, auth)
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			chain := newTestChain(t, "TaskEscrowV2")
+			ctx := context.Background()
+			taskID := newAssignedTask(t, chain)
+
+			auth, err := chain.NewReleaseAuthorization(ctx, taskID, 0, validDeadline())
+			if err != nil {
+				t.Fatal(err)
+			}
+			sig, err := tc.sign(chain, auth)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.check {
+				if err := chain.VerifyEscrowSignatures(ctx, auth, sig, nil); err == nil {
+					t.Fatal("VerifyEscrowSignatures accepted a bad signature")
+				}
+			}
+			if _, err := chain.ReleaseMilestoneSigned(ctx, auth, sig); err == nil {
+				t.Fatal("escrow accepted a bad signature")
+			}
+			assertBalance(t, chain, address(chain.executor), big.NewInt(0))
+			assertBalance(t, chain, chain.EscrowAddress, xzt(100))
+		})
+	}
+}
+
+func TestCancelTaskSigned(t *testing.T) {
+	chain := newTestChain(t, "TaskEscrowV2")
+	ctx := context.Background()
+	taskID := newAssignedTask(t, chain)
+
+	auth, err := chain.NewCancelAuthorization(ctx, taskID, xzt(30), validDeadline())
+	if err != nil {
+		t.Fatal(err)
+	}
+	creatorSig, err := chain.SignEscrowAuthorization(chain.creator, auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	executorSig, err := chain.SignEscrowAuthorization(chain.executor, auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.VerifyEscrowSignatures(ctx, auth, creatorSig, executorSig); err != nil {
+		t.Fatalf("VerifyEscrowSignatures: %v", err)
+	}
+	if _, err := chain.CancelTaskSigned(ctx, auth, creatorSig, executorSig); err != nil {
+		t.Fatalf("CancelTaskSigned: %v", err)
+	}
+	assertBalance(t, chain, address(chain.executor), xzt(30))
+	assertBalance(t, chain, address(chain.creator), xzt(70))
+	assertBalance(t, chain, chain.EscrowAddress, big.NewInt(0))
+}
+
+func TestCancelTaskRejectsBadSignatures(t *testing.T) {
+	cases := []struct {
+		name  string
+		sign  func(chain *testChain, auth *EscrowAuthorization) (creatorSig, executorSig []byte, err error)
+		check bool // VerifyEscrowSignatures also rejects it
+	}{
+		{
+			name: "missing executor",
+			sign: func(chain *testChain, auth *EscrowAuthorization) ([]byte, []byte, error) {
+				creatorSig, err := chain.SignEscrowAuthorization(chain.creator, auth)
+				return creatorSig, nil, err
+			},
+			check: true,
+		},
+		{
+			name: "creator signs for executor",
+			sign: func(chain *testChain, auth *EscrowAuthorization) ([]byte, []byte, error) {
+				creatorSig, err := chain.SignEscrowAuthorization(chain.creator, auth)
+				return creatorSig, creatorSig, err
+			},
+			check: true,
+		},
+		{
+			name: "other executor",
+			sign: func(chain *testChain, auth *EscrowAuthorization) ([]byte, []byte, error) {
+				other := *auth
+				other.Executor = address(chain.admin)
+				creatorSig, err := chain.SignEscrowAuthorization(chain.creator, &other)
+				if err != nil {
+					return nil, nil, err
+				}
+				executorSig, err := chain.SignEscrowAuthorization(chain.executor, &other)
+				return creatorSig, executorSig, err
+			},
+			check: true,
+		},
+		{
+			name: "expired on chain",
+			sign: func(chain *testChain, auth *EscrowAuthorization) ([]byte, []byte, error) {
+				auth.Deadline = big.NewInt(chain.now(t) - 1)
+				creatorSig, err := chain.SignEscrowAuthorization(chain.creator, auth)
+				if err != nil {
+					return nil, nil, err
+				}
+				executorSig, err := chain.SignEscrowAuthorization(chain.executor, auth)
+				return creatorSig, executorSig, err
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			chain := newTestChain(t, "TaskEscrowV2")
+			ctx := context.Background()
+			taskID := newAssignedTask(t, chain)
+
+			auth, err := chain.NewCancelAuthorization(ctx, taskID, xzt(30), validDeadline())
+			if err != nil {
+				t.Fatal(err)
+			}
+			creatorSig, executorSig, err := tc.sign(chain, auth)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.check {
+				if err := chain.VerifyEscrowSignatures(ctx, auth, creatorSig, executorSig); err == nil {
+					t.Fatal("VerifyEscrowSignatures accepted a bad signature")
+				}
+			}
+			if _, err := chain.CancelTaskSigned(ctx, auth, creatorSig, executorSig); err == nil {
+				t.Fatal("escrow accepted a bad signature")
+			}
+			_, _, _, _, cancelled, err := chain.GetTask(taskID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cancelled {
+				t.Fatal("task cancelled with a bad signature")
+			}
+			assertBalance(t, chain, chain.EscrowAddress, xzt(100))
+		})
+	}
+}
+
+func TestSetExecutorOnlyOnce(t *testing.T) {
+	chain := newTestChain(t, "TaskEscrowV2")
+	ctx := context.Background()
+	taskID := newAssignedTask(t, chain)
+
+	// The admin cannot swap the payee on its own
+	if _, err := chain.SetExecutor(taskID, address(chain.admin).Hex()); err == nil {
+		t.Fatal("SetExecutor replaced an executor")
+	}
+
+	// A release signed for the original executor fails once the creator
+	// has changed the executor
+	auth, err := chain.NewReleaseAuthorization(ctx, taskID, 0, validDeadline())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := chain.SignEscrowAuthorization(chain.creator, auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changeExecutor(t, chain, taskID, address(chain.admin))
+	err = chain.VerifyEscrowSignatures(ctx, auth, sig, nil)
+	if err == nil || !strings.Contains(err.Error(), "executor changed") {
+		t.Fatalf("VerifyEscrowSignatures = %v, want executor changed", err)
+	}
+	if _, err := chain.ReleaseMilestoneSigned(ctx, auth, sig); err == nil {
+		t.Fatal("release signed for the old executor succeeded")
+	}
+	assertBalance(t, chain, chain.EscrowAddress, xzt(100))
+}
+
+// changeExecutor replaces the executor with the creator's ChangeExecutor signature
+func changeExecutor(t *testing.T, chain *testChain, taskID uint64, executor common.Address) {
+	t.Helper()
+
+	ctx := context.Background()
+	nonce, err := chain.TaskNonce(ctx, taskID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := validDeadline()
+	structHash := crypto.Keccak256Hash(
+		crypto.Keccak256([]byte("ChangeExecutor(uint256 taskId,address executor,uint256 nonce,uint256 deadline)")),
+		common.LeftPadBytes(new(big.Int).SetUint64(taskID).Bytes(), 32),
+		common.LeftPadBytes(executor.Bytes(), 32),
+		common.LeftPadBytes(nonce.Bytes(), 32),
+		common.LeftPadBytes(deadline.Bytes(), 32),
+	)
+	digest := crypto.Keccak256([]byte{0x19, 0x01}, chain.EscrowDomainSeparator().Bytes(), structHash.Bytes())
+	sig, err := crypto.Sign(digest, chain.creator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[64] += 27
+
+	tx, err := chain.EscrowV2.ChangeExecutor(chain.AdminAuth, new(big.Int).SetUint64(taskID), executor, deadline, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := chain.waitMined(ctx, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Status == 0 {
+		t.Fatal("changeExecutor failed")
+	}
+}
+
+// newTeamTask creates a v2 task of 100 XZT paid in two halves and sets a
+// team led by the executor, splitting 60/40 with a fresh address
+func newTeamTask(t *testing.T, chain *testChain) (uint64, common.Address) {
+	t.Helper()
+
+	creator := address(chain.creator)
+	chain.fund(t, creator, xzt(100))
+	permit, err := chain.SignPermit(chain.creator, xzt(100), big.NewInt(chain.now(t)+3600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	taskID, _, err := chain.CreateTaskWithPermit(creator.Hex(), xzt(100), []uint16{5000, 5000}, permit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	member := address(key)
+	if _, err := chain.SetExecutorTeam(taskID, []string{address(chain.executor).Hex(), member.Hex()}, []uint16{6000, 4000}); err != nil {
+		t.Fatal(err)
+	}
+	return taskID, member
+}
+
+func TestReleaseMilestoneToTeam(t *testing.T) {
+	chain := newTestChain(t, "TaskEscrowV2")
+	ctx := context.Background()
+	taskID, member := newTeamTask(t, chain)
+
+	onChain, err := chain.EscrowV2.TeamHash(nil, new(big.Int).SetUint64(taskID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := TeamHash([]common.Address{address(chain.executor), member}, []uint16{6000, 4000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if common.Hash(onChain) != want {
+		t.Fatalf("escrow team hash %x, want %s", onChain, want.Hex())
+	}
+
+	auth, err := chain.NewReleaseAuthorization(ctx, taskID, 0, validDeadline())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth.Team != want {
+		t.Fatalf("authorization team %s, want %s", auth.Team.Hex(), want.Hex())
+	}
+
+	// A release signed without the team does not pay it
+	noTeam := *auth
+	noTeam.Team = common.Hash{}
+	sig, err := chain.SignEscrowAuthorization(chain.creator, &noTeam)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.VerifyEscrowSignatures(ctx, auth, sig, nil); err == nil {
+		t.Fatal("VerifyEscrowSignatures accepted a release signed without the team")
+	}
+	if _, err := chain.ReleaseMilestoneSigned(ctx, auth, sig); err == nil {
+		t.Fatal("escrow accepted a release signed without the team")
+	}
+
+	sig, err = chain.SignEscrowAuthorization(chain.creator, auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.VerifyEscrowSignatures(ctx, auth, sig, nil); err != nil {
+		t.Fatalf("VerifyEscrowSignatures: %v", err)
+	}
+	if _, err := chain.ReleaseMilestoneSigned(ctx, auth, sig); err != nil {
+		t.Fatalf("ReleaseMilestoneSigned: %v", err)
+	}
+	assertBalance(t, chain, address(chain.executor), xzt(30))
+	assertBalance(t, chain, member, xzt(20))
+}
+
+func TestSetExecutorTeamOnlyOnce(t *testing.T) {
+	chain := newTestChain(t, "TaskEscrowV2")
+	taskID := newAssignedTask(t, chain)
+
+	// The admin cannot turn an assigned task into a team task
+	_, err := chain.SetExecutorTeam(taskID, []string{address(chain.executor).Hex(), address(chain.admin).Hex()}, []uint16{5000, 5000})
+	if err == nil {
+		t.Fatal("SetExecutorTeam replaced an executor")
+	}
+
+	teamTaskID, _ := newTeamTask(t, chain)
+	_, err = chain.SetExecutorTeam(teamTaskID, []string{address(chain.executor).Hex(), address(chain.admin).Hex()}, []uint16{5000, 5000})
+	if err == nil {
+		t.Fatal("SetExecutorTeam replaced a team")
+	}
+	if _, err := chain.SetExecutor(teamTaskID, address(chain.admin).Hex()); err == nil {
+		t.Fatal("SetExecutor replaced a team")
+	}
+}
+
+func TestResignExecutorSigned(t *testing.T) {
+	chain := newTestChain(t, "TaskEscrowV2")
+	ctx := context.Background()
+	taskID, _ := newTeamTask(t, chain)
+
+	auth, err := chain.NewResignAuthorization(ctx, taskID, validDeadline())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := chain.SignEscrowAuthorization(chain.executor, auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.VerifyEscrowSignatures(ctx, auth, nil, sig); err != nil {
+		t.Fatalf("VerifyEscrowSignatures: %v", err)
+	}
+	if _, err := chain.ResignExecutorSigned(ctx, auth, sig); err != nil {
+		t.Fatalf("ResignExecutorSigned: %v", err)
+	}
+
+	// The executor and team are gone, so the admin can assign someone else
+	executor, team, err := chain.taskPayees(ctx, taskID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if executor != (common.Address{}) || team != (common.Hash{}) {
+		t.Fatalf("payees after resign = %s, %s; want none", executor.Hex(), team.Hex())
+	}
+	if _, err := chain.SetExecutor(taskID, address(chain.admin).Hex()); err != nil {
+		t.Fatalf("SetExecutor after resign: %v", err)
+	}
+	assertBalance(t, chain, chain.EscrowAddress, xzt(100))
+}
+
+func TestResignExecutorRejected(t *testing.T) {
+	cases := []struct {
+		name  string
+		setup func(t *testing.T, chain *testChain, taskID uint64)
+		key   func(chain *testChain) *ecdsa.PrivateKey
+		check bool // VerifyEscrowSignatures also rejects it
+	}{
+		{
+			name:  "creator signs for executor",
+			key:   func(chain *testChain) *ecdsa.PrivateKey { return chain.creator },
+			check: true,
+		},
+		{
+			name: "milestone already paid",
+			setup: func(t *testing.T, chain *testChain, taskID uint64) {
+				release, err := chain.NewReleaseAuthorization(context.Background(), taskID, 0, validDeadline())
+				if err != nil {
+					t.Fatal(err)
+				}
+				sig, err := chain.SignEscrowAuthorization(chain.creator, release)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := chain.ReleaseMilestoneSigned(context.Background(), release, sig); err != nil {
+					t.Fatal(err)
+				}
+			},
+			key: func(chain *testChain) *ecdsa.PrivateKey { return chain.executor },
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			chain := newTestChain(t, "TaskEscrowV2")
+			ctx := context.Background()
+			taskID := newAssignedTask(t, chain)
+			if tc.setup != nil {
+				tc.setup(t, chain, taskID)
+			}
+
+			auth, err := chain.NewResignAuthorization(ctx, taskID, validDeadline())
+			if err != nil {
+				t.Fatal(err)
+			}
+			sig, err := chain.SignEscrowAuthorization(tc.key(chain), auth)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.check {
+				if err := chain.VerifyEscrowSignatures(ctx, auth, nil, sig); err == nil {
+					t.Fatal("VerifyEscrowSignatures accepted a bad signature")
+				}
+			}
+			if _, err := chain.ResignExecutorSigned(ctx, auth, sig); err == nil {
+				t.Fatal("escrow accepted the resign")
+			}
+			executor, _, err := chain.taskPayees(ctx, taskID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if executor != address(chain.executor) {
+				t.Fatalf("executor = %s, want unchanged", executor.Hex())
+			}
+		})
+	}
+}
+
+// approveEscrow lets the escrow pull amount from the creator
+func approveEscrow(t *testing.T, chain *testChain, amount *big.Int) {
+	t.Helper()
+
+	approver := &fakeApprover{chain: chain}
+	if err := approver.Approve(context.Background(), ApprovalRequest{Owner: address(chain.creator), Amount: amount}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTopUpSigned(t *testing.T) {
+	chain := newTestChain(t, "TaskEscrowV2")
+	ctx := context.Background()
+	taskID := newOpenTask(t, chain)
+	chain.fund(t, address(chain.creator), xzt(20))
+	approveEscrow(t, chain, xzt(20))
+
+	auth, err := chain.NewTopUpAuthorization(ctx, taskID, xzt(20), validDeadline())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := chain.SignEscrowAuthorization(chain.creator, auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.VerifyEscrowSignatures(ctx, auth, sig, nil); err != nil {
+		t.Fatalf("VerifyEscrowSignatures: %v", err)
+	}
+	if _, err := chain.TopUpSigned(ctx, auth, sig); err != nil {
+		t.Fatalf("TopUpSigned: %v", err)
+	}
+	_, _, total, _, _, err := chain.GetTask(taskID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total.Cmp(xzt(120)) != 0 {
+		t.Fatalf("total = %s, want %s", total, xzt(120))
+	}
+
+	// The signature consumed the task nonce
+	if _, err := chain.TopUpSigned(ctx, auth, sig); err == nil {
+		t.Fatal("top-up signature was replayed")
+	}
+}
+
+func TestTopUpRequiresCreator(t *testing.T) {
+	chain := newTestChain(t, "TaskEscrowV2")
+	ctx := context.Background()
+	taskID := newOpenTask(t, chain)
+
+	// A lingering allowance is not the creator's consent to a top-up
+	chain.fund(t, address(chain.creator), xzt(20))
+	approveEscrow(t, chain, xzt(20))
+
+	auth, err := chain.NewTopUpAuthorization(ctx, taskID, xzt(20), validDeadline())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := chain.SignEscrowAuthorization(chain.admin, auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.VerifyEscrowSignatures(ctx, auth, sig, nil); err == nil {
+		t.Fatal("VerifyEscrowSignatures accepted the admin's signature")
+	}
+	if _, err := chain.TopUpSigned(ctx, auth, sig); err == nil {
+		t.Fatal("escrow accepted a top-up signed by the admin")
+	}
+
+	// Nor is a permit the creator did not sign
+	permit, err := chain.SignPermit(chain.admin, xzt(20), big.NewInt(chain.now(t)+3600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.TopUpWithPermit(taskID, xzt(20), permit); err == nil {
+		t.Fatal("escrow accepted a top-up with the admin's permit")
+	}
+	assertBalance(t, chain, chain.EscrowAddress, xzt(100))
+}
@@ -0,0 +1,402 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/x-zero/xz-wallet/pkg/blockchain/contracts"
+)
+
+// History entry types
+const (
+	HistoryTaskLock          = "task_lock"
+	HistoryMilestoneReceived = "milestone_received"
+	HistoryRefund            = "refund"
+	HistoryTransferIn        = "transfer_in"
+	HistoryTransferOut       = "transfer_out"
+)
+
+// HistoryEntry is one XZT movement in or out of a wallet
+type HistoryEntry struct {
+	TxHash         string
+	LogIndex       uint
+	BlockNumber    uint64
+	Timestamp      time.Time
+	Type           string
+	Direction      string // "in" or "out"
+	Counterparty   common.Address
+	Amount         *big.Int
+	ContractTaskID *big.Int       // Set for escrow movements
+	EscrowAddress  common.Address // Escrow holding the task, for escrow movements
+}
+
+// historyCancel marks a cancel of a task created outside the scanned range.
+// Whether the owner got a refund or an executor share is looked up on chain
+// only if one of the owner's transfers belongs to it.
+const historyCancel = "cancel"
+
+// defaultLogRange is the block span of one eth_getLogs call; most providers cap it
+const defaultLogRange = 10000
+
+// escrowAction is the escrow event found in the same transaction as a transfer
+type escrowAction struct {
+	Type   string
+	TaskID *big.Int
+	Escrow common.Address
+}
+
+// historyStartBlock returns HISTORY_START_BLOCK (the escrow deployment block), or 0
+func historyStartBlock() uint64 {
+	if value := os.Getenv("HISTORY_START_BLOCK"); value != "" {
+		if block, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return block
+		}
+	}
+	return 0
+}
+
+// historyLogRange returns HISTORY_LOG_RANGE, the blocks per log query (default 10000)
+func historyLogRange() uint64 {
+	if value := os.Getenv("HISTORY_LOG_RANGE"); value != "" {
+		if blocks, err := strconv.ParseUint(value, 10, 64); err == nil && blocks > 0 {
+			return blocks
+		}
+	}
+	return defaultLogRange
+}
+
+// BlockRange converts a time range to the blocks mined in it. A zero from or
+// to leaves that end open (chain start block, latest block). ok is false
+// when no block falls in the range.
+func (c *BlockchainClient) BlockRange(ctx context.Context, from, to time.Time) (first, last uint64, ok bool, err error) {
+	first = historyStartBlock()
+	if c.Chain != nil {
+		first = c.Chain.StartBlock
+	}
+	last, err = c.Client.BlockNumber(ctx)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	if first > last {
+		return 0, 0, false, nil
+	}
+
+	if !from.IsZero() {
+		block, err := c.firstBlockAt(ctx, from, first, last)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		first = block
+	}
+	if !to.IsZero() {
+		// The last block at or before to is the one before the first block after it
+		block, err := c.firstBlockAt(ctx, to.Add(time.Second), first, last)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if block == 0 {
+			return 0, 0, false, nil
+		}
+		last = block - 1
+	}
+	return first, last, first <= last, nil
+}
+
+// firstBlockAt binary-searches [low, high] for the first block mined at or
+// after t; high+1 if there is none
+func (c *BlockchainClient) firstBlockAt(ctx context.Context, t time.Time, low, high uint64) (uint64, error) {
+	target := t.Unix()
+	end := high + 1
+	for low < end {
+		mid := low + (end-low)/2
+		header, err := c.Client.HeaderByNumber(ctx, new(big.Int).SetUint64(mid))
+		if err != nil {
+			return 0, fmt.Errorf("failed to get block %d: %w", mid, err)
+		}
+		if int64(header.Time) >= target {
+			end = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	return low, nil
+}
+
+// WalletHistory merges XZT Transfer events with escrow events for owner in
+// blocks [first, last], newest first. Transfers to or from an escrow
+// (current or legacy) are labelled by the escrow event emitted in the same
+// transaction. Logs are read HISTORY_LOG_RANGE blocks at a time. Timestamps
+// are left zero; call FillTimestamps for the entries actually returned.
+func (c *BlockchainClient) WalletHistory(ctx context.Context, owner common.Address, first, last uint64) ([]HistoryEntry, error) {
+	escrows := []common.Address{c.EscrowAddress}
+	if c.Chain != nil {
+		escrows = c.Chain.Escrows()
+	}
+
+	isEscrow := make(map[common.Address]bool)
+	createdTasks := make(map[common.Address]map[string]bool)
+	for _, escrow := range escrows {
+		isEscrow[escrow] = true
+		createdTasks[escrow] = make(map[string]bool)
+	}
+	cancelTypes := make(map[string]string)
+
+	var entries []HistoryEntry
+	logRange := historyLogRange()
+	for start := first; start <= last; start += logRange {
+		end := start + logRange - 1
+		if end > last || end < start {
+			end = last
+		}
+		opts := &bind.FilterOpts{Start: start, End: &end, Context: ctx}
+
+		// Escrow events share a transaction, and so a block, with their
+		// transfers, so each range is labelled on its own
+		actions := make(map[common.Hash]escrowAction)
+		for _, escrow := range escrows {
+			filterer, err := contracts.NewTaskEscrowFilterer(escrow, c.Client)
+			if err != nil {
+				return nil, fmt.Errorf("failed to bind escrow %s: %w", escrow.Hex(), err)
+			}
+			if err := escrowActions(filterer, escrow, opts, owner, actions, createdTasks[escrow]); err != nil {
+				return nil, err
+			}
+			if err := c.escrowV2Actions(escrow, opts, owner, actions); err != nil {
+				return nil, err
+			}
+		}
+
+		outgoing, err := c.Token.FilterTransfer(opts, []common.Address{owner}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to filter outgoing transfers: %w", err)
+		}
+		for outgoing.Next() {
+			ev := outgoing.Event
+			entry := HistoryEntry{
+				TxHash:       ev.Raw.TxHash.Hex(),
+				LogIndex:     ev.Raw.Index,
+				BlockNumber:  ev.Raw.BlockNumber,
+				Type:         HistoryTransferOut,
+				Direction:    "out",
+				Counterparty: ev.To,
+				Amount:       ev.Value,
+			}
+			if isEscrow[ev.To] {
+				if action, ok := actions[ev.Raw.TxHash]; ok && action.Type == HistoryTaskLock {
+					entry.Type = action.Type
+					entry.ContractTaskID = action.TaskID
+					entry.EscrowAddress = action.Escrow
+				}
+			}
+			entries = append(entries, entry)
+		}
+		if err := outgoing.Error(); err != nil {
+			return nil, fmt.Errorf("failed to read outgoing transfers: %w", err)
+		}
+		outgoing.Close()
+
+		incoming, err := c.Token.FilterTransfer(opts, nil, []common.Address{owner})
+		if err != nil {
+			return nil, fmt.Errorf("failed to filter incoming transfers: %w", err)
+		}
+		for incoming.Next() {
+			ev := incoming.Event
+			entry := HistoryEntry{
+				TxHash:       ev.Raw.TxHash.Hex(),
+				LogIndex:     ev.Raw.Index,
+				BlockNumber:  ev.Raw.BlockNumber,
+				Type:         HistoryTransferIn,
+				Direction:    "in",
+				Counterparty: ev.From,
+				Amount:       ev.Value,
+			}
+			if isEscrow[ev.From] {
+				if action, ok := actions[ev.Raw.TxHash]; ok && action.Type != HistoryTaskLock {
+					if action.Type == historyCancel {
+						action.Type, err = c.cancelType(ctx, action, owner, cancelTypes)
+						if err != nil {
+							incoming.Close()
+							return nil, err
+						}
+					}
+					entry.Type = action.Type
+					entry.ContractTaskID = action.TaskID
+					entry.EscrowAddress = action.Escrow
+				}
+			}
+			entries = append(entries, entry)
+		}
+		if err := incoming.Error(); err != nil {
+			return nil, fmt.Errorf("failed to read incoming transfers: %w", err)
+		}
+		incoming.Close()
+
+		if end == last {
+			break
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].BlockNumber != entries[j].BlockNumber {
+			return entries[i].BlockNumber > entries[j].BlockNumber
+		}
+		return entries[i].LogIndex > entries[j].LogIndex
+	})
+
+	return entries, nil
+}
+
+// cancelType labels owner's share of a cancel whose task was created outside
+// the scanned range: a refund if owner created the task, otherwise income
+func (c *BlockchainClient) cancelType(ctx context.Context, action escrowAction, owner common.Address, known map[string]string) (string, error) {
+	key := action.Escrow.Hex() + ":" + action.TaskID.String()
+	if t, ok := known[key]; ok {
+		return t, nil
+	}
+
+	caller, err := contracts.NewTaskEscrowCaller(action.Escrow, c.Client)
+	if err != nil {
+		return "", fmt.Errorf("failed to bind escrow %s: %w", action.Escrow.Hex(), err)
+	}
+	task, err := caller.GetTask(&bind.CallOpts{Context: ctx}, action.TaskID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get task %s: %w", action.TaskID, err)
+	}
+
+	t := HistoryMilestoneReceived
+	if task.Creator == owner {
+		t = HistoryRefund
+	}
+	known[key] = t
+	return t, nil
+}
+
+// escrowActions indexes one escrow's events that moved owner's XZT by
+// transaction hash. createdTasks collects the owner's tasks across ranges.
+func escrowActions(filterer *contracts.TaskEscrowFilterer, escrow common.Address, opts *bind.FilterOpts, owner common.Address, actions map[common.Hash]escrowAction, createdTasks map[string]bool) error {
+	created, err := filterer.FilterTaskCreated(opts, nil, []common.Address{owner}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to filter task creations: %w", err)
+	}
+	for created.Next() {
+		ev := created.Event
+		actions[ev.Raw.TxHash] = escrowAction{Type: HistoryTaskLock, TaskID: ev.TaskId, Escrow: escrow}
+		createdTasks[ev.TaskId.String()] = true
+	}
+	if err := created.Error(); err != nil {
+		return fmt.Errorf("failed to read task creations: %w", err)
+	}
+	created.Close()
+
+	paid, err := filterer.FilterMilestonePaid(opts, nil, []common.Address{owner})
+	if err != nil {
+		return fmt.Errorf("failed to filter milestone payments: %w", err)
+	}
+	for paid.Next() {
+		ev := paid.Event
+		actions[ev.Raw.TxHash] = escrowAction{Type: HistoryMilestoneReceived, TaskID: ev.TaskId, Escrow: escrow}
+	}
+	if err := paid.Error(); err != nil {
+		return fmt.Errorf("failed to read milestone payments: %w", err)
+	}
+	paid.Close()
+
+	// TaskCancelled is indexed only by task, so match it against tasks the owner
+	// created (refund). An executor's share of a cancel counts as milestone
+	// income. Tasks created before the scanned range are resolved on demand.
+	cancelled, err := filterer.FilterTaskCancelled(opts, nil)
+	if err != nil {
+		return fmt.Errorf("failed to filter task cancellations: %w", err)
+	}
+	for cancelled.Next() {
+		ev := cancelled.Event
+		if createdTasks[ev.TaskId.String()] {
+			actions[ev.Raw.TxHash] = escrowAction{Type: HistoryRefund, TaskID: ev.TaskId, Escrow: escrow}
+		} else {
+			actions[ev.Raw.TxHash] = escrowAction{Type: historyCancel, TaskID: ev.TaskId, Escrow: escrow}
+		}
+	}
+	if err := cancelled.Error(); err != nil {
+		return fmt.Errorf("failed to read task cancellations: %w", err)
+	}
+	cancelled.Close()
+
+	return nil
+}
+
+// escrowV2Actions indexes v2 top-ups by owner and partial refunds and team
+// payouts to owner; v1 escrows never emit them
+func (c *BlockchainClient) escrowV2Actions(escrow common.Address, opts *bind.FilterOpts, owner common.Address, actions map[common.Hash]escrowAction) error {
+	filterer, err := contracts.NewTaskEscrowV2Filterer(escrow, c.Client)
+	if err != nil {
+		return fmt.Errorf("failed to bind escrow %s: %w", escrow.Hex(), err)
+	}
+
+	toppedUp, err := filterer.FilterTaskToppedUp(opts, nil, []common.Address{owner})
+	if err != nil {
+		return fmt.Errorf("failed to filter top-ups: %w", err)
+	}
+	for toppedUp.Next() {
+		ev := toppedUp.Event
+		actions[ev.Raw.TxHash] = escrowAction{Type: HistoryTaskLock, TaskID: ev.TaskId, Escrow: escrow}
+	}
+	if err := toppedUp.Error(); err != nil {
+		return fmt.Errorf("failed to read top-ups: %w", err)
+	}
+	toppedUp.Close()
+
+	refunded, err := filterer.FilterTaskRefunded(opts, nil, []common.Address{owner})
+	if err != nil {
+		return fmt.Errorf("failed to filter partial refunds: %w", err)
+	}
+	for refunded.Next() {
+		ev := refunded.Event
+		actions[ev.Raw.TxHash] = escrowAction{Type: HistoryRefund, TaskID: ev.TaskId, Escrow: escrow}
+	}
+	if err := refunded.Error(); err != nil {
+		return fmt.Errorf("failed to read partial refunds: %w", err)
+	}
+	refunded.Close()
+
+	// Team members other than the lead are not in MilestonePaid
+	teamPaid, err := filterer.FilterTeamMemberPaid(opts, nil, []common.Address{owner})
+	if err != nil {
+		return fmt.Errorf("failed to filter team payouts: %w", err)
+	}
+	for teamPaid.Next() {
+		ev := teamPaid.Event
+		actions[ev.Raw.TxHash] = escrowAction{Type: HistoryMilestoneReceived, TaskID: ev.TaskId, Escrow: escrow}
+	}
+	if err := teamPaid.Error(); err != nil {
+		return fmt.Errorf("failed to read team payouts: %w", err)
+	}
+	teamPaid.Close()
+
+	return nil
+}
+
+// FillTimestamps sets each entry's block time, fetching every block header once
+func (c *BlockchainClient) FillTimestamps(ctx context.Context, entries []HistoryEntry) error {
+	times := make(map[uint64]time.Time)
+	for i := range entries {
+		block := entries[i].BlockNumber
+		t, ok := times[block]
+		if !ok {
+			header, err := c.Client.HeaderByNumber(ctx, new(big.Int).SetUint64(block))
+			if err != nil {
+				return fmt.Errorf("failed to get block %d: %w", block, err)
+			}
+			t = time.Unix(int64(header.Time), 0).UTC()
+			times[block] = t
+		}
+		entries[i].Timestamp = t
+	}
+	return nil
+}
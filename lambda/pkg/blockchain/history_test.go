@@ -0,0 +1,123 @@
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestWalletHistoryAcrossLogRanges(t *testing.T) {
+	t.Setenv("HISTORY_LOG_RANGE", "2")
+	chain := newTestChain(t, "TaskEscrow")
+	ctx := context.Background()
+	creator := address(chain.creator)
+
+	chain.fund(t, creator, xzt(100))
+	permit, err := chain.SignPermit(chain.creator, xzt(30), big.NewInt(chain.now(t)+3600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	taskID, _, err := chain.CreateTaskWithPermit(creator.Hex(), xzt(30), nil, permit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.CancelTask(taskID, big.NewInt(0)); err != nil {
+		t.Fatal(err)
+	}
+
+	first, last, ok, err := chain.BlockRange(ctx, time.Time{}, time.Time{})
+	if err != nil || !ok {
+		t.Fatalf("BlockRange = %d, %d, %v, %v", first, last, ok, err)
+	}
+	entries, err := chain.WalletHistory(ctx, creator, first, last)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Newest first: the refund, the lock, then the funding transfer
+	want := []string{HistoryRefund, HistoryTaskLock, HistoryTransferIn}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i, entry := range entries {
+		if entry.Type != want[i] {
+			t.Errorf("entry %d type %s, want %s", i, entry.Type, want[i])
+		}
+		if !entry.Timestamp.IsZero() {
+			t.Errorf("entry %d has a timestamp before FillTimestamps", i)
+		}
+	}
+
+	if err := chain.FillTimestamps(ctx, entries[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if entries[0].Timestamp.IsZero() || !entries[1].Timestamp.IsZero() {
+		t.Fatalf("FillTimestamps should only fill the given entries")
+	}
+
+	// A range starting at the lock's block leaves out the funding transfer
+	lockTime := blockTime(t, chain, entries[1].BlockNumber)
+	first, last, ok, err = chain.BlockRange(ctx, lockTime, time.Time{})
+	if err != nil || !ok {
+		t.Fatalf("BlockRange from lock = %d, %d, %v, %v", first, last, ok, err)
+	}
+	if first != entries[1].BlockNumber {
+		t.Fatalf("first block %d, want %d", first, entries[1].BlockNumber)
+	}
+	entries, err = chain.WalletHistory(ctx, creator, first, last)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Type != HistoryRefund {
+		t.Fatalf("got %+v, want the refund and the lock", entries)
+	}
+
+	// A range after the latest block holds nothing
+	future := time.Unix(chain.now(t)+3600, 0)
+	if _, _, ok, err := chain.BlockRange(ctx, future, time.Time{}); err != nil || ok {
+		t.Fatalf("BlockRange after the head = %v, %v, want no blocks", ok, err)
+	}
+}
+
+func TestWalletHistoryCancelOfEarlierTask(t *testing.T) {
+	chain := newTestChain(t, "TaskEscrow")
+	ctx := context.Background()
+	creator := address(chain.creator)
+
+	chain.fund(t, creator, xzt(100))
+	permit, err := chain.SignPermit(chain.creator, xzt(30), big.NewInt(chain.now(t)+3600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	taskID, _, err := chain.CreateTaskWithPermit(creator.Hex(), xzt(30), nil, permit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := chain.Client.BlockNumber(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.CancelTask(taskID, big.NewInt(0)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The creation is outside the range, so the refund is resolved from getTask
+	entries, err := chain.WalletHistory(ctx, creator, head+1, head+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Type != HistoryRefund {
+		t.Fatalf("got %+v, want one refund", entries)
+	}
+}
+
+func blockTime(t *testing.T, chain *testChain, block uint64) time.Time {
+	t.Helper()
+
+	header, err := chain.Client.HeaderByNumber(context.Background(), new(big.Int).SetUint64(block))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return time.Unix(int64(header.Time), 0)
+}
@@ -0,0 +1,72 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// MaxMilestones matches TaskEscrowV2.MAX_MILESTONES
+const MaxMilestones = 10
+
+// ValidateMilestoneBps checks a schedule the way TaskEscrowV2 does: 1 to
+// MaxMilestones non-zero shares summing to 10000 bps
+func ValidateMilestoneBps(bps []uint16) error {
+	if len(bps) == 0 || len(bps) > MaxMilestones {
+		return fmt.Errorf("milestone schedule needs 1 to %d entries", MaxMilestones)
+	}
+	total := 0
+	for _, share := range bps {
+		if share == 0 {
+			return fmt.Errorf("milestone share must be positive")
+		}
+		total += int(share)
+	}
+	if total != 10000 {
+		return fmt.Errorf("milestone shares must sum to 10000 bps, got %d", total)
+	}
+	return nil
+}
+
+// MilestoneSlice computes a milestone's payment like TaskEscrowV2: every
+// slice is rounded down and the last one takes the remainder
+func MilestoneSlice(total *big.Int, bps []uint16, index int) *big.Int {
+	if index < len(bps)-1 {
+		return ApplyBps(total, int64(bps[index]))
+	}
+	others := new(big.Int)
+	for i := 0; i < len(bps)-1; i++ {
+		others.Add(others, ApplyBps(total, int64(bps[i])))
+	}
+	return others.Sub(total, others)
+}
+
+// MilestoneAmount returns what releasing milestone index pays, from the escrow
+func (c *BlockchainClient) MilestoneAmount(ctx context.Context, taskID uint64, index int) (*big.Int, error) {
+	if c.EscrowV2 == nil {
+		return nil, ErrEscrowV2Required
+	}
+	amount, err := c.EscrowV2.MilestoneAmount(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(taskID), big.NewInt(int64(index)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get milestone amount: %w", err)
+	}
+	return amount, nil
+}
+
+// Milestones returns the task's on-chain schedule and which indexes are released
+func (c *BlockchainClient) Milestones(ctx context.Context, taskID uint64) ([]uint16, []bool, error) {
+	if c.EscrowV2 == nil {
+		return nil, nil, ErrEscrowV2Required
+	}
+	result, err := c.EscrowV2.GetMilestones(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(taskID))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get milestones: %w", err)
+	}
+	released := make([]bool, len(result.MilestoneBps))
+	for i := range released {
+		released[i] = result.ReleasedMask.Bit(i) == 1
+	}
+	return result.MilestoneBps, released, nil
+}
@@ -0,0 +1,140 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/x-zero/xz-wallet/pkg/blockchain/contracts"
+)
+
+// Multicall3 is deployed at the same address on mainnet, Sepolia and most other chains
+var defaultMulticall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+const multicall3ABI = `[
+	{"type":"function","name":"aggregate3","stateMutability":"payable",
+	 "inputs":[{"name":"calls","type":"tuple[]","components":[
+		{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}]}],
+	 "outputs":[{"name":"returnData","type":"tuple[]","components":[
+		{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}]}]},
+	{"type":"function","name":"getEthBalance","stateMutability":"view",
+	 "inputs":[{"name":"addr","type":"address"}],
+	 "outputs":[{"name":"balance","type":"uint256"}]}
+]`
+
+var parsedMulticall3 = mustParseABI(multicall3ABI)
+
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// Call is one read-only contract call in a multicall
+type Call struct {
+	Target       common.Address
+	Data         []byte
+	AllowFailure bool
+}
+
+// CallResult is the outcome of one Call
+type CallResult struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// multicall3Call mirrors the Multicall3.Call3 tuple
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// Multicall3Address returns MULTICALL3_ADDRESS or the canonical deployment
+func Multicall3Address() common.Address {
+	if value := os.Getenv("MULTICALL3_ADDRESS"); value != "" && common.IsHexAddress(value) {
+		return common.HexToAddress(value)
+	}
+	return defaultMulticall3Address
+}
+
+// Multicall runs calls in one eth_call through Multicall3.aggregate3
+func (c *BlockchainClient) Multicall(ctx context.Context, calls []Call) ([]CallResult, error) {
+	if len(calls) == 0 {
+		return nil, nil
+	}
+
+	packed := make([]multicall3Call, len(calls))
+	for i, call := range calls {
+		packed[i] = multicall3Call{Target: call.Target, AllowFailure: call.AllowFailure, CallData: call.Data}
+	}
+
+	input, err := parsedMulticall3.Pack("aggregate3", packed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack multicall: %w", err)
+	}
+
+	target := Multicall3Address()
+	output, err := c.Client.CallContract(ctx, ethereum.CallMsg{To: &target, Data: input}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("multicall failed: %w", err)
+	}
+
+	unpacked, err := parsedMulticall3.Unpack("aggregate3", output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack multicall: %w", err)
+	}
+
+	results := *abi.ConvertType(unpacked[0], new([]CallResult)).(*[]CallResult)
+	return results, nil
+}
+
+// EthBalanceCall reads an address's ETH balance inside a multicall
+func EthBalanceCall(address common.Address) Call {
+	data, _ := parsedMulticall3.Pack("getEthBalance", address)
+	return Call{Target: Multicall3Address(), Data: data}
+}
+
+// TokenCall packs an XZToken view call
+func (c *BlockchainClient) TokenCall(method string, args ...interface{}) (Call, error) {
+	parsed, err := contracts.XZTokenMetaData.GetAbi()
+	if err != nil {
+		return Call{}, err
+	}
+	data, err := parsed.Pack(method, args...)
+	if err != nil {
+		return Call{}, fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+	return Call{Target: c.TokenAddress, Data: data}, nil
+}
+
+// EscrowCall packs a TaskEscrow view call
+func (c *BlockchainClient) EscrowCall(method string, args ...interface{}) (Call, error) {
+	parsed, err := contracts.TaskEscrowMetaData.GetAbi()
+	if err != nil {
+		return Call{}, err
+	}
+	data, err := parsed.Pack(method, args...)
+	if err != nil {
+		return Call{}, fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+	return Call{Target: c.EscrowAddress, Data: data}, nil
+}
+
+// UnpackBigInt decodes a single uint256 return value
+func UnpackBigInt(result CallResult) (*big.Int, error) {
+	if !result.Success {
+		return nil, fmt.Errorf("call reverted")
+	}
+	if len(result.ReturnData) < 32 {
+		return nil, fmt.Errorf("short return data")
+	}
+	return new(big.Int).SetBytes(result.ReturnData[:32]), nil
+}
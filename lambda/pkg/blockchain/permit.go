@@ -0,0 +1,117 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// permitTypeHash is keccak256("Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)")
+var permitTypeHash = crypto.Keccak256Hash([]byte("Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)"))
+
+// PermitSignature is an EIP-2612 permit signed by the token owner
+type PermitSignature struct {
+	Deadline *big.Int
+	V        uint8
+	R        [32]byte
+	S        [32]byte
+}
+
+// ParsePermit builds a PermitSignature from API request fields
+func ParsePermit(deadline int64, v uint8, r, s string) (*PermitSignature, error) {
+	rBytes, err := hexutil.Decode(r)
+	if err != nil || len(rBytes) != 32 {
+		return nil, fmt.Errorf("invalid permit r")
+	}
+	sBytes, err := hexutil.Decode(s)
+	if err != nil || len(sBytes) != 32 {
+		return nil, fmt.Errorf("invalid permit s")
+	}
+	if v < 27 {
+		v += 27 // Some wallets return 0/1
+	}
+
+	permit := &PermitSignature{
+		Deadline: big.NewInt(deadline),
+		V:        v,
+	}
+	copy(permit.R[:], rBytes)
+	copy(permit.S[:], sBytes)
+	return permit, nil
+}
+
+// PermitDigest returns the EIP-712 digest an owner signs to let spender pull value
+func (c *BlockchainClient) PermitDigest(owner, spender common.Address, value, deadline *big.Int) (common.Hash, error) {
+	nonce, err := c.Token.Nonces(&bind.CallOpts{}, owner)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get permit nonce: %w", err)
+	}
+
+	domainSeparator, err := c.Token.DOMAINSEPARATOR(&bind.CallOpts{})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get domain separator: %w", err)
+	}
+
+	structHash := crypto.Keccak256Hash(
+		permitTypeHash.Bytes(),
+		common.LeftPadBytes(owner.Bytes(), 32),
+		common.LeftPadBytes(spender.Bytes(), 32),
+		common.LeftPadBytes(value.Bytes(), 32),
+		common.LeftPadBytes(nonce.Bytes(), 32),
+		common.LeftPadBytes(deadline.Bytes(), 32),
+	)
+
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domainSeparator[:], structHash.Bytes()), nil
+}
+
+// SignPermit signs a permit for the escrow contract with the owner's key (scripts and tests)
+func (c *BlockchainClient) SignPermit(privateKey *ecdsa.PrivateKey, value, deadline *big.Int) (*PermitSignature, error) {
+	owner := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	digest, err := c.PermitDigest(owner, c.EscrowAddress, value, deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := crypto.Sign(digest.Bytes(), privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign permit: %w", err)
+	}
+
+	permit := &PermitSignature{
+		Deadline: deadline,
+		V:        sig[64] + 27,
+	}
+	copy(permit.R[:], sig[:32])
+	copy(permit.S[:], sig[32:64])
+	return permit, nil
+}
+
+// SubmitPermit relays the owner's permit for the escrow contract from the admin wallet
+func (c *BlockchainClient) SubmitPermit(owner common.Address, value *big.Int, permit *PermitSignature) (string, error) {
+	return c.submitPermit(owner, c.EscrowAddress, value, permit)
+}
+
+func (c *BlockchainClient) submitPermit(owner, spender common.Address, value *big.Int, permit *PermitSignature) (string, error) {
+	tx, err := c.Token.Permit(c.AdminAuth, owner, spender, value, permit.Deadline, permit.V, permit.R, permit.S)
+	if err != nil {
+		return "", fmt.Errorf("failed to submit permit: %w", err)
+	}
+
+	receipt, err := c.waitMined(context.Background(), tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for transaction: %w", err)
+	}
+
+	if receipt.Status == 0 {
+		return "", fmt.Errorf("transaction failed")
+	}
+
+	return tx.Hash().Hex(), nil
+}
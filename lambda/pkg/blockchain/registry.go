@@ -0,0 +1,198 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ChainConfig describes one supported chain and its contracts
+type ChainConfig struct {
+	ChainID       int64         `json:"chain_id"`
+	Name          string        `json:"name"`
+	RPCURLs       []RPCEndpoint `json:"rpc_urls"`
+	TokenAddress  string        `json:"token_address"`
+	EscrowAddress string        `json:"escrow_address"`          // Escrow for new tasks
+	LegacyEscrows []string      `json:"legacy_escrow_addresses"` // Older escrows whose tasks still settle
+	Confirmations uint64        `json:"confirmations"`           // Blocks to wait after inclusion (0 or 1 = mined)
+	ExplorerURL   string        `json:"explorer_url"`
+	StartBlock    uint64        `json:"start_block"` // First block scanned for history
+}
+
+// Registry is the set of chains the backend talks to
+type Registry struct {
+	DefaultChainID int64          `json:"default_chain_id"`
+	Chains         []*ChainConfig `json:"chains"`
+}
+
+var (
+	registry     *Registry
+	registryErr  error
+	registryOnce sync.Once
+)
+
+// LoadRegistry reads CHAIN_REGISTRY (JSON) or CHAIN_REGISTRY_FILE. Without
+// either it builds a single-chain registry from CHAIN_ID, RPC_URLS /
+// SEPOLIA_RPC_URL, XZT_TOKEN_ADDRESS, TASK_ESCROW_ADDRESS and HISTORY_START_BLOCK.
+func LoadRegistry() (*Registry, error) {
+	registryOnce.Do(func() {
+		registry, registryErr = loadRegistry()
+	})
+	return registry, registryErr
+}
+
+func loadRegistry() (*Registry, error) {
+	data := []byte(os.Getenv("CHAIN_REGISTRY"))
+	if len(data) == 0 {
+		if path := os.Getenv("CHAIN_REGISTRY_FILE"); path != "" {
+			var err error
+			data, err = os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read chain registry: %w", err)
+			}
+		}
+	}
+	if len(data) == 0 {
+		return registryFromEnv()
+	}
+
+	var r Registry
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("invalid chain registry: %w", err)
+	}
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func registryFromEnv() (*Registry, error) {
+	endpoints, err := RPCEndpointsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	chainID := int64(11155111)
+	if value := os.Getenv("CHAIN_ID"); value != "" {
+		chainID, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CHAIN_ID: %s", value)
+		}
+	}
+
+	r := &Registry{
+		DefaultChainID: chainID,
+		Chains: []*ChainConfig{{
+			ChainID:       chainID,
+			Name:          "sepolia",
+			RPCURLs:       endpoints,
+			TokenAddress:  os.Getenv("XZT_TOKEN_ADDRESS"),
+			EscrowAddress: os.Getenv("TASK_ESCROW_ADDRESS"),
+			Confirmations: 1,
+			ExplorerURL:   "https://sepolia.etherscan.io",
+			StartBlock:    historyStartBlock(),
+		}},
+	}
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Registry) validate() error {
+	if len(r.Chains) == 0 {
+		return fmt.Errorf("chain registry has no chains")
+	}
+	for _, chain := range r.Chains {
+		if len(chain.RPCURLs) == 0 {
+			return fmt.Errorf("chain %d has no RPC URLs", chain.ChainID)
+		}
+		if !common.IsHexAddress(chain.TokenAddress) {
+			return fmt.Errorf("chain %d: invalid token address", chain.ChainID)
+		}
+		if !common.IsHexAddress(chain.EscrowAddress) {
+			return fmt.Errorf("chain %d: invalid escrow address", chain.ChainID)
+		}
+		for _, legacy := range chain.LegacyEscrows {
+			if !common.IsHexAddress(legacy) {
+				return fmt.Errorf("chain %d: invalid legacy escrow address %s", chain.ChainID, legacy)
+			}
+		}
+	}
+	if r.DefaultChainID == 0 {
+		r.DefaultChainID = r.Chains[0].ChainID
+	}
+	if r.Chain(r.DefaultChainID) == nil {
+		return fmt.Errorf("default chain %d not in registry", r.DefaultChainID)
+	}
+	return nil
+}
+
+// Chain returns the config for chainID, or nil
+func (r *Registry) Chain(chainID int64) *ChainConfig {
+	for _, chain := range r.Chains {
+		if chain.ChainID == chainID {
+			return chain
+		}
+	}
+	return nil
+}
+
+// Escrows returns the current escrow followed by the legacy ones
+func (c *ChainConfig) Escrows() []common.Address {
+	escrows := []common.Address{common.HexToAddress(c.EscrowAddress)}
+	for _, legacy := range c.LegacyEscrows {
+		escrows = append(escrows, common.HexToAddress(legacy))
+	}
+	return escrows
+}
+
+// HasEscrow reports whether escrow is the chain's current or a legacy escrow
+func (c *ChainConfig) HasEscrow(escrow common.Address) bool {
+	for _, e := range c.Escrows() {
+		if e == escrow {
+			return true
+		}
+	}
+	return false
+}
+
+// TxURL links a transaction on the chain's block explorer
+func (c *ChainConfig) TxURL(txHash string) string {
+	if c.ExplorerURL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(c.ExplorerURL, "/") + "/tx/" + txHash
+}
+
+// waitMined waits for the transaction and then for the chain's confirmation depth
+func (c *BlockchainClient) waitMined(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	receipt, err := bind.WaitMined(ctx, c.Client, tx)
+	if err != nil || c.Chain == nil || c.Chain.Confirmations <= 1 || receipt.Status == 0 {
+		return receipt, err
+	}
+
+	target := receipt.BlockNumber.Uint64() + c.Chain.Confirmations - 1
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		head, err := c.Client.BlockNumber(ctx)
+		if err == nil && head >= target {
+			return receipt, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,416 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RPCEndpoint is one JSON-RPC provider. Higher weights get more traffic.
+type RPCEndpoint struct {
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+// ParseRPCEndpoints parses "url|weight,url|weight". The weight is optional (default 1).
+func ParseRPCEndpoints(value string) ([]RPCEndpoint, error) {
+	var endpoints []RPCEndpoint
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		rawURL, weightStr, hasWeight := strings.Cut(part, "|")
+		weight := 1
+		if hasWeight {
+			w, err := strconv.Atoi(weightStr)
+			if err != nil || w <= 0 {
+				return nil, fmt.Errorf("invalid weight for %s", rawURL)
+			}
+			weight = w
+		}
+		if _, err := url.ParseRequestURI(rawURL); err != nil {
+			return nil, fmt.Errorf("invalid RPC URL %s: %w", rawURL, err)
+		}
+		endpoints = append(endpoints, RPCEndpoint{URL: rawURL, Weight: weight})
+	}
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no RPC endpoints configured")
+	}
+	return endpoints, nil
+}
+
+// RPCEndpointsFromEnv reads RPC_URLS, falling back to SEPOLIA_RPC_URL
+func RPCEndpointsFromEnv() ([]RPCEndpoint, error) {
+	if value := os.Getenv("RPC_URLS"); value != "" {
+		return ParseRPCEndpoints(value)
+	}
+	if value := os.Getenv("SEPOLIA_RPC_URL"); value != "" {
+		return ParseRPCEndpoints(value)
+	}
+	return nil, fmt.Errorf("RPC_URLS or SEPOLIA_RPC_URL not set")
+}
+
+type endpointState struct {
+	url       *url.URL
+	weight    int
+	failures  int
+	downUntil time.Time
+}
+
+// FailoverTransport is an http.RoundTripper that spreads JSON-RPC requests
+// over several endpoints by weight, takes failing endpoints out of rotation
+// for a cooldown, and retries retryable errors (network errors, timeouts,
+// 429 and 5xx) on another endpoint with exponential backoff. Requests that
+// submit a transaction are sent once and never retried.
+type FailoverTransport struct {
+	Base             http.RoundTripper
+	MaxRetries       int
+	BackoffBase      time.Duration
+	BackoffMax       time.Duration
+	AttemptTimeout   time.Duration
+	FailureThreshold int           // Consecutive failures before an endpoint is marked down
+	Cooldown         time.Duration // How long a down endpoint is skipped
+
+	mu        sync.Mutex
+	endpoints []*endpointState
+	rand      *rand.Rand
+}
+
+// NewFailoverTransport creates a transport with settings from RPC_MAX_RETRIES (3),
+// RPC_BACKOFF_BASE (200ms), RPC_BACKOFF_MAX (5s), RPC_ATTEMPT_TIMEOUT (10s) and RPC_COOLDOWN (30s)
+func NewFailoverTransport(endpoints []RPCEndpoint) (*FailoverTransport, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no RPC endpoints configured")
+	}
+
+	t := &FailoverTransport{
+		Base:             http.DefaultTransport,
+		MaxRetries:       envInt("RPC_MAX_RETRIES", 3),
+		BackoffBase:      envDuration("RPC_BACKOFF_BASE", 200*time.Millisecond),
+		BackoffMax:       envDuration("RPC_BACKOFF_MAX", 5*time.Second),
+		AttemptTimeout:   envDuration("RPC_ATTEMPT_TIMEOUT", 10*time.Second),
+		FailureThreshold: 2,
+		Cooldown:         envDuration("RPC_COOLDOWN", 30*time.Second),
+		rand:             rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, e := range endpoints {
+		u, err := url.Parse(e.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RPC URL %s: %w", e.URL, err)
+		}
+		weight := e.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		t.endpoints = append(t.endpoints, &endpointState{url: u, weight: weight})
+	}
+	return t, nil
+}
+
+// RoundTrip sends the request to a healthy endpoint, failing over on retryable errors
+func (t *FailoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// A lost response does not mean the node missed the transaction. A resend
+	// would come back "already known" or "nonce too low", and the caller would
+	// report a failure for a transaction that still gets mined.
+	maxRetries := t.MaxRetries
+	if sendsTransaction(body) {
+		maxRetries = 0
+	}
+
+	ctx := req.Context()
+	tried := make(map[int]bool)
+	var lastErr error
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleepContext(ctx, t.backoff(attempt)); err != nil {
+				return nil, err
+			}
+		}
+
+		idx := t.pick(tried)
+		tried[idx] = true
+
+		resp, err := t.send(req, body, idx)
+		if err == nil && !retryableStatus(resp.StatusCode) {
+			t.markSuccess(idx)
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			if resp != nil {
+				resp.Body.Close()
+			}
+			return nil, ctx.Err()
+		}
+
+		t.markFailure(idx)
+		if err != nil {
+			lastErr = fmt.Errorf("rpc endpoint %s: %w", t.endpoints[idx].url.Host, err)
+			continue
+		}
+
+		// Last attempt: hand the error response to the RPC client
+		if attempt == maxRetries {
+			return resp, nil
+		}
+		lastErr = fmt.Errorf("rpc endpoint %s: status %d", t.endpoints[idx].url.Host, resp.StatusCode)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	return nil, lastErr
+}
+
+// send performs one attempt against endpoint idx with a per-attempt timeout
+func (t *FailoverTransport) send(req *http.Request, body []byte, idx int) (*http.Response, error) {
+	ctx := req.Context()
+	var cancel context.CancelFunc
+	if t.AttemptTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.AttemptTimeout)
+	}
+
+	target := *t.endpoints[idx].url
+	attempt := req.Clone(ctx)
+	attempt.URL = &target
+	attempt.Host = ""
+	attempt.Body = io.NopCloser(bytes.NewReader(body))
+	attempt.ContentLength = int64(len(body))
+
+	resp, err := t.Base.RoundTrip(attempt)
+	if err != nil {
+		if cancel != nil {
+			cancel()
+		}
+		return nil, err
+	}
+	if cancel != nil {
+		resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	}
+	return resp, nil
+}
+
+// pick chooses an untried endpoint by weight, preferring healthy ones
+func (t *FailoverTransport) pick(tried map[int]bool) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	var healthy, untried []int
+	for i, e := range t.endpoints {
+		if tried[i] {
+			continue
+		}
+		untried = append(untried, i)
+		if now.After(e.downUntil) {
+			healthy = append(healthy, i)
+		}
+	}
+
+	candidates := healthy
+	if len(candidates) == 0 {
+		candidates = untried
+	}
+	if len(candidates) == 0 {
+		// Every endpoint failed this request; start over
+		for k := range tried {
+			delete(tried, k)
+		}
+		for i := range t.endpoints {
+			candidates = append(candidates, i)
+		}
+	}
+
+	total := 0
+	for _, i := range candidates {
+		total += t.endpoints[i].weight
+	}
+	n := t.rand.Intn(total)
+	for _, i := range candidates {
+		n -= t.endpoints[i].weight
+		if n < 0 {
+			return i
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+func (t *FailoverTransport) markSuccess(idx int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.endpoints[idx].failures = 0
+	t.endpoints[idx].downUntil = time.Time{}
+}
+
+func (t *FailoverTransport) markFailure(idx int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := t.endpoints[idx]
+	e.failures++
+	if e.failures >= t.FailureThreshold {
+		e.downUntil = time.Now().Add(t.Cooldown)
+	}
+}
+
+// backoff returns the delay before retry attempt n: exponential with full jitter
+func (t *FailoverTransport) backoff(n int) time.Duration {
+	d := t.BackoffBase << (n - 1)
+	if d <= 0 || d > t.BackoffMax {
+		d = t.BackoffMax
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return time.Duration(t.rand.Int63n(int64(d) + 1))
+}
+
+// CheckHealth probes every endpoint with eth_chainId and updates its status.
+// Returns the number of healthy endpoints.
+func (t *FailoverTransport) CheckHealth(ctx context.Context) int {
+	payload := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`)
+
+	var wg sync.WaitGroup
+	healthy := make([]bool, len(t.endpoints))
+	for i := range t.endpoints {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req, err := http.NewRequestWithContext(ctx, "POST", t.endpoints[i].url.String(), bytes.NewReader(payload))
+			if err != nil {
+				return
+			}
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := t.send(req, payload, i)
+			if err != nil {
+				return
+			}
+			defer resp.Body.Close()
+			io.Copy(io.Discard, resp.Body)
+			healthy[i] = resp.StatusCode == http.StatusOK
+		}(i)
+	}
+	wg.Wait()
+
+	count := 0
+	for i, ok := range healthy {
+		if ok {
+			t.markSuccess(i)
+			count++
+		} else {
+			t.mu.Lock()
+			t.endpoints[i].failures = t.FailureThreshold
+			t.endpoints[i].downUntil = time.Now().Add(t.Cooldown)
+			t.mu.Unlock()
+		}
+	}
+	return count
+}
+
+// DialFailover connects an ethclient through a FailoverTransport
+func DialFailover(ctx context.Context, endpoints []RPCEndpoint) (*ethclient.Client, *FailoverTransport, error) {
+	transport, err := NewFailoverTransport(endpoints)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The URL only selects the HTTP transport; the failover transport picks the endpoint
+	rpcClient, err := rpc.DialOptions(ctx, endpoints[0].URL, rpc.WithHTTPClient(&http.Client{Transport: transport}))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create RPC client: %w", err)
+	}
+	return ethclient.NewClient(rpcClient), transport, nil
+}
+
+// sendsTransaction reports whether a JSON-RPC request, single or batch,
+// submits a transaction
+func sendsTransaction(body []byte) bool {
+	type call struct {
+		Method string `json:"method"`
+	}
+	var calls []call
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		if err := json.Unmarshal(body, &calls); err != nil {
+			return false
+		}
+	} else {
+		var single call
+		if err := json.Unmarshal(body, &single); err != nil {
+			return false
+		}
+		calls = append(calls, single)
+	}
+	for _, c := range calls {
+		if c.Method == "eth_sendRawTransaction" || c.Method == "eth_sendTransaction" {
+			return true
+		}
+	}
+	return false
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusRequestTimeout || code >= 500
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// cancelOnClose releases the attempt's timeout once the body is consumed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+func envInt(name string, def int) int {
+	if value := os.Getenv(name); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return def
+}
+
+func envDuration(name string, def time.Duration) time.Duration {
+	if value := os.Getenv(name); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			return d
+		}
+	}
+	return def
+}
@@ -0,0 +1,200 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// rpcStub is a local JSON-RPC endpoint that answers eth_chainId and
+// eth_sendRawTransaction, after failing as told
+type rpcStub struct {
+	*httptest.Server
+	calls atomic.Int32
+	fault func(n int32, w http.ResponseWriter) bool // Returns true when it wrote a failure
+}
+
+func newRPCStub(t *testing.T, fault func(n int32, w http.ResponseWriter) bool) *rpcStub {
+	t.Helper()
+
+	stub := &rpcStub{fault: fault}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := stub.calls.Add(1)
+		if stub.fault != nil && stub.fault(n, w) {
+			return
+		}
+
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		result := `"0x539"`
+		if req.Method == "eth_sendRawTransaction" {
+			result = `"0x` + strings.Repeat("ab", 32) + `"`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"jsonrpc":"2.0","id":`+string(req.ID)+`,"result":`+result+`}`)
+	}))
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+// failWith answers every request with status
+func failWith(status int) func(int32, http.ResponseWriter) bool {
+	return func(_ int32, w http.ResponseWriter) bool {
+		w.WriteHeader(status)
+		return true
+	}
+}
+
+// stall holds every request past the attempt timeout
+func stall(_ int32, w http.ResponseWriter) bool {
+	time.Sleep(300 * time.Millisecond)
+	return false
+}
+
+func dialStubs(t *testing.T, stubs ...*rpcStub) (*ethclient.Client, *FailoverTransport) {
+	t.Helper()
+
+	t.Setenv("RPC_BACKOFF_BASE", "1ms")
+	t.Setenv("RPC_BACKOFF_MAX", "5ms")
+	t.Setenv("RPC_ATTEMPT_TIMEOUT", "100ms")
+	var endpoints []RPCEndpoint
+	for _, stub := range stubs {
+		endpoints = append(endpoints, RPCEndpoint{URL: stub.URL, Weight: 1})
+	}
+	client, transport, err := DialFailover(context.Background(), endpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	return client, transport
+}
+
+func signedTx(t *testing.T) *types.Transaction {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	tx, err := types.SignNewTx(key, types.NewEIP155Signer(big.NewInt(1337)), &types.LegacyTx{
+		To: &to, Gas: 21000, GasPrice: big.NewInt(1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestFailoverOnRetryableErrors(t *testing.T) {
+	faults := map[string]func(int32, http.ResponseWriter) bool{
+		"503":     failWith(http.StatusServiceUnavailable),
+		"429":     failWith(http.StatusTooManyRequests),
+		"timeout": stall,
+	}
+	for name, fault := range faults {
+		t.Run(name, func(t *testing.T) {
+			bad := newRPCStub(t, fault)
+			good := newRPCStub(t, nil)
+			client, _ := dialStubs(t, bad, good)
+
+			// Whichever endpoint is picked first, the call ends on the good one
+			for i := 0; i < 5; i++ {
+				chainID, err := client.ChainID(context.Background())
+				if err != nil {
+					t.Fatalf("ChainID: %v", err)
+				}
+				if chainID.Int64() != 1337 {
+					t.Fatalf("chain ID %s, want 1337", chainID)
+				}
+			}
+			if good.calls.Load() != 5 {
+				t.Fatalf("good endpoint served %d calls, want 5", good.calls.Load())
+			}
+		})
+	}
+}
+
+func TestFailoverMarksEndpointDown(t *testing.T) {
+	bad := newRPCStub(t, failWith(http.StatusBadGateway))
+	good := newRPCStub(t, nil)
+	client, transport := dialStubs(t, bad, good)
+
+	for i := 0; i < 20; i++ {
+		if _, err := client.ChainID(context.Background()); err != nil {
+			t.Fatalf("ChainID: %v", err)
+		}
+	}
+	// FailureThreshold failures take it out of rotation for the cooldown
+	if got := bad.calls.Load(); got != int32(transport.FailureThreshold) {
+		t.Fatalf("down endpoint got %d calls, want %d", got, transport.FailureThreshold)
+	}
+}
+
+func TestSendTransactionIsNotRetried(t *testing.T) {
+	faults := map[string]func(int32, http.ResponseWriter) bool{
+		"503":     failWith(http.StatusServiceUnavailable),
+		"429":     failWith(http.StatusTooManyRequests),
+		"timeout": stall,
+	}
+	for name, fault := range faults {
+		t.Run(name, func(t *testing.T) {
+			first := newRPCStub(t, fault)
+			second := newRPCStub(t, fault)
+			client, _ := dialStubs(t, first, second)
+
+			if err := client.SendTransaction(context.Background(), signedTx(t)); err == nil {
+				t.Fatal("SendTransaction succeeded against failing endpoints")
+			}
+			if calls := first.calls.Load() + second.calls.Load(); calls != 1 {
+				t.Fatalf("transaction sent %d times, want 1", calls)
+			}
+		})
+	}
+}
+
+func TestSendTransactionSucceeds(t *testing.T) {
+	stub := newRPCStub(t, nil)
+	client, _ := dialStubs(t, stub)
+
+	if err := client.SendTransaction(context.Background(), signedTx(t)); err != nil {
+		t.Fatalf("SendTransaction: %v", err)
+	}
+	if stub.calls.Load() != 1 {
+		t.Fatalf("transaction sent %d times, want 1", stub.calls.Load())
+	}
+}
+
+func TestSendsTransaction(t *testing.T) {
+	cases := map[string]bool{
+		`{"jsonrpc":"2.0","id":1,"method":"eth_sendRawTransaction","params":["0x00"]}`:                   true,
+		`[{"id":1,"method":"eth_chainId"},{"id":2,"method":"eth_sendRawTransaction","params":["0x00"]}]`: true,
+		`{"jsonrpc":"2.0","id":1,"method":"eth_call","params":[]}`:                                       false,
+		`[{"id":1,"method":"eth_call"},{"id":2,"method":"eth_getBalance"}]`:                              false,
+		`not json`: false,
+	}
+	for body, want := range cases {
+		if got := sendsTransaction([]byte(body)); got != want {
+			t.Errorf("sendsTransaction(%s) = %v, want %v", body, got, want)
+		}
+	}
+}
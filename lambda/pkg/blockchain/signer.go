@@ -0,0 +1,117 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrNoSigner is returned when the backend holds no key for a wallet
+var ErrNoSigner = errors.New("no signer available for this wallet")
+
+// Signer signs transactions for one wallet
+type Signer interface {
+	Address() common.Address
+	TransactOpts(ctx context.Context) (*bind.TransactOpts, error)
+}
+
+// SignerProvider finds the signer for a user's wallet
+type SignerProvider interface {
+	SignerFor(ctx context.Context, did string, address common.Address) (Signer, error)
+}
+
+// KeySigner signs with a private key held by the backend
+type KeySigner struct {
+	key     *ecdsa.PrivateKey
+	chainID *big.Int
+}
+
+// NewKeySigner creates a signer from a hex private key (0x prefix optional)
+func NewKeySigner(privateKeyHex string, chainID *big.Int) (*KeySigner, error) {
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	return &KeySigner{key: privateKey, chainID: chainID}, nil
+}
+
+// Address returns the signer's wallet address
+func (s *KeySigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+// TransactOpts returns fresh transact options for the key
+func (s *KeySigner) TransactOpts(ctx context.Context) (*bind.TransactOpts, error) {
+	auth, err := bind.NewKeyedTransactorWithChainID(s.key, s.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transactor: %w", err)
+	}
+	auth.Context = ctx
+	auth.GasLimit = 100000
+	return auth, nil
+}
+
+// StaticSignerProvider serves the fixed set of wallets whose keys the backend holds
+type StaticSignerProvider struct {
+	signers map[common.Address]Signer
+}
+
+// NewStaticSignerProvider indexes signers by address
+func NewStaticSignerProvider(signers ...Signer) *StaticSignerProvider {
+	p := &StaticSignerProvider{signers: make(map[common.Address]Signer)}
+	for _, s := range signers {
+		p.signers[s.Address()] = s
+	}
+	return p
+}
+
+// SignerFor returns the signer for address, or ErrNoSigner
+func (p *StaticSignerProvider) SignerFor(ctx context.Context, did string, address common.Address) (Signer, error) {
+	if s, ok := p.signers[address]; ok {
+		return s, nil
+	}
+	return nil, ErrNoSigner
+}
+
+// NewSignerProviderFromEnv registers the custodial user wallets listed in
+// TRANSFER_SIGNER_KEYS (comma-separated private keys). The admin and system
+// wallets are never user signers: a user whose address matched one could
+// spend its funds, so their keys are refused.
+func (c *BlockchainClient) NewSignerProviderFromEnv() (SignerProvider, error) {
+	reserved := map[common.Address]string{c.AdminAuth.From: "admin wallet"}
+	for name, wallet := range map[string]string{"ADMIN_WALLET_PRIVATE_KEY": "admin wallet", "SYSTEM_WALLET_PRIVATE_KEY": "system wallet"} {
+		if value := os.Getenv(name); value != "" {
+			if signer, err := NewKeySigner(value, c.ChainID); err == nil {
+				reserved[signer.Address()] = wallet
+			}
+		}
+	}
+	if value := os.Getenv("SYSTEM_WALLET_ADDRESS"); common.IsHexAddress(value) {
+		reserved[common.HexToAddress(value)] = "system wallet"
+	}
+
+	var signers []Signer
+	for i, value := range strings.Split(os.Getenv("TRANSFER_SIGNER_KEYS"), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		signer, err := NewKeySigner(value, c.ChainID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TRANSFER_SIGNER_KEYS entry %d: %w", i, err)
+		}
+		if name, ok := reserved[signer.Address()]; ok {
+			return nil, fmt.Errorf("TRANSFER_SIGNER_KEYS entry %d is the %s", i, name)
+		}
+		signers = append(signers, signer)
+	}
+	return NewStaticSignerProvider(signers...), nil
+}
@@ -0,0 +1,56 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSignerProviderExcludesAdminAndSystemWallets(t *testing.T) {
+	keyHex := func() string {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hexutil.Encode(crypto.FromECDSA(key))
+	}
+	adminKey, systemKey, userKey := keyHex(), keyHex(), keyHex()
+
+	admin, err := NewKeySigner(adminKey, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &BlockchainClient{ChainID: big.NewInt(1), AdminAuth: &bind.TransactOpts{From: admin.Address()}}
+	t.Setenv("ADMIN_WALLET_PRIVATE_KEY", adminKey)
+	t.Setenv("SYSTEM_WALLET_PRIVATE_KEY", systemKey)
+	t.Setenv("SYSTEM_WALLET_ADDRESS", "")
+
+	t.Setenv("TRANSFER_SIGNER_KEYS", userKey)
+	provider, err := client.NewSignerProviderFromEnv()
+	if err != nil {
+		t.Fatalf("NewSignerProviderFromEnv: %v", err)
+	}
+	user, _ := NewKeySigner(userKey, big.NewInt(1))
+	if _, err := provider.SignerFor(context.Background(), "did:example:user", user.Address()); err != nil {
+		t.Fatalf("user wallet: %v", err)
+	}
+	system, _ := NewKeySigner(systemKey, big.NewInt(1))
+	for name, signer := range map[string]*KeySigner{"admin": admin, "system": system} {
+		if _, err := provider.SignerFor(context.Background(), "did:example:user", signer.Address()); !errors.Is(err, ErrNoSigner) {
+			t.Errorf("%s wallet: err = %v, want ErrNoSigner", name, err)
+		}
+	}
+
+	for name, key := range map[string]string{"admin": adminKey, "system": systemKey} {
+		t.Setenv("TRANSFER_SIGNER_KEYS", userKey+","+key)
+		if _, err := client.NewSignerProviderFromEnv(); err == nil || !strings.Contains(err.Error(), name+" wallet") {
+			t.Errorf("%s key as user signer: err = %v, want refusal", name, err)
+		}
+	}
+}
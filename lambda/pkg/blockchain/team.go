@@ -0,0 +1,109 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// MaxTeamSize matches TaskEscrowV2.MAX_TEAM_SIZE
+const MaxTeamSize = 10
+
+// ValidateTeamShares checks a team split the way TaskEscrowV2 does: 2 to
+// MaxTeamSize non-zero shares summing to 10000 bps
+func ValidateTeamShares(bps []uint16) error {
+	if len(bps) < 2 || len(bps) > MaxTeamSize {
+		return fmt.Errorf("team needs 2 to %d members", MaxTeamSize)
+	}
+	total := 0
+	for _, share := range bps {
+		if share == 0 {
+			return fmt.Errorf("member share must be positive")
+		}
+		total += int(share)
+	}
+	if total != 10000 {
+		return fmt.Errorf("member shares must sum to 10000 bps, got %d", total)
+	}
+	return nil
+}
+
+// TeamSplit divides an executor payment among the team like TaskEscrowV2,
+// which rounds the same way as milestone slices
+func TeamSplit(amount *big.Int, bps []uint16) []*big.Int {
+	shares := make([]*big.Int, len(bps))
+	for i := range bps {
+		shares[i] = MilestoneSlice(amount, bps, i)
+	}
+	return shares
+}
+
+// teamArguments encodes a team as TaskEscrowV2.teamHash does
+var teamArguments = abi.Arguments{
+	{Type: mustType("address[]")},
+	{Type: mustType("uint16[]")},
+}
+
+func mustType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// TeamHash is keccak256(abi.encode(members, shareBps)) as signed in v2
+// releases and cancels, or zero without a team
+func TeamHash(members []common.Address, shareBps []uint16) (common.Hash, error) {
+	if len(members) == 0 {
+		return common.Hash{}, nil
+	}
+	encoded, err := teamArguments.Pack(members, shareBps)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to encode team: %w", err)
+	}
+	return crypto.Keccak256Hash(encoded), nil
+}
+
+// taskTeamHash reads the task's team from the escrow and hashes it
+func (c *BlockchainClient) taskTeamHash(ctx context.Context, taskID uint64) (common.Hash, error) {
+	team, err := c.EscrowV2.GetTeam(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(taskID))
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get team: %w", err)
+	}
+	return TeamHash(team.Members, team.ShareBps)
+}
+
+// SetExecutorTeam sets the team that splits every executor payment of a task
+// without an executor; the first member becomes the executor (v2 escrow)
+func (c *BlockchainClient) SetExecutorTeam(taskID uint64, memberAddresses []string, shareBps []uint16) (string, error) {
+	if c.EscrowV2 == nil {
+		return "", ErrEscrowV2Required
+	}
+	members := make([]common.Address, len(memberAddresses))
+	for i, address := range memberAddresses {
+		members[i] = common.HexToAddress(address)
+	}
+	taskIDBig := big.NewInt(int64(taskID))
+
+	tx, err := c.EscrowV2.SetExecutorTeam(c.AdminAuth, taskIDBig, members, shareBps)
+	if err != nil {
+		return "", fmt.Errorf("failed to set executor team: %w", err)
+	}
+
+	receipt, err := c.waitMined(context.Background(), tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for transaction: %w", err)
+	}
+
+	if receipt.Status == 0 {
+		return "", fmt.Errorf("transaction failed")
+	}
+
+	return tx.Hash().Hex(), nil
+}
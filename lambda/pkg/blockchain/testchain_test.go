@@ -0,0 +1,219 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/x-zero/xz-wallet/pkg/blockchain/contracts"
+)
+
+// artifactsDir holds the Hardhat build output (cd contracts && npx hardhat compile).
+// The simulated chain predates Shanghai, hence evmVersion paris in hardhat.config.js.
+const artifactsDir = "../../../contracts/artifacts/contracts"
+
+// testChain is a simulated chain with XZToken and an escrow deployed, plus
+// an admin holding the supply and a creator and executor with gas money
+type testChain struct {
+	*BlockchainClient
+	admin    *ecdsa.PrivateKey
+	creator  *ecdsa.PrivateKey
+	executor *ecdsa.PrivateKey
+}
+
+// newTestChain deploys XZToken and the named escrow ("TaskEscrow" or
+// "TaskEscrowV2"). It skips the test when the contracts are not compiled.
+func newTestChain(t *testing.T, escrowName string) *testChain {
+	t.Helper()
+
+	tokenABI, tokenCode := loadArtifact(t, "XZToken")
+	escrowABI, escrowCode := loadArtifact(t, escrowName)
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	alloc := core.GenesisAlloc{}
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = key
+		alloc[crypto.PubkeyToAddress(key.PublicKey)] = core.GenesisAccount{Balance: big.NewInt(params.Ether)}
+	}
+
+	sim := backends.NewSimulatedBackend(alloc, 30_000_000)
+	t.Cleanup(func() { sim.Close() })
+	client := simChain{sim}
+	ctx := context.Background()
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deployer, err := bind.NewKeyedTransactorWithChainID(keys[0], chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenAddress := deploy(t, client, deployer, tokenABI, tokenCode)
+	escrowArgs := []interface{}{tokenAddress}
+	if escrowName == "TaskEscrowV2" {
+		escrowArgs = append(escrowArgs, xzt(1000)) // Emergency withdrawal limit per period
+	}
+	escrowAddress := deploy(t, client, deployer, escrowABI, escrowCode, escrowArgs...)
+
+	token, err := contracts.NewXZToken(tokenAddress, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	escrow, err := contracts.NewTaskEscrow(escrowAddress, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	escrowV2, err := detectEscrowV2(ctx, escrowAddress, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adminAuth, err := bind.NewKeyedTransactorWithChainID(keys[0], chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	adminAuth.GasLimit = 500000 // As in newClient
+
+	return &testChain{
+		BlockchainClient: &BlockchainClient{
+			Client:        client,
+			Token:         token,
+			Escrow:        escrow,
+			EscrowV2:      escrowV2,
+			AdminAuth:     adminAuth,
+			ChainID:       chainID,
+			TokenAddress:  tokenAddress,
+			EscrowAddress: escrowAddress,
+			Chain: &ChainConfig{
+				ChainID:       chainID.Int64(),
+				TokenAddress:  tokenAddress.Hex(),
+				EscrowAddress: escrowAddress.Hex(),
+				Confirmations: 1,
+			},
+		},
+		admin:    keys[0],
+		creator:  keys[1],
+		executor: keys[2],
+	}
+}
+
+// simChain mines each transaction as soon as it is sent and adds the reads
+// Backend needs that SimulatedBackend lacks
+type simChain struct {
+	*backends.SimulatedBackend
+}
+
+func (b simChain) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.SimulatedBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	b.Commit()
+	return nil
+}
+
+func (b simChain) ChainID(ctx context.Context) (*big.Int, error) {
+	return b.Blockchain().Config().ChainID, nil
+}
+
+func (b simChain) BlockNumber(ctx context.Context) (uint64, error) {
+	return b.Blockchain().CurrentBlock().Number.Uint64(), nil
+}
+
+// now returns the latest block's timestamp, the contracts' notion of time
+func (c *testChain) now(t *testing.T) int64 {
+	t.Helper()
+
+	header, err := c.Client.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return int64(header.Time)
+}
+
+// advance moves the chain's clock forward by d and mines a block
+func (c *testChain) advance(t *testing.T, d time.Duration) {
+	t.Helper()
+
+	sim := c.Client.(simChain)
+	if err := sim.AdjustTime(d); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+}
+
+// loadArtifact reads a contract's ABI and creation bytecode
+func loadArtifact(t *testing.T, name string) (abi.ABI, []byte) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(artifactsDir, name+".sol", name+".json"))
+	if os.IsNotExist(err) {
+		t.Skipf("%s not compiled; run npx hardhat compile in contracts/", name)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var artifact struct {
+		ABI      json.RawMessage `json:"abi"`
+		Bytecode string          `json:"bytecode"`
+	}
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := abi.JSON(strings.NewReader(string(artifact.ABI)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed, common.FromHex(artifact.Bytecode)
+}
+
+func deploy(t *testing.T, client Backend, auth *bind.TransactOpts, parsed abi.ABI, code []byte, args ...interface{}) common.Address {
+	t.Helper()
+
+	address, tx, _, err := bind.DeployContract(auth, parsed, code, client, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bind.WaitDeployed(context.Background(), client, tx); err != nil {
+		t.Fatal(err)
+	}
+	return address
+}
+
+// fund sends XZT from the admin, who holds the initial supply
+func (c *testChain) fund(t *testing.T, to common.Address, amount *big.Int) {
+	t.Helper()
+
+	if _, err := c.Transfer(to.Hex(), amount); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// address returns the account of one of the chain's keys
+func address(key *ecdsa.PrivateKey) common.Address {
+	return crypto.PubkeyToAddress(key.PublicKey)
+}
+
+// xzt converts whole tokens to wei
+func xzt(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(params.Ether))
+}
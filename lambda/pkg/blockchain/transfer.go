@@ -0,0 +1,102 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// ErrUnconfirmed is returned, with the transaction hash, for a transfer
+	// that was sent but has no receipt yet; it may still be mined
+	ErrUnconfirmed = errors.New("transfer sent but not confirmed yet")
+	// ErrTransferReverted is returned for a transfer mined with a failed status
+	ErrTransferReverted = errors.New("transaction failed")
+)
+
+// TransferResult identifies a mined transfer
+type TransferResult struct {
+	TxHash      string
+	BlockNumber uint64
+}
+
+// TransferAs sends XZT from the signer's wallet
+func (c *BlockchainClient) TransferAs(ctx context.Context, signer Signer, to common.Address, amount *big.Int) (*TransferResult, error) {
+	opts, err := signer.TransactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.Token.Transfer(opts, to, amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transfer: %w", err)
+	}
+
+	receipt, err := c.waitMined(ctx, tx)
+	if err != nil {
+		return &TransferResult{TxHash: tx.Hash().Hex()}, fmt.Errorf("%w: %v", ErrUnconfirmed, err)
+	}
+
+	if receipt.Status == 0 {
+		return nil, ErrTransferReverted
+	}
+
+	return &TransferResult{TxHash: tx.Hash().Hex(), BlockNumber: receipt.BlockNumber.Uint64()}, nil
+}
+
+// TransferWithPermit moves XZT out of a wallet the backend has no key for.
+// The owner signs an EIP-2612 permit for the admin wallet, which relays the
+// permit and then calls transferFrom.
+func (c *BlockchainClient) TransferWithPermit(ctx context.Context, owner, to common.Address, amount *big.Int, permit *PermitSignature) (*TransferResult, error) {
+	if _, err := c.submitPermit(owner, c.AdminAuth.From, amount, permit); err != nil {
+		return nil, err
+	}
+
+	tx, err := c.Token.TransferFrom(c.AdminAuth, owner, to, amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transfer: %w", err)
+	}
+
+	receipt, err := c.waitMined(ctx, tx)
+	if err != nil {
+		return &TransferResult{TxHash: tx.Hash().Hex()}, fmt.Errorf("%w: %v", ErrUnconfirmed, err)
+	}
+
+	if receipt.Status == 0 {
+		return nil, ErrTransferReverted
+	}
+
+	return &TransferResult{TxHash: tx.Hash().Hex(), BlockNumber: receipt.BlockNumber.Uint64()}, nil
+}
+
+// TransferOutcome looks up a transfer sent earlier. It returns ErrUnconfirmed
+// until the receipt has the chain's confirmations and ErrTransferReverted
+// for a failed transfer.
+func (c *BlockchainClient) TransferOutcome(ctx context.Context, txHash string) (*TransferResult, error) {
+	receipt, err := c.Client.TransactionReceipt(ctx, common.HexToHash(txHash))
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, ErrUnconfirmed
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipt: %w", err)
+	}
+	if receipt.Status == 0 {
+		return nil, ErrTransferReverted
+	}
+
+	if c.Chain != nil && c.Chain.Confirmations > 1 {
+		head, err := c.Client.BlockNumber(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get block number: %w", err)
+		}
+		if head < receipt.BlockNumber.Uint64()+c.Chain.Confirmations-1 {
+			return nil, ErrUnconfirmed
+		}
+	}
+
+	return &TransferResult{TxHash: txHash, BlockNumber: receipt.BlockNumber.Uint64()}, nil
+}
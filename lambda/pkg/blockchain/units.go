@@ -0,0 +1,42 @@
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// XZT has 18 decimals
+var weiPerXZT = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// ToWei converts a decimal XZT amount ("12.5") to wei without float rounding
+func ToWei(amount string) (*big.Int, error) {
+	amount = strings.TrimSpace(amount)
+	whole, frac, _ := strings.Cut(amount, ".")
+	if whole == "" {
+		whole = "0"
+	}
+	if len(frac) > 18 {
+		return nil, fmt.Errorf("amount has more than 18 decimals: %s", amount)
+	}
+	frac += strings.Repeat("0", 18-len(frac))
+
+	wei, ok := new(big.Int).SetString(whole+frac, 10)
+	if !ok || strings.HasPrefix(whole, "-") || strings.HasPrefix(whole, "+") {
+		return nil, fmt.Errorf("invalid amount: %s", amount)
+	}
+	return wei, nil
+}
+
+// FromWei formats wei as XZT with the given number of decimals
+func FromWei(wei *big.Int, decimals int) string {
+	value := new(big.Float).SetPrec(256).SetInt(wei)
+	value.Quo(value, new(big.Float).SetPrec(256).SetInt(weiPerXZT))
+	return value.Text('f', decimals)
+}
+
+// ApplyBps returns bps basis points of amount, rounded down
+func ApplyBps(amount *big.Int, bps int64) *big.Int {
+	share := new(big.Int).Mul(amount, big.NewInt(bps))
+	return share.Div(share, big.NewInt(10000))
+}
@@ -0,0 +1,201 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Withdrawal is a proposed emergency withdrawal on a v2 escrow
+type Withdrawal struct {
+	ID           uint64
+	To           common.Address
+	Amount       *big.Int
+	ExecutableAt time.Time
+	Executed     bool
+	Cancelled    bool
+}
+
+// Status is "pending", "ready", "executed" or "cancelled"
+func (w *Withdrawal) Status() string {
+	switch {
+	case w.Executed:
+		return "executed"
+	case w.Cancelled:
+		return "cancelled"
+	case time.Now().Before(w.ExecutableAt):
+		return "pending"
+	}
+	return "ready"
+}
+
+// WithdrawLimits is the escrow's emergency withdrawal headroom
+type WithdrawLimits struct {
+	Excess            *big.Int // Balance beyond what open tasks hold
+	Outstanding       *big.Int // Remaining balances of open tasks
+	PeriodLimit       *big.Int
+	WithdrawnInPeriod *big.Int
+	PeriodStart       time.Time
+	PeriodLength      time.Duration
+	Delay             time.Duration
+}
+
+// WithdrawLimits reads the excess, outstanding and per-period figures
+func (c *BlockchainClient) WithdrawLimits(ctx context.Context) (*WithdrawLimits, error) {
+	if c.EscrowV2 == nil {
+		return nil, ErrEscrowV2Required
+	}
+	opts := &bind.CallOpts{Context: ctx}
+
+	limits := &WithdrawLimits{}
+	var err error
+	if limits.Excess, err = c.EscrowV2.WithdrawableExcess(opts); err != nil {
+		return nil, fmt.Errorf("failed to get withdrawable excess: %w", err)
+	}
+	if limits.Outstanding, err = c.EscrowV2.TotalOutstanding(opts); err != nil {
+		return nil, fmt.Errorf("failed to get outstanding balance: %w", err)
+	}
+	if limits.PeriodLimit, err = c.EscrowV2.WithdrawPeriodLimit(opts); err != nil {
+		return nil, fmt.Errorf("failed to get period limit: %w", err)
+	}
+	if limits.WithdrawnInPeriod, err = c.EscrowV2.WithdrawnInPeriod(opts); err != nil {
+		return nil, fmt.Errorf("failed to get withdrawn amount: %w", err)
+	}
+	periodStart, err := c.EscrowV2.WithdrawPeriodStart(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get period start: %w", err)
+	}
+	period, err := c.EscrowV2.WITHDRAWPERIOD(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get period length: %w", err)
+	}
+	delay, err := c.EscrowV2.WITHDRAWDELAY(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get withdraw delay: %w", err)
+	}
+	limits.PeriodStart = time.Unix(periodStart.Int64(), 0).UTC()
+	limits.PeriodLength = time.Duration(period.Int64()) * time.Second
+	limits.Delay = time.Duration(delay.Int64()) * time.Second
+
+	// A new period starts at the next execution once the current one has run
+	// out, by the chain's clock
+	head, err := c.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	if !time.Unix(int64(head.Time), 0).Before(limits.PeriodStart.Add(limits.PeriodLength)) {
+		limits.WithdrawnInPeriod = new(big.Int)
+	}
+	return limits, nil
+}
+
+// ProposeWithdrawal queues an emergency withdrawal of excess tokens and returns its id
+func (c *BlockchainClient) ProposeWithdrawal(ctx context.Context, to common.Address, amount *big.Int) (uint64, string, error) {
+	if c.EscrowV2 == nil {
+		return 0, "", ErrEscrowV2Required
+	}
+
+	tx, err := c.EscrowV2.ProposeEmergencyWithdraw(c.AdminAuth, to, amount)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to propose withdrawal: %w", err)
+	}
+
+	receipt, err := c.waitMined(ctx, tx)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to wait for transaction: %w", err)
+	}
+
+	if receipt.Status == 0 {
+		return 0, "", fmt.Errorf("transaction failed")
+	}
+
+	for _, log := range receipt.Logs {
+		if log.Address != c.EscrowAddress {
+			continue
+		}
+		if event, err := c.EscrowV2.ParseWithdrawalProposed(*log); err == nil {
+			return event.WithdrawalId.Uint64(), tx.Hash().Hex(), nil
+		}
+	}
+	return 0, "", fmt.Errorf("WithdrawalProposed event not found in transaction %s", tx.Hash().Hex())
+}
+
+// Withdrawals lists every proposed withdrawal, oldest first
+func (c *BlockchainClient) Withdrawals(ctx context.Context) ([]*Withdrawal, error) {
+	if c.EscrowV2 == nil {
+		return nil, ErrEscrowV2Required
+	}
+	opts := &bind.CallOpts{Context: ctx}
+
+	count, err := c.EscrowV2.NextWithdrawalId(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get withdrawal count: %w", err)
+	}
+
+	withdrawals := make([]*Withdrawal, 0, count.Uint64())
+	for id := uint64(0); id < count.Uint64(); id++ {
+		w, err := c.EscrowV2.Withdrawals(opts, new(big.Int).SetUint64(id))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get withdrawal %d: %w", id, err)
+		}
+		withdrawals = append(withdrawals, &Withdrawal{
+			ID:           id,
+			To:           w.To,
+			Amount:       w.Amount,
+			ExecutableAt: time.Unix(w.ExecutableAt.Int64(), 0).UTC(),
+			Executed:     w.Executed,
+			Cancelled:    w.Cancelled,
+		})
+	}
+	return withdrawals, nil
+}
+
+// ExecuteWithdrawal sends a proposed withdrawal once its timelock has passed
+func (c *BlockchainClient) ExecuteWithdrawal(ctx context.Context, id uint64) (string, error) {
+	if c.EscrowV2 == nil {
+		return "", ErrEscrowV2Required
+	}
+
+	tx, err := c.EscrowV2.ExecuteEmergencyWithdraw(c.AdminAuth, new(big.Int).SetUint64(id))
+	if err != nil {
+		return "", fmt.Errorf("failed to execute withdrawal: %w", err)
+	}
+
+	receipt, err := c.waitMined(ctx, tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for transaction: %w", err)
+	}
+
+	if receipt.Status == 0 {
+		return "", fmt.Errorf("transaction failed")
+	}
+
+	return tx.Hash().Hex(), nil
+}
+
+// CancelWithdrawal drops a proposed withdrawal
+func (c *BlockchainClient) CancelWithdrawal(ctx context.Context, id uint64) (string, error) {
+	if c.EscrowV2 == nil {
+		return "", ErrEscrowV2Required
+	}
+
+	tx, err := c.EscrowV2.CancelEmergencyWithdraw(c.AdminAuth, new(big.Int).SetUint64(id))
+	if err != nil {
+		return "", fmt.Errorf("failed to cancel withdrawal: %w", err)
+	}
+
+	receipt, err := c.waitMined(ctx, tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for transaction: %w", err)
+	}
+
+	if receipt.Status == 0 {
+		return "", fmt.Errorf("transaction failed")
+	}
+
+	return tx.Hash().Hex(), nil
+}
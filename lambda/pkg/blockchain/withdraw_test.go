@@ -0,0 +1,110 @@
+package blockchain
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// withdrawDelay and withdrawPeriod match TaskEscrowV2's WITHDRAW_DELAY and WITHDRAW_PERIOD
+const (
+	withdrawDelay  = 2 * 24 * time.Hour
+	withdrawPeriod = 7 * 24 * time.Hour
+)
+
+func TestEmergencyWithdrawTimelock(t *testing.T) {
+	chain := newTestChain(t, "TaskEscrowV2")
+	ctx := context.Background()
+	to := address(chain.executor)
+	chain.fund(t, chain.EscrowAddress, xzt(300))
+
+	id, _, err := chain.ProposeWithdrawal(ctx, to, xzt(200))
+	if err != nil {
+		t.Fatalf("ProposeWithdrawal: %v", err)
+	}
+
+	// Before the delay
+	if _, err := chain.ExecuteWithdrawal(ctx, id); err == nil {
+		t.Fatal("withdrawal executed before its timelock")
+	}
+	chain.advance(t, withdrawDelay-time.Hour)
+	if _, err := chain.ExecuteWithdrawal(ctx, id); err == nil {
+		t.Fatal("withdrawal executed an hour before its timelock")
+	}
+	assertBalance(t, chain, to, xzt(0))
+
+	// After the delay, once
+	chain.advance(t, time.Hour)
+	if _, err := chain.ExecuteWithdrawal(ctx, id); err != nil {
+		t.Fatalf("ExecuteWithdrawal: %v", err)
+	}
+	assertBalance(t, chain, to, xzt(200))
+	if _, err := chain.ExecuteWithdrawal(ctx, id); err == nil {
+		t.Fatal("withdrawal executed twice")
+	}
+
+	// A cancelled proposal never executes
+	id, _, err = chain.ProposeWithdrawal(ctx, to, xzt(50))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.CancelWithdrawal(ctx, id); err != nil {
+		t.Fatalf("CancelWithdrawal: %v", err)
+	}
+	chain.advance(t, withdrawDelay)
+	if _, err := chain.ExecuteWithdrawal(ctx, id); err == nil {
+		t.Fatal("cancelled withdrawal executed")
+	}
+	assertBalance(t, chain, to, xzt(200))
+}
+
+func TestEmergencyWithdrawLimits(t *testing.T) {
+	chain := newTestChain(t, "TaskEscrowV2")
+	ctx := context.Background()
+	to := address(chain.executor)
+	newOpenTask(t, chain) // 100 XZT that must stay locked
+	chain.fund(t, chain.EscrowAddress, xzt(1500))
+
+	// Only the excess over open tasks can be proposed
+	if _, _, err := chain.ProposeWithdrawal(ctx, to, xzt(1501)); err == nil {
+		t.Fatal("proposed a withdrawal of task funds")
+	}
+
+	// The deployment caps each period at 1000 XZT
+	first, _, err := chain.ProposeWithdrawal(ctx, to, xzt(600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _, err := chain.ProposeWithdrawal(ctx, to, xzt(500))
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.advance(t, withdrawDelay)
+	if _, err := chain.ExecuteWithdrawal(ctx, first); err != nil {
+		t.Fatalf("first withdrawal: %v", err)
+	}
+	if _, err := chain.ExecuteWithdrawal(ctx, second); err == nil {
+		t.Fatal("withdrawal over the period limit executed")
+	}
+
+	limits, err := chain.WithdrawLimits(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limits.WithdrawnInPeriod.Cmp(xzt(600)) != 0 || limits.PeriodLimit.Cmp(xzt(1000)) != 0 {
+		t.Fatalf("withdrawn %s of %s, want %s of %s", limits.WithdrawnInPeriod, limits.PeriodLimit, xzt(600), xzt(1000))
+	}
+
+	// A new period makes room again
+	chain.advance(t, withdrawPeriod)
+	if _, err := chain.ExecuteWithdrawal(ctx, second); err != nil {
+		t.Fatalf("second withdrawal in the next period: %v", err)
+	}
+	assertBalance(t, chain, to, xzt(1100))
+	assertBalance(t, chain, chain.EscrowAddress, xzt(500))
+
+	// What is left beyond the open task is the only excess
+	if _, _, err := chain.ProposeWithdrawal(ctx, to, xzt(401)); err == nil {
+		t.Fatal("proposed a withdrawal of task funds")
+	}
+}
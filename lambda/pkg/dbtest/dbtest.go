@@ -0,0 +1,90 @@
+// Package dbtest gives tests a throwaway copy of the database schema
+package dbtest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ownedTables stands in for users and projects, which the identity service
+// creates and schema.sql only extends
+const ownedTables = `
+	CREATE TABLE users (
+		did VARCHAR(66) PRIMARY KEY,
+		username VARCHAR(255),
+		email VARCHAR(255),
+		bio TEXT,
+		eth_address VARCHAR(42),
+		profession_tags TEXT[] DEFAULT '{}',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE projects (
+		project_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		project_name VARCHAR(255),
+		owner_did VARCHAR(66) REFERENCES users(did),
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+`
+
+// Begin opens a transaction on TEST_DATABASE_URL with database/schema.sql
+// loaded into a fresh schema. Everything is rolled back when the test ends.
+// The test is skipped when TEST_DATABASE_URL is not set.
+func Begin(t testing.TB) pgx.Tx {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+
+	config, err := pgx.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("invalid TEST_DATABASE_URL: %v", err)
+	}
+	// schema.sql is many statements in one string
+	config.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+	conn, err := pgx.ConnectConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close(ctx) })
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { tx.Rollback(ctx) })
+
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		t.Fatal(err)
+	}
+	schema := "test_" + hex.EncodeToString(suffix)
+	if _, err := tx.Exec(ctx, "CREATE SCHEMA "+schema+"; SET LOCAL search_path TO "+schema+", public"); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	ddl, err := os.ReadFile(schemaPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(ctx, ownedTables+string(ddl)); err != nil {
+		t.Fatalf("failed to load schema.sql: %v", err)
+	}
+	return tx
+}
+
+// schemaPath locates database/schema.sql from this file, whatever package
+// the test runs in
+func schemaPath() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "..", "database", "schema.sql")
+}
@@ -0,0 +1,317 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jackc/pgx/v5"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/db"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+// HeaderName is the request header carrying the client-chosen key
+const HeaderName = "Idempotency-Key"
+
+// Key status values stored in idempotency_keys.status
+const (
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+)
+
+// Handler is the API Gateway handler signature used by every Lambda in cmd/
+type Handler func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error)
+
+var (
+	// ErrInProgress is returned when another request with the same key is still running
+	ErrInProgress = errors.New("a request with this Idempotency-Key is already in progress")
+	// ErrKeyReused is returned when a key is replayed with a different request body
+	ErrKeyReused = errors.New("Idempotency-Key was already used with a different request body")
+)
+
+// Record is a stored idempotency key
+type Record struct {
+	DID          string
+	Route        string
+	Key          string
+	RequestHash  string
+	Status       string
+	StatusCode   int
+	ResponseBody string
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+// store keeps idempotency keys; Wrap uses Postgres, tests an in-memory store
+type store interface {
+	Begin(ctx context.Context, did, route, key, body string) (*Record, error)
+	Complete(ctx context.Context, did, route, key string, statusCode int, body string) error
+	Release(ctx context.Context, did, route, key string) error
+}
+
+// pgStore keeps keys in idempotency_keys
+type pgStore struct {
+	q db.Querier
+}
+
+func (s pgStore) Begin(ctx context.Context, did, route, key, body string) (*Record, error) {
+	return Begin(ctx, s.q, did, route, key, body)
+}
+
+func (s pgStore) Complete(ctx context.Context, did, route, key string, statusCode int, body string) error {
+	return Complete(ctx, s.q, did, route, key, statusCode, body)
+}
+
+func (s pgStore) Release(ctx context.Context, did, route, key string) error {
+	return Release(ctx, s.q, did, route, key)
+}
+
+// openPgStore connects to the database for one request
+func openPgStore() (store, error) {
+	if err := db.InitDB(); err != nil {
+		return nil, err
+	}
+	return pgStore{db.GetPool()}, nil
+}
+
+// Wrap makes a POST handler idempotent. Requests without an Idempotency-Key
+// header, or without a valid token, are passed straight to the handler.
+// Responses are stored and replayed on retry, so a client that wants to try
+// again after an error must use a new key. Only a 5xx marked with
+// response.RetryableError frees the key: any other 5xx may come after a
+// transaction was sent, and running the request again could pay twice.
+func Wrap(h Handler) Handler {
+	return wrap(h, openPgStore)
+}
+
+func wrap(h Handler, open func() (store, error)) Handler {
+	return func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		if request.HTTPMethod != "POST" {
+			return h(ctx, request)
+		}
+
+		key := headerValue(request.Headers, HeaderName)
+		if key == "" {
+			return h(ctx, request)
+		}
+		if len(key) > 255 {
+			return response.Error(400, "Idempotency-Key must be at most 255 characters")
+		}
+
+		authHeader := headerValue(request.Headers, "Authorization")
+		claims, err := auth.ValidateToken(authHeader)
+		if err != nil {
+			// Let the handler produce its usual 401
+			return h(ctx, request)
+		}
+
+		keys, err := open()
+		if err != nil {
+			return response.RetryableError(500, fmt.Sprintf("Database error: %v", err))
+		}
+
+		route := request.HTTPMethod + " " + request.Path
+		record, err := keys.Begin(ctx, claims.DID, route, key, request.Body)
+		switch {
+		case errors.Is(err, ErrInProgress):
+			return response.Error(409, err.Error())
+		case errors.Is(err, ErrKeyReused):
+			return response.Error(422, err.Error())
+		case err != nil:
+			return response.RetryableError(500, fmt.Sprintf("Idempotency error: %v", err))
+		}
+
+		// Completed earlier: replay the stored response
+		if record.Status == StatusCompleted {
+			return replay(record), nil
+		}
+
+		resp, handlerErr := h(ctx, request)
+		if handlerErr == nil && resp.StatusCode >= 500 && resp.Headers[response.RetryableHeader] == "true" {
+			// Nothing happened; free the key so the client can retry
+			if err := keys.Release(ctx, claims.DID, route, key); err != nil {
+				fmt.Printf("Failed to release idempotency key %s: %v\n", key, err)
+			}
+			return resp, nil
+		}
+		if handlerErr != nil {
+			// The handler may have stopped after sending a transaction
+			resp, _ = response.Error(500, fmt.Sprintf("Request failed: %v. Check its effect before retrying with a new Idempotency-Key.", handlerErr))
+		}
+
+		if err := keys.Complete(ctx, claims.DID, route, key, resp.StatusCode, resp.Body); err != nil {
+			fmt.Printf("Failed to store idempotent response for key %s: %v\n", key, err)
+		}
+
+		return resp, nil
+	}
+}
+
+// Begin claims (did, route, key) for the current request. It returns the
+// existing record when the key already completed, ErrInProgress while another
+// request holds it and ErrKeyReused when the body does not match. An
+// in-progress key is only taken over once it is older than
+// IDEMPOTENCY_IN_PROGRESS_TTL, the longest a Lambda can run: its request
+// was cut off and will never complete the key.
+func Begin(ctx context.Context, q db.Querier, did, route, key, body string) (*Record, error) {
+	hash := hashBody(body)
+	now := time.Now()
+
+	ttl, err := keyTTL()
+	if err != nil {
+		return nil, err
+	}
+	inProgressTTL, err := inProgressTTL()
+	if err != nil {
+		return nil, err
+	}
+	// Drop an expired key, or one whose request died in progress
+	_, err = q.Exec(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE user_did = $1 AND route = $2 AND idem_key = $3
+		  AND (expires_at < $4 OR (status = $5 AND created_at < $6))
+	`, did, route, key, now, StatusInProgress, now.Add(-inProgressTTL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge stale key: %w", err)
+	}
+
+	record := &Record{
+		DID:         did,
+		Route:       route,
+		Key:         key,
+		RequestHash: hash,
+		Status:      StatusInProgress,
+	}
+	err = q.QueryRow(ctx, `
+		INSERT INTO idempotency_keys (user_did, route, idem_key, request_hash, status, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_did, route, idem_key) DO NOTHING
+		RETURNING created_at, expires_at
+	`, did, route, key, hash, StatusInProgress, now, now.Add(ttl)).Scan(&record.CreatedAt, &record.ExpiresAt)
+	if err == nil {
+		return record, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to claim key: %w", err)
+	}
+
+	// Key already exists
+	var statusCode *int
+	var responseBody *string
+	err = q.QueryRow(ctx, `
+		SELECT request_hash, status, status_code, response_body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE user_did = $1 AND route = $2 AND idem_key = $3
+	`, did, route, key).Scan(&record.RequestHash, &record.Status, &statusCode, &responseBody, &record.CreatedAt, &record.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Purged between the insert and the select; ask the client to retry
+		return nil, ErrInProgress
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load key: %w", err)
+	}
+
+	if record.RequestHash != hash {
+		return nil, ErrKeyReused
+	}
+	if record.Status != StatusCompleted {
+		return nil, ErrInProgress
+	}
+
+	if statusCode != nil {
+		record.StatusCode = *statusCode
+	}
+	if responseBody != nil {
+		record.ResponseBody = *responseBody
+	}
+
+	return record, nil
+}
+
+// Complete stores the handler's response against the key
+func Complete(ctx context.Context, q db.Querier, did, route, key string, statusCode int, body string) error {
+	_, err := q.Exec(ctx, `
+		UPDATE idempotency_keys
+		SET status = $1, status_code = $2, response_body = $3, completed_at = NOW()
+		WHERE user_did = $4 AND route = $5 AND idem_key = $6
+	`, StatusCompleted, statusCode, body, did, route, key)
+	if err != nil {
+		return fmt.Errorf("failed to complete key: %w", err)
+	}
+	return nil
+}
+
+// Release deletes an in-progress key so the request can be retried
+func Release(ctx context.Context, q db.Querier, did, route, key string) error {
+	_, err := q.Exec(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE user_did = $1 AND route = $2 AND idem_key = $3 AND status = $4
+	`, did, route, key, StatusInProgress)
+	if err != nil {
+		return fmt.Errorf("failed to release key: %w", err)
+	}
+	return nil
+}
+
+// replay rebuilds the stored API Gateway response
+func replay(record *Record) events.APIGatewayProxyResponse {
+	return events.APIGatewayProxyResponse{
+		StatusCode: record.StatusCode,
+		Headers: map[string]string{
+			"Content-Type":                 "application/json",
+			"Access-Control-Allow-Origin":  "*",
+			"Access-Control-Allow-Headers": response.AllowHeaders,
+			"Access-Control-Allow-Methods": response.AllowMethods,
+			"Idempotent-Replayed":          "true",
+		},
+		Body: record.ResponseBody,
+	}
+}
+
+// keyTTL reads IDEMPOTENCY_KEY_TTL (default 24h)
+func keyTTL() (time.Duration, error) {
+	return durationEnv("IDEMPOTENCY_KEY_TTL", 24*time.Hour)
+}
+
+// inProgressTTL reads IDEMPOTENCY_IN_PROGRESS_TTL (default 15m, the Lambda
+// timeout limit)
+func inProgressTTL() (time.Duration, error) {
+	return durationEnv("IDEMPOTENCY_IN_PROGRESS_TTL", 15*time.Minute)
+}
+
+func durationEnv(name string, def time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return d, nil
+}
+
+func hashBody(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// headerValue looks up a header case-insensitively (API Gateway keeps client casing)
+func headerValue(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
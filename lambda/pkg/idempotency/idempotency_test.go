@@ -0,0 +1,238 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/x-zero/xz-wallet/pkg/auth"
+	"github.com/x-zero/xz-wallet/pkg/response"
+)
+
+// memStore keeps keys in memory with the same rules Begin applies in SQL
+type memStore struct {
+	records map[string]*Record
+}
+
+func newMemStore() *memStore {
+	return &memStore{records: map[string]*Record{}}
+}
+
+func (s *memStore) Begin(ctx context.Context, did, route, key, body string) (*Record, error) {
+	id := did + " " + route + " " + key
+	now := time.Now()
+	ttl, err := inProgressTTL()
+	if err != nil {
+		return nil, err
+	}
+	if r, ok := s.records[id]; ok && r.Status == StatusInProgress && r.CreatedAt.Before(now.Add(-ttl)) {
+		delete(s.records, id)
+	}
+
+	r, ok := s.records[id]
+	if !ok {
+		r = &Record{DID: did, Route: route, Key: key, RequestHash: hashBody(body), Status: StatusInProgress, CreatedAt: now}
+		s.records[id] = r
+		copied := *r
+		return &copied, nil
+	}
+	if r.RequestHash != hashBody(body) {
+		return nil, ErrKeyReused
+	}
+	if r.Status != StatusCompleted {
+		return nil, ErrInProgress
+	}
+	copied := *r
+	return &copied, nil
+}
+
+func (s *memStore) Complete(ctx context.Context, did, route, key string, statusCode int, body string) error {
+	r := s.records[did+" "+route+" "+key]
+	r.Status, r.StatusCode, r.ResponseBody = StatusCompleted, statusCode, body
+	return nil
+}
+
+func (s *memStore) Release(ctx context.Context, did, route, key string) error {
+	id := did + " " + route + " " + key
+	if r, ok := s.records[id]; ok && r.Status == StatusInProgress {
+		delete(s.records, id)
+	}
+	return nil
+}
+
+// countingHandler answers with the queued responses and counts its calls
+type countingHandler struct {
+	calls     int
+	responses []func() (events.APIGatewayProxyResponse, error)
+}
+
+func (h *countingHandler) handle(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	next := h.responses[h.calls%len(h.responses)]
+	h.calls++
+	return next()
+}
+
+func ok() (events.APIGatewayProxyResponse, error) {
+	return response.Success(map[string]string{"status": "ok"})
+}
+
+func newRequest(t *testing.T, key, body string) events.APIGatewayProxyRequest {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("JWT_REVOCATION_CHECK", "false")
+	token, err := auth.GenerateToken("did:example:alice", "alice")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	return events.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodPost,
+		Path:       "/tasks",
+		Headers:    map[string]string{"authorization": "Bearer " + token, "idempotency-key": key},
+		Body:       body,
+	}
+}
+
+func wrapped(keys *memStore, h *countingHandler) Handler {
+	return wrap(h.handle, func() (store, error) { return keys, nil })
+}
+
+func TestWrapReplaysCompletedResponse(t *testing.T) {
+	keys, h := newMemStore(), &countingHandler{responses: []func() (events.APIGatewayProxyResponse, error){ok}}
+	handler := wrapped(keys, h)
+	req := newRequest(t, "k1", `{"a":1}`)
+
+	first, _ := handler(context.Background(), req)
+	second, _ := handler(context.Background(), req)
+
+	if h.calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", h.calls)
+	}
+	if second.StatusCode != first.StatusCode || second.Body != first.Body {
+		t.Fatalf("replay = %d %s, want %d %s", second.StatusCode, second.Body, first.StatusCode, first.Body)
+	}
+	if second.Headers["Idempotent-Replayed"] != "true" {
+		t.Fatal("replay is missing Idempotent-Replayed")
+	}
+}
+
+func TestWrapRejectsKeyReuseWithDifferentBody(t *testing.T) {
+	keys, h := newMemStore(), &countingHandler{responses: []func() (events.APIGatewayProxyResponse, error){ok}}
+	handler := wrapped(keys, h)
+
+	handler(context.Background(), newRequest(t, "k1", `{"a":1}`))
+	resp, _ := handler(context.Background(), newRequest(t, "k1", `{"a":2}`))
+
+	if resp.StatusCode != 422 {
+		t.Fatalf("status = %d, want 422", resp.StatusCode)
+	}
+	if h.calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", h.calls)
+	}
+}
+
+func TestWrapReturnsConflictWhileInProgress(t *testing.T) {
+	keys := newMemStore()
+	var inner events.APIGatewayProxyResponse
+	var handler Handler
+	req := newRequest(t, "k1", `{}`)
+	h := &countingHandler{responses: []func() (events.APIGatewayProxyResponse, error){func() (events.APIGatewayProxyResponse, error) {
+		// The same key arrives again while this request is running
+		inner, _ = handler(context.Background(), req)
+		return ok()
+	}}}
+	handler = wrapped(keys, h)
+
+	handler(context.Background(), req)
+
+	if inner.StatusCode != 409 {
+		t.Fatalf("concurrent status = %d, want 409", inner.StatusCode)
+	}
+	if h.calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", h.calls)
+	}
+}
+
+func TestWrapReleasesKeyOnRetryableError(t *testing.T) {
+	keys := newMemStore()
+	h := &countingHandler{responses: []func() (events.APIGatewayProxyResponse, error){
+		func() (events.APIGatewayProxyResponse, error) { return response.RetryableError(500, "Database error") },
+		ok,
+	}}
+	handler := wrapped(keys, h)
+	req := newRequest(t, "k1", `{}`)
+
+	first, _ := handler(context.Background(), req)
+	second, _ := handler(context.Background(), req)
+
+	if first.StatusCode != 500 || second.StatusCode != 200 {
+		t.Fatalf("statuses = %d, %d; want 500, 200", first.StatusCode, second.StatusCode)
+	}
+	if h.calls != 2 {
+		t.Fatalf("handler ran %d times, want 2", h.calls)
+	}
+}
+
+func TestWrapStoresOtherServerErrors(t *testing.T) {
+	keys := newMemStore()
+	h := &countingHandler{responses: []func() (events.APIGatewayProxyResponse, error){
+		func() (events.APIGatewayProxyResponse, error) {
+			return response.Error(500, "Task created on blockchain but database update failed")
+		},
+		ok,
+	}}
+	handler := wrapped(keys, h)
+	req := newRequest(t, "k1", `{}`)
+
+	first, _ := handler(context.Background(), req)
+	second, _ := handler(context.Background(), req)
+
+	if h.calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", h.calls)
+	}
+	if second.StatusCode != 500 || second.Body != first.Body {
+		t.Fatalf("replay = %d %s, want the stored 500", second.StatusCode, second.Body)
+	}
+}
+
+func TestWrapExpiresStaleInProgressKey(t *testing.T) {
+	t.Setenv("IDEMPOTENCY_IN_PROGRESS_TTL", "15m")
+	keys := newMemStore()
+	h := &countingHandler{responses: []func() (events.APIGatewayProxyResponse, error){ok}}
+	handler := wrapped(keys, h)
+	req := newRequest(t, "k1", `{}`)
+
+	// A Lambda that timed out left the key in progress
+	record, err := keys.Begin(context.Background(), "did:example:alice", "POST /tasks", "k1", `{}`)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	stored := keys.records[record.DID+" "+record.Route+" "+record.Key]
+
+	if resp, _ := handler(context.Background(), req); resp.StatusCode != 409 {
+		t.Fatalf("fresh in-progress key: status = %d, want 409", resp.StatusCode)
+	}
+
+	stored.CreatedAt = time.Now().Add(-16 * time.Minute)
+	if resp, _ := handler(context.Background(), req); resp.StatusCode != 200 {
+		t.Fatalf("stale in-progress key: status = %d, want 200", resp.StatusCode)
+	}
+	if h.calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", h.calls)
+	}
+}
+
+func TestWrapPassesThroughWithoutKey(t *testing.T) {
+	keys := newMemStore()
+	h := &countingHandler{responses: []func() (events.APIGatewayProxyResponse, error){ok}}
+	handler := wrapped(keys, h)
+	req := newRequest(t, "", `{}`)
+
+	handler(context.Background(), req)
+	handler(context.Background(), req)
+
+	if h.calls != 2 || len(keys.records) != 0 {
+		t.Fatalf("calls = %d, stored keys = %d; want 2, 0", h.calls, len(keys.records))
+	}
+}
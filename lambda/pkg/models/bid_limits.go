@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BidLimit caps open (pending) bids for users at or above a credit score
+type BidLimit struct {
+	MinCreditScore int
+	MaxOpenBids    int
+}
+
+// DefaultBidLimits applies when OPEN_BID_LIMITS is unset or invalid
+var DefaultBidLimits = []BidLimit{
+	{MinCreditScore: 0, MaxOpenBids: 3},
+	{MinCreditScore: 3000, MaxOpenBids: 5},
+	{MinCreditScore: 5000, MaxOpenBids: 10},
+	{MinCreditScore: 7000, MaxOpenBids: 20},
+	{MinCreditScore: 9000, MaxOpenBids: 40},
+}
+
+// ParseBidLimits parses "score:max,score:max,..." into tiers sorted by score
+func ParseBidLimits(value string) ([]BidLimit, error) {
+	var limits []BidLimit
+	for _, tier := range strings.Split(value, ",") {
+		score, max, found := strings.Cut(strings.TrimSpace(tier), ":")
+		if !found {
+			return nil, fmt.Errorf("invalid tier %q, want score:max", tier)
+		}
+		minScore, err := strconv.Atoi(score)
+		if err != nil {
+			return nil, fmt.Errorf("invalid credit score in %q", tier)
+		}
+		maxBids, err := strconv.Atoi(max)
+		if err != nil || maxBids < 0 {
+			return nil, fmt.Errorf("invalid bid count in %q", tier)
+		}
+		limits = append(limits, BidLimit{MinCreditScore: minScore, MaxOpenBids: maxBids})
+	}
+	sort.Slice(limits, func(i, j int) bool { return limits[i].MinCreditScore < limits[j].MinCreditScore })
+	return limits, nil
+}
+
+// OpenBidLimit returns how many open bids a user with creditScore may hold,
+// from OPEN_BID_LIMITS or DefaultBidLimits; 0 below the lowest tier
+func OpenBidLimit(creditScore int) int {
+	limits := DefaultBidLimits
+	if value := os.Getenv("OPEN_BID_LIMITS"); value != "" {
+		if parsed, err := ParseBidLimits(value); err == nil {
+			limits = parsed
+		}
+	}
+
+	max := 0
+	for _, limit := range limits {
+		if creditScore >= limit.MinCreditScore {
+			max = limit.MaxOpenBids
+		}
+	}
+	return max
+}
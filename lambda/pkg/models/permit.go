@@ -0,0 +1,10 @@
+package models
+
+// PermitRequest is an EIP-2612 permit as sent in API requests, signed by the
+// token owner for the escrow contract or the admin wallet
+type PermitRequest struct {
+	Deadline int64  `json:"deadline"`
+	V        uint8  `json:"v"`
+	R        string `json:"r"`
+	S        string `json:"s"`
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Criterion review results
+const (
+	CriterionPass = "pass"
+	CriterionFail = "fail"
+)
+
+// DefaultCriterion stands in for tasks created without acceptance criteria
+const DefaultCriterion = "Work matches the task description"
+
+// CriterionReview is a reviewer's verdict on one acceptance criterion of a submission
+type CriterionReview struct {
+	CriterionIndex int     `json:"criterion_index"`
+	Criterion      string  `json:"criterion,omitempty"` // Criterion text when reviewed
+	Result         string  `json:"result"`              // pass or fail
+	Comment        *string `json:"comment,omitempty"`
+}
+
+// SubmissionChanges compares a submission with the previous revision of its milestone
+type SubmissionChanges struct {
+	PreviousSubmissionID string   `json:"previous_submission_id"`
+	ContentChanged       bool     `json:"content_changed"`
+	FilesAdded           []string `json:"files_added,omitempty"`
+	FilesRemoved         []string `json:"files_removed,omitempty"`
+	PreviouslyFailed     []int    `json:"previously_failed,omitempty"` // Criteria the previous revision failed
+}
+
+// listMarker matches a leading "-", "*", "•", "1." or "1)" followed by
+// whitespace, so "1.5x speed" keeps its number
+var listMarker = regexp.MustCompile(`^(?:[-*•]|\d+[.)])(?:\s+|$)`)
+
+// AcceptanceCriteriaList splits a task's acceptance criteria into one
+// criterion per non-empty line, dropping list markers such as "-", "*" or "1."
+func AcceptanceCriteriaList(text string) []string {
+	var criteria []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(listMarker.ReplaceAllString(line, ""))
+		if line != "" {
+			criteria = append(criteria, line)
+		}
+	}
+	if len(criteria) == 0 {
+		criteria = []string{DefaultCriterion}
+	}
+	return criteria
+}
+
+// ReviewCriteria returns the criteria a task's work is reviewed against: the
+// snapshot taken when the executor was assigned, or for tasks assigned
+// before snapshots, the current acceptance criteria
+func ReviewCriteria(snapshot []string, text string) []string {
+	if len(snapshot) > 0 {
+		return snapshot
+	}
+	return AcceptanceCriteriaList(text)
+}
+
+// CompleteReview checks a review covers every criterion exactly once and
+// fills in the criterion text, in criterion order. Omitted results on an
+// approval count as passed; a rejection must list every criterion and fail
+// at least one, and an approval may not fail any.
+func CompleteReview(criteria []string, results []CriterionReview, approve bool) ([]CriterionReview, error) {
+	if len(results) == 0 && approve {
+		results = make([]CriterionReview, len(criteria))
+		for i := range criteria {
+			results[i] = CriterionReview{CriterionIndex: i, Result: CriterionPass}
+		}
+	}
+
+	review := make([]CriterionReview, len(criteria))
+	seen := make([]bool, len(criteria))
+	failed := 0
+	for _, result := range results {
+		i := result.CriterionIndex
+		if i < 0 || i >= len(criteria) {
+			return nil, fmt.Errorf("criterion_index %d is out of range (task has %d criteria)", i, len(criteria))
+		}
+		if seen[i] {
+			return nil, fmt.Errorf("criterion %d is reviewed twice", i)
+		}
+		switch result.Result {
+		case CriterionPass:
+		case CriterionFail:
+			failed++
+		default:
+			return nil, fmt.Errorf("criterion %d result must be pass or fail", i)
+		}
+		seen[i] = true
+		result.Criterion = criteria[i]
+		review[i] = result
+	}
+	for i, ok := range seen {
+		if !ok {
+			return nil, fmt.Errorf("criterion %d (%s) has no result", i, criteria[i])
+		}
+	}
+
+	if !approve && failed == 0 {
+		return nil, fmt.Errorf("a rejection must fail at least one criterion")
+	}
+	if approve && failed > 0 {
+		return nil, fmt.Errorf("cannot approve with %d failed criteria", failed)
+	}
+	return review, nil
+}
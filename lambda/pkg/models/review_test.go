@@ -0,0 +1,83 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAcceptanceCriteriaList(t *testing.T) {
+	cases := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"plain lines", "Loads in 2s\nWorks offline", []string{"Loads in 2s", "Works offline"}},
+		{"dash and star markers", "- Loads\n* Works\n• Ships", []string{"Loads", "Works", "Ships"}},
+		{"numbered markers", "1. Loads\n2) Works\n10.  Ships", []string{"Loads", "Works", "Ships"}},
+		{"number without space is text", "1.5x speed\n2)done", []string{"1.5x speed", "2)done"}},
+		{"marker without space is text", "-v flag works\n*bold* text", []string{"-v flag works", "*bold* text"}},
+		{"blank lines and bare markers", "\n  - Loads  \n-\n\n3.\n", []string{"Loads"}},
+		{"only the first marker", "- 1. Loads", []string{"1. Loads"}},
+		{"empty", "", []string{DefaultCriterion}},
+		{"whitespace only", " \n\t\n", []string{DefaultCriterion}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := AcceptanceCriteriaList(tc.text); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("AcceptanceCriteriaList(%q) = %q, want %q", tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReviewCriteria(t *testing.T) {
+	snapshot := []string{"Loads", "Works"}
+	if got := ReviewCriteria(snapshot, "- Edited after assignment"); !reflect.DeepEqual(got, snapshot) {
+		t.Fatalf("with snapshot = %q, want %q", got, snapshot)
+	}
+	if got := ReviewCriteria(nil, "- Loads\n- Works"); !reflect.DeepEqual(got, snapshot) {
+		t.Fatalf("without snapshot = %q, want %q", got, snapshot)
+	}
+}
+
+func TestCompleteReview(t *testing.T) {
+	criteria := []string{"Loads", "Works"}
+	comment := "No error state"
+	cases := []struct {
+		name    string
+		results []CriterionReview
+		approve bool
+		wantErr bool
+	}{
+		{"approval without results passes all", nil, true, false},
+		{"approval with all passed", []CriterionReview{{CriterionIndex: 1, Result: CriterionPass}, {CriterionIndex: 0, Result: CriterionPass}}, true, false},
+		{"approval cannot fail", []CriterionReview{{CriterionIndex: 0, Result: CriterionFail}}, true, true},
+		{"rejection lists every criterion", []CriterionReview{{CriterionIndex: 0, Result: CriterionFail}}, false, true},
+		{"rejection fails one", []CriterionReview{{CriterionIndex: 0, Result: CriterionFail, Comment: &comment}, {CriterionIndex: 1, Result: CriterionPass}}, false, false},
+		{"rejection must fail one", []CriterionReview{{CriterionIndex: 0, Result: CriterionPass}, {CriterionIndex: 1, Result: CriterionPass}}, false, true},
+		{"out of range", []CriterionReview{{CriterionIndex: 2, Result: CriterionPass}}, true, true},
+		{"reviewed twice", []CriterionReview{{CriterionIndex: 0, Result: CriterionPass}, {CriterionIndex: 0, Result: CriterionPass}}, true, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			review, err := CompleteReview(criteria, tc.results, tc.approve)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("review = %+v, want an error", review)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(review) != len(criteria) {
+				t.Fatalf("%d results, want %d", len(review), len(criteria))
+			}
+			for i, result := range review {
+				if result.CriterionIndex != i || result.Criterion != criteria[i] {
+					t.Errorf("result %d = %+v, want criterion %d %q", i, result, i, criteria[i])
+				}
+			}
+		})
+	}
+}
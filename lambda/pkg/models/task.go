@@ -8,17 +8,24 @@ import (
 type Task struct {
 	TaskID          string    `json:"task_id"`
 	ContractTaskID  *int64    `json:"contract_task_id,omitempty"`
+	ChainID         *int64    `json:"chain_id,omitempty"`
+	EscrowAddress   *string   `json:"escrow_address,omitempty"`
 	ProjectID       string    `json:"project_id"`
 	CreatorDID      string    `json:"creator_did"`
 	ExecutorDID     *string   `json:"executor_did,omitempty"`
+	ExecutorAddress *string   `json:"executor_address,omitempty"` // Payout address set on chain
+	ParentTaskID    *string   `json:"parent_task_id,omitempty"`   // Set on subtasks
 	TaskName        string    `json:"task_name"`
 	TaskDescription string    `json:"task_description"`
 	AcceptanceCriteria string `json:"acceptance_criteria"`
 	RewardAmount    string    `json:"reward_amount"`
 	PaidAmount      string    `json:"paid_amount"`
 	Visibility      string    `json:"visibility"`
+	AssignmentMode  string    `json:"assignment_mode,omitempty"` // bidding or direct
 	Status          string    `json:"status"`
 	ProfessionTags  []string  `json:"profession_tags,omitempty"`
+	MilestoneBps    []int32   `json:"milestone_bps,omitempty"`
+	TemplateID      *string   `json:"template_id,omitempty"` // Template the task was created from
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 	CompletedAt     *time.Time `json:"completed_at,omitempty"`
@@ -32,22 +39,97 @@ type TaskBid struct {
 	BidderDID          string    `json:"bidder_did"`
 	BidMessage         *string   `json:"bid_message,omitempty"`
 	CreditScoreSnapshot int      `json:"credit_score_snapshot"`
+	ProposedReward     *string    `json:"proposed_reward,omitempty"`
+	EstimatedDeliveryDate *time.Time `json:"estimated_delivery_date,omitempty"`
+	ProposedMilestoneBps []int32  `json:"proposed_milestone_bps,omitempty"`
+	AttachmentURLs     []string   `json:"attachment_urls,omitempty"`
+	ProposedTeam       []BidTeamMember `json:"proposed_team,omitempty"` // Executor team the bidder leads
+	RewardSnapshot     *string    `json:"reward_snapshot,omitempty"` // Task reward the bid was placed against
 	Status             string    `json:"status"`
 	CreatedAt          time.Time `json:"created_at"`
 	UpdatedAt          time.Time `json:"updated_at"`
 }
 
+// TaskInvitation invites a user to an invite-only task
+type TaskInvitation struct {
+	InvitationID string     `json:"invitation_id"`
+	TaskID       string     `json:"task_id"`
+	InviteeDID   string     `json:"invitee_did"`
+	Status       string     `json:"status"`
+	CreatedAt    time.Time  `json:"created_at"`
+	RespondedAt  *time.Time `json:"responded_at,omitempty"`
+}
+
+// TaskTeamMember is one executor of a team task and their payout share
+type TaskTeamMember struct {
+	TaskID        string    `json:"task_id"`
+	MemberDID     string    `json:"member_did"`
+	PayoutAddress string    `json:"payout_address"`
+	Position      int       `json:"position"` // Order on the escrow; 0 is the lead
+	ShareBps      int       `json:"share_bps"`
+	PaidAmount    string    `json:"paid_amount"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TaskTemplate holds reusable task details for a project
+type TaskTemplate struct {
+	TemplateID         string    `json:"template_id"`
+	ProjectID          string    `json:"project_id"`
+	CreatorDID         string    `json:"creator_did"`
+	TemplateName       string    `json:"template_name"`
+	TaskDescription    string    `json:"task_description"`
+	AcceptanceCriteria string    `json:"acceptance_criteria"`
+	ProfessionTags     []string  `json:"profession_tags"`
+	MilestoneBps       []int32   `json:"milestone_bps,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// TaskEdit is one change to a task after creation
+type TaskEdit struct {
+	EditID    string                 `json:"edit_id"`
+	TaskID    string                 `json:"task_id"`
+	EditorDID string                 `json:"editor_did"`
+	Changes   map[string]FieldChange `json:"changes"`
+	TxHash    *string                `json:"tx_hash,omitempty"` // Escrow transaction for reward changes
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// FieldChange is a field's value before and after an edit
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// Notification is a message to a user about a task they take part in
+type Notification struct {
+	NotificationID string                 `json:"notification_id"`
+	UserDID        string                 `json:"user_did"`
+	TaskID         *string                `json:"task_id,omitempty"`
+	Type           string                 `json:"type"`
+	Payload        map[string]interface{} `json:"payload"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
+
+// Notification types
+const (
+	NotificationTaskRewardChanged = "task_reward_changed"
+)
+
 // TaskSubmission represents a work submission
 type TaskSubmission struct {
 	SubmissionID   string     `json:"submission_id"`
 	TaskID         string     `json:"task_id"`
 	SubmissionType string     `json:"submission_type"`
+	Revision       int        `json:"revision"` // 1 for the first attempt at a milestone
 	Content        string     `json:"content"`
 	FileURLs       []string   `json:"file_urls,omitempty"`
 	Status         string     `json:"status"`
 	RejectionReason *string   `json:"rejection_reason,omitempty"`
 	SubmittedAt    time.Time  `json:"submitted_at"`
 	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	Review         []CriterionReview  `json:"review,omitempty"`  // Per-criterion results once reviewed
+	Changes        *SubmissionChanges `json:"changes,omitempty"` // Against the previous revision
 }
 
 // CreditHistory represents credit score changes
@@ -66,6 +148,7 @@ type CreditHistory struct {
 type User struct {
 	DID            string  `json:"did"`
 	EthAddress     string  `json:"eth_address"`
+	PayoutAddress  *string `json:"payout_address,omitempty"` // Verified external wallet for payouts
 	Username       string  `json:"username"`
 	Email          string  `json:"email"`
 	CreditScore    int     `json:"credit_score"`
@@ -79,6 +162,7 @@ type User struct {
 // TaskStatus constants
 const (
 	TaskStatusPending                  = "pending"
+	TaskStatusBlocked                  = "blocked" // Waiting on prerequisites before bidding opens
 	TaskStatusBidding                  = "bidding"
 	TaskStatusAccepted                 = "accepted"
 	TaskStatusDesignSubmitted          = "design_submitted"
@@ -90,6 +174,26 @@ const (
 	TaskStatusCancelled                = "cancelled"
 )
 
+// Visibility constants
+const (
+	VisibilityProject = "project"
+	VisibilityGlobal  = "global"
+	VisibilityInvite  = "invite" // Only invited users may bid or be assigned
+)
+
+// AssignmentMode constants
+const (
+	AssignmentBidding = "bidding" // Executor chosen from bids
+	AssignmentDirect  = "direct"  // Executor set at creation
+)
+
+// InvitationStatus constants
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusDeclined = "declined"
+)
+
 // SubmissionType constants
 const (
 	SubmissionTypeDesign         = "design"
@@ -102,6 +206,16 @@ const (
 	BidStatusPending  = "pending"
 	BidStatusAccepted = "accepted"
 	BidStatusRejected = "rejected"
+	BidStatusWithdrawn = "withdrawn"
+)
+
+// BidRevision actions recorded in task_bid_revisions
+const (
+	BidRevisionCreated     = "created"
+	BidRevisionUpdated     = "updated"
+	BidRevisionWithdrawn   = "withdrawn"
+	BidRevisionResubmitted = "resubmitted"
+	BidRevisionRewardChanged = "reward_changed" // Task reward changed under a pending bid
 )
 
 // Milestone payment percentages (in basis points, 10000 = 100%)
@@ -110,3 +224,42 @@ const (
 	MilestoneImplementation = 5000 // 50% (cumulative 80%)
 	MilestoneFinal          = 2000 // 20% (cumulative 100%)
 )
+
+// Milestones in schedule order; a task's milestone_bps has one entry per milestone
+var Milestones = []string{"design", "implementation", "final"}
+
+// DefaultMilestoneBps is the schedule for tasks created without one
+var DefaultMilestoneBps = []uint16{MilestoneDesign, MilestoneImplementation, MilestoneFinal}
+
+// MilestoneIndex returns the schedule index of a milestone ("design",
+// "implementation", "final"), or -1 if unknown
+func MilestoneIndex(milestone string) int {
+	for i, m := range Milestones {
+		if m == milestone {
+			return i
+		}
+	}
+	return -1
+}
+
+// MilestoneSchedule returns a task's stored milestone_bps, or the default
+// for tasks created before schedules were stored
+func MilestoneSchedule(stored []int32) []uint16 {
+	if len(stored) != len(Milestones) {
+		return DefaultMilestoneBps
+	}
+	bps := make([]uint16, len(stored))
+	for i, share := range stored {
+		bps[i] = uint16(share)
+	}
+	return bps
+}
+
+// MilestoneColumn converts a schedule for the milestone_bps INT[] column
+func MilestoneColumn(bps []uint16) []int32 {
+	column := make([]int32, len(bps))
+	for i, share := range bps {
+		column[i] = int32(share)
+	}
+	return column
+}
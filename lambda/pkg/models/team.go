@@ -0,0 +1,51 @@
+package models
+
+import "fmt"
+
+// BidTeamMember is one member of the executor team a bidder proposes with
+// their bid, with their share of each executor payment
+type BidTeamMember struct {
+	DID      string `json:"did"`
+	ShareBps uint16 `json:"share_bps"`
+}
+
+// LeadFirst checks a proposed team names each member once and includes the
+// lead, and returns it with the lead first, the order the escrow pays in
+func LeadFirst(lead string, team []BidTeamMember) ([]BidTeamMember, error) {
+	ordered := make([]BidTeamMember, 0, len(team))
+	seen := make(map[string]bool)
+	for _, member := range team {
+		if seen[member.DID] {
+			return nil, fmt.Errorf("duplicate team member: %s", member.DID)
+		}
+		seen[member.DID] = true
+		if member.DID == lead {
+			ordered = append([]BidTeamMember{member}, ordered...)
+		} else {
+			ordered = append(ordered, member)
+		}
+	}
+	if !seen[lead] {
+		return nil, fmt.Errorf("team must include the bidder")
+	}
+	return ordered, nil
+}
+
+// SameTeam reports whether two teams have the same members and shares, in any order
+func SameTeam(a, b []BidTeamMember) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	shares := make(map[string]uint16, len(a))
+	for _, member := range a {
+		shares[member.DID] = member.ShareBps
+	}
+	for _, member := range b {
+		share, ok := shares[member.DID]
+		if !ok || share != member.ShareBps {
+			return false
+		}
+		delete(shares, member.DID)
+	}
+	return true
+}
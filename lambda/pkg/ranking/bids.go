@@ -0,0 +1,165 @@
+package ranking
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// Factor weights; a bid's total score is out of 100
+const (
+	WeightCreditScore = 30
+	WeightReliability = 20
+	WeightTagOverlap  = 20
+	WeightOnTime      = 15
+	WeightPrice       = 15
+)
+
+// MaxCreditScore is the credit score that earns the full credit factor
+const MaxCreditScore = 10000
+
+// Task is what the ranking needs to know about the task being bid on
+type Task struct {
+	ProfessionTags []string
+	Reward         *big.Int // Locked reward in wei
+}
+
+// Bid is one bid and its bidder's track record
+type Bid struct {
+	BidID            string
+	CreditScore      int
+	TasksCompleted   int
+	TasksCancelled   int
+	ProfessionTags   []string
+	OnTimeDeliveries int      // Completed tasks delivered by the accepted bid's date
+	DatedDeliveries  int      // Completed tasks whose accepted bid had a delivery date
+	ProposedReward   *big.Int // nil when the bidder takes the task reward
+}
+
+// Factor is one part of a score and why it got its points
+type Factor struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+	Value  float64 `json:"value"` // 0 to 1
+	Points float64 `json:"points"`
+	Detail string  `json:"detail"`
+}
+
+// Score is a bid's total (0 to 100) with its breakdown
+type Score struct {
+	Total   float64  `json:"total"`
+	Factors []Factor `json:"factors"`
+}
+
+// ScoreBid scores a bid from credit score, completion record, tag overlap,
+// on-time delivery history and price
+func ScoreBid(task Task, bid Bid) Score {
+	factors := []Factor{
+		creditFactor(bid),
+		reliabilityFactor(bid),
+		tagFactor(task, bid),
+		onTimeFactor(bid),
+		priceFactor(task, bid),
+	}
+
+	var total float64
+	for i := range factors {
+		factors[i].Value = round(factors[i].Value, 4)
+		factors[i].Points = round(factors[i].Weight*factors[i].Value, 2)
+		total += factors[i].Points
+	}
+	return Score{Total: round(total, 2), Factors: factors}
+}
+
+// Rank returns the bids' scores and their order, best first; ties keep input order
+func Rank(task Task, bids []Bid) ([]Score, []int) {
+	scores := make([]Score, len(bids))
+	order := make([]int, len(bids))
+	for i, bid := range bids {
+		scores[i] = ScoreBid(task, bid)
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]].Total > scores[order[b]].Total
+	})
+	return scores, order
+}
+
+func creditFactor(bid Bid) Factor {
+	value := clamp(float64(bid.CreditScore) / MaxCreditScore)
+	return Factor{
+		Name:   "credit_score",
+		Weight: WeightCreditScore,
+		Value:  value,
+		Detail: fmt.Sprintf("credit score %d of %d", bid.CreditScore, MaxCreditScore),
+	}
+}
+
+// reliabilityFactor smooths the completion ratio so a new user starts at 0.5
+func reliabilityFactor(bid Bid) Factor {
+	finished := bid.TasksCompleted + bid.TasksCancelled
+	value := float64(bid.TasksCompleted+1) / float64(finished+2)
+	return Factor{
+		Name:   "reliability",
+		Weight: WeightReliability,
+		Value:  value,
+		Detail: fmt.Sprintf("%d completed, %d cancelled", bid.TasksCompleted, bid.TasksCancelled),
+	}
+}
+
+// tagFactor is the share of the task's tags the bidder has; neutral for untagged tasks
+func tagFactor(task Task, bid Bid) Factor {
+	if len(task.ProfessionTags) == 0 {
+		return Factor{Name: "tag_overlap", Weight: WeightTagOverlap, Value: 0.5, Detail: "task has no profession tags"}
+	}
+
+	matched := matchTags(task.ProfessionTags, bid.ProfessionTags)
+	detail := fmt.Sprintf("%d of %d task tags", len(matched), len(task.ProfessionTags))
+	if len(matched) > 0 {
+		detail += " (" + strings.Join(matched, ", ") + ")"
+	}
+	return Factor{
+		Name:   "tag_overlap",
+		Weight: WeightTagOverlap,
+		Value:  float64(len(matched)) / float64(len(task.ProfessionTags)),
+		Detail: detail,
+	}
+}
+
+// onTimeFactor smooths the on-time ratio so a bidder without dated deliveries starts at 0.5
+func onTimeFactor(bid Bid) Factor {
+	value := float64(bid.OnTimeDeliveries+1) / float64(bid.DatedDeliveries+2)
+	detail := "no dated deliveries yet"
+	if bid.DatedDeliveries > 0 {
+		detail = fmt.Sprintf("%d of %d deliveries on time", bid.OnTimeDeliveries, bid.DatedDeliveries)
+	}
+	return Factor{Name: "on_time_delivery", Weight: WeightOnTime, Value: value, Detail: detail}
+}
+
+// priceFactor is the share of the reward the bid would save
+func priceFactor(task Task, bid Bid) Factor {
+	factor := Factor{Name: "price", Weight: WeightPrice, Detail: "asks the full reward"}
+	if bid.ProposedReward == nil || task.Reward == nil || task.Reward.Sign() <= 0 {
+		return factor
+	}
+
+	saved := new(big.Int).Sub(task.Reward, bid.ProposedReward)
+	if saved.Sign() <= 0 {
+		return factor
+	}
+	ratio, _ := new(big.Rat).SetFrac(saved, task.Reward).Float64()
+	factor.Value = clamp(ratio)
+	factor.Detail = fmt.Sprintf("%.1f%% below the reward", ratio*100)
+	return factor
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+func round(v float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(v*scale) / scale
+}
@@ -0,0 +1,96 @@
+package ranking
+
+import (
+	"math/big"
+	"testing"
+)
+
+// factor returns the named factor of a score
+func factor(t *testing.T, score Score, name string) Factor {
+	t.Helper()
+	for _, f := range score.Factors {
+		if f.Name == name {
+			return f
+		}
+	}
+	t.Fatalf("score has no %s factor", name)
+	return Factor{}
+}
+
+func TestOnTimeFactor(t *testing.T) {
+	cases := []struct {
+		name   string
+		onTime int
+		dated  int
+		value  float64
+		points float64
+		detail string
+	}{
+		{"no history", 0, 0, 0.5, 7.5, "no dated deliveries yet"},
+		{"always on time", 4, 4, 0.8333, 12.5, "4 of 4 deliveries on time"},
+		{"always late", 0, 4, 0.1667, 2.5, "0 of 4 deliveries on time"},
+		{"half on time", 3, 6, 0.5, 7.5, "3 of 6 deliveries on time"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			score := ScoreBid(Task{}, Bid{OnTimeDeliveries: tc.onTime, DatedDeliveries: tc.dated})
+			f := factor(t, score, "on_time_delivery")
+			if f.Weight != WeightOnTime {
+				t.Errorf("weight = %v, want %v", f.Weight, WeightOnTime)
+			}
+			if f.Value != tc.value || f.Points != tc.points {
+				t.Errorf("value, points = %v, %v; want %v, %v", f.Value, f.Points, tc.value, tc.points)
+			}
+			if f.Detail != tc.detail {
+				t.Errorf("detail = %q, want %q", f.Detail, tc.detail)
+			}
+		})
+	}
+}
+
+func TestScoreBidTotal(t *testing.T) {
+	task := Task{ProfessionTags: []string{"go", "solidity"}, Reward: big.NewInt(100)}
+	bid := Bid{
+		CreditScore:      5000,
+		TasksCompleted:   8,
+		TasksCancelled:   0,
+		ProfessionTags:   []string{"Go"},
+		OnTimeDeliveries: 8,
+		DatedDeliveries:  8,
+		ProposedReward:   big.NewInt(80),
+	}
+
+	score := ScoreBid(task, bid)
+	// 15 credit + 18 reliability + 10 tags + 13.5 on time + 3 price
+	if score.Total != 59.5 {
+		t.Fatalf("total = %v, want 59.5: %+v", score.Total, score.Factors)
+	}
+	if len(score.Factors) != 5 {
+		t.Fatalf("%d factors, want 5", len(score.Factors))
+	}
+}
+
+func TestRankPrefersOnTimeDelivery(t *testing.T) {
+	task := Task{Reward: big.NewInt(100)}
+	late := Bid{BidID: "late", CreditScore: 3000, TasksCompleted: 5, OnTimeDeliveries: 0, DatedDeliveries: 5}
+	punctual := Bid{BidID: "punctual", CreditScore: 3000, TasksCompleted: 5, OnTimeDeliveries: 5, DatedDeliveries: 5}
+	undated := Bid{BidID: "undated", CreditScore: 3000, TasksCompleted: 5}
+
+	_, order := Rank(task, []Bid{late, punctual, undated})
+	want := []int{1, 2, 0}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestRankKeepsInputOrderOnTies(t *testing.T) {
+	bids := []Bid{{BidID: "a"}, {BidID: "b"}, {BidID: "c"}}
+	_, order := Rank(Task{}, bids)
+	for i := range order {
+		if order[i] != i {
+			t.Fatalf("order = %v, want input order", order)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package ranking
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/x-zero/xz-wallet/pkg/blockchain"
+)
+
+// Task recommendation weights; a task's total score is out of 100
+const (
+	WeightProfileTags = 40
+	WeightHistoryTags = 25
+	WeightCreditTier  = 20
+	WeightFreshness   = 15
+)
+
+// FreshnessHalfLife is the task age at which the freshness factor drops to 0.5
+const FreshnessHalfLife = 7 * 24 * time.Hour
+
+// CreditTier is the largest reward suggested to users at or above a credit score
+type CreditTier struct {
+	Name           string
+	MinCreditScore int
+	MaxReward      string // XZT; empty for no ceiling
+}
+
+// CreditTiers in ascending score order
+var CreditTiers = []CreditTier{
+	{Name: "new", MinCreditScore: 0, MaxReward: "100"},
+	{Name: "bronze", MinCreditScore: 3000, MaxReward: "500"},
+	{Name: "silver", MinCreditScore: 5000, MaxReward: "2000"},
+	{Name: "gold", MinCreditScore: 7000, MaxReward: ""},
+}
+
+// TierFor returns the credit tier for a score
+func TierFor(creditScore int) CreditTier {
+	tier := CreditTiers[0]
+	for _, t := range CreditTiers {
+		if creditScore >= t.MinCreditScore {
+			tier = t
+		}
+	}
+	return tier
+}
+
+// Executor is what the feed knows about the user asking for work
+type Executor struct {
+	CreditScore    int
+	ProfessionTags []string
+	CompletedTags  []string // Profession tags of tasks the user completed, repeats allowed
+}
+
+// OpenTask is a task the executor could bid on
+type OpenTask struct {
+	TaskID         string
+	ProfessionTags []string
+	Reward         *big.Int // wei
+	CreatedAt      time.Time
+}
+
+// ScoreTask scores how well an open task suits an executor at time now
+func ScoreTask(executor Executor, task OpenTask, now time.Time) Score {
+	factors := []Factor{
+		profileTagFactor(executor, task),
+		historyTagFactor(executor, task),
+		creditTierFactor(executor, task),
+		freshnessFactor(task, now),
+	}
+
+	var total float64
+	for i := range factors {
+		factors[i].Value = round(factors[i].Value, 4)
+		factors[i].Points = round(factors[i].Weight*factors[i].Value, 2)
+		total += factors[i].Points
+	}
+	return Score{Total: round(total, 2), Factors: factors}
+}
+
+// RecommendTasks returns the tasks' scores and their order: best score first,
+// then newest, then by task id, so equal inputs always give the same order
+func RecommendTasks(executor Executor, tasks []OpenTask, now time.Time) ([]Score, []int) {
+	scores := make([]Score, len(tasks))
+	order := make([]int, len(tasks))
+	for i, task := range tasks {
+		scores[i] = ScoreTask(executor, task, now)
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		x, y := order[a], order[b]
+		if scores[x].Total != scores[y].Total {
+			return scores[x].Total > scores[y].Total
+		}
+		if !tasks[x].CreatedAt.Equal(tasks[y].CreatedAt) {
+			return tasks[x].CreatedAt.After(tasks[y].CreatedAt)
+		}
+		return tasks[x].TaskID < tasks[y].TaskID
+	})
+	return scores, order
+}
+
+// profileTagFactor is the share of the task's tags on the executor's profile
+func profileTagFactor(executor Executor, task OpenTask) Factor {
+	factor := Factor{Name: "profile_tags", Weight: WeightProfileTags}
+	if len(task.ProfessionTags) == 0 {
+		factor.Value = 0.25
+		factor.Detail = "task has no profession tags"
+		return factor
+	}
+
+	matched := matchTags(task.ProfessionTags, executor.ProfessionTags)
+	factor.Value = float64(len(matched)) / float64(len(task.ProfessionTags))
+	factor.Detail = fmt.Sprintf("%d of %d task tags on your profile", len(matched), len(task.ProfessionTags))
+	if len(matched) > 0 {
+		factor.Detail += " (" + strings.Join(matched, ", ") + ")"
+	}
+	return factor
+}
+
+// historyTagFactor is the share of the task's tags the executor has completed work in
+func historyTagFactor(executor Executor, task OpenTask) Factor {
+	factor := Factor{Name: "past_completions", Weight: WeightHistoryTags, Detail: "no completed tasks with these tags"}
+	if len(task.ProfessionTags) == 0 || len(executor.CompletedTags) == 0 {
+		return factor
+	}
+
+	matched := matchTags(task.ProfessionTags, executor.CompletedTags)
+	factor.Value = float64(len(matched)) / float64(len(task.ProfessionTags))
+	if len(matched) > 0 {
+		factor.Detail = "completed tasks tagged " + strings.Join(matched, ", ")
+	}
+	return factor
+}
+
+// creditTierFactor is full while the reward is within the executor's tier and shrinks above it
+func creditTierFactor(executor Executor, task OpenTask) Factor {
+	tier := TierFor(executor.CreditScore)
+	factor := Factor{Name: "credit_tier", Weight: WeightCreditTier, Value: 1}
+	if tier.MaxReward == "" || task.Reward == nil || task.Reward.Sign() <= 0 {
+		factor.Detail = fmt.Sprintf("%s tier has no reward ceiling", tier.Name)
+		return factor
+	}
+
+	ceiling, err := blockchain.ToWei(tier.MaxReward)
+	if err != nil || task.Reward.Cmp(ceiling) <= 0 {
+		factor.Detail = fmt.Sprintf("reward within %s tier (up to %s XZT)", tier.Name, tier.MaxReward)
+		return factor
+	}
+	factor.Value, _ = new(big.Rat).SetFrac(ceiling, task.Reward).Float64()
+	factor.Detail = fmt.Sprintf("reward above %s tier ceiling of %s XZT", tier.Name, tier.MaxReward)
+	return factor
+}
+
+// freshnessFactor is 1 for a new task and 0.5 at FreshnessHalfLife
+func freshnessFactor(task OpenTask, now time.Time) Factor {
+	age := now.Sub(task.CreatedAt)
+	if age < 0 {
+		age = 0
+	}
+	value := 1 / (1 + float64(age)/float64(FreshnessHalfLife))
+	return Factor{
+		Name:   "freshness",
+		Weight: WeightFreshness,
+		Value:  value,
+		Detail: fmt.Sprintf("posted %d days ago", int(age/(24*time.Hour))),
+	}
+}
+
+// matchTags returns the task tags found in have, case-insensitively
+func matchTags(taskTags, have []string) []string {
+	set := make(map[string]bool, len(have))
+	for _, tag := range have {
+		set[strings.ToLower(tag)] = true
+	}
+	var matched []string
+	for _, tag := range taskTags {
+		if set[strings.ToLower(tag)] {
+			matched = append(matched, tag)
+		}
+	}
+	return matched
+}
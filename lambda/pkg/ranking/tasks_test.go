@@ -0,0 +1,192 @@
+package ranking
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestScoreTaskFactors(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	executor := Executor{
+		CreditScore:    3500, // bronze, up to 500 XZT
+		ProfessionTags: []string{"Go", "SQL"},
+		CompletedTags:  []string{"go", "go", "react"},
+	}
+
+	cases := []struct {
+		name   string
+		task   OpenTask
+		values map[string]float64
+	}{
+		{
+			name: "full match",
+			task: OpenTask{ProfessionTags: []string{"go", "sql"}, Reward: xztAmount(100), CreatedAt: now},
+			values: map[string]float64{
+				"profile_tags": 1, "past_completions": 0.5, "credit_tier": 1, "freshness": 1,
+			},
+		},
+		{
+			name: "untagged",
+			task: OpenTask{Reward: xztAmount(100), CreatedAt: now},
+			values: map[string]float64{
+				"profile_tags": 0.25, "past_completions": 0, "credit_tier": 1, "freshness": 1,
+			},
+		},
+		{
+			name: "reward above tier, a half-life old",
+			task: OpenTask{ProfessionTags: []string{"rust"}, Reward: xztAmount(1000), CreatedAt: now.Add(-FreshnessHalfLife)},
+			values: map[string]float64{
+				"profile_tags": 0, "past_completions": 0, "credit_tier": 0.5, "freshness": 0.5,
+			},
+		},
+		{
+			name: "created in the future",
+			task: OpenTask{ProfessionTags: []string{"react"}, CreatedAt: now.Add(time.Hour)},
+			values: map[string]float64{
+				"profile_tags": 0, "past_completions": 1, "credit_tier": 1, "freshness": 1,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			score := ScoreTask(executor, tc.task, now)
+			var total float64
+			for name, want := range tc.values {
+				f := factor(t, score, name)
+				if f.Value != want {
+					t.Errorf("%s = %v, want %v (%s)", name, f.Value, want, f.Detail)
+				}
+				total += f.Points
+			}
+			if round(total, 2) != score.Total {
+				t.Errorf("total = %v, factors add up to %v", score.Total, total)
+			}
+		})
+	}
+}
+
+func TestTierFor(t *testing.T) {
+	cases := []struct {
+		score int
+		tier  string
+	}{
+		{0, "new"},
+		{2999, "new"},
+		{3000, "bronze"},
+		{5000, "silver"},
+		{6999, "silver"},
+		{7000, "gold"},
+		{10000, "gold"},
+	}
+	for _, tc := range cases {
+		if got := TierFor(tc.score).Name; got != tc.tier {
+			t.Errorf("TierFor(%d) = %s, want %s", tc.score, got, tc.tier)
+		}
+	}
+}
+
+func TestRecommendTasksOrder(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	executor := Executor{CreditScore: 5000, ProfessionTags: []string{"go"}}
+
+	cases := []struct {
+		name  string
+		tasks []OpenTask
+		want  []string
+	}{
+		{
+			name: "best score first",
+			tasks: []OpenTask{
+				{TaskID: "other-tags", ProfessionTags: []string{"rust"}, CreatedAt: now},
+				{TaskID: "match", ProfessionTags: []string{"go"}, CreatedAt: now},
+				{TaskID: "half-match", ProfessionTags: []string{"go", "rust"}, CreatedAt: now},
+			},
+			want: []string{"match", "half-match", "other-tags"},
+		},
+		{
+			name: "match beats freshness",
+			tasks: []OpenTask{
+				{TaskID: "new-other", ProfessionTags: []string{"rust"}, CreatedAt: now},
+				{TaskID: "old-match", ProfessionTags: []string{"go"}, CreatedAt: now.Add(-30 * 24 * time.Hour)},
+			},
+			want: []string{"old-match", "new-other"},
+		},
+		{
+			name: "equal scores: newest first",
+			tasks: []OpenTask{
+				{TaskID: "a", ProfessionTags: []string{"go"}, CreatedAt: now.Add(-time.Second)},
+				{TaskID: "b", ProfessionTags: []string{"go"}, CreatedAt: now},
+			},
+			want: []string{"b", "a"},
+		},
+		{
+			name: "equal scores and age: by task id",
+			tasks: []OpenTask{
+				{TaskID: "c", ProfessionTags: []string{"go"}, CreatedAt: now},
+				{TaskID: "a", ProfessionTags: []string{"go"}, CreatedAt: now},
+				{TaskID: "b", ProfessionTags: []string{"go"}, CreatedAt: now},
+			},
+			want: []string{"a", "b", "c"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, order := RecommendTasks(executor, tc.tasks, now)
+			if got := taskIDs(tc.tasks, order); !equalStrings(got, tc.want) {
+				t.Fatalf("order = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRecommendTasksStableForEqualScores(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	tasks := []OpenTask{
+		{TaskID: "d", CreatedAt: now},
+		{TaskID: "b", CreatedAt: now},
+		{TaskID: "a", CreatedAt: now},
+		{TaskID: "c", CreatedAt: now},
+	}
+	want := []string{"a", "b", "c", "d"}
+
+	// Every rotation of the input gives the same output
+	for shift := range tasks {
+		rotated := append(append([]OpenTask{}, tasks[shift:]...), tasks[:shift]...)
+		scores, order := RecommendTasks(Executor{}, rotated, now)
+		if got := taskIDs(rotated, order); !equalStrings(got, want) {
+			t.Fatalf("rotation %d: order = %v, want %v", shift, got, want)
+		}
+		for i := range scores {
+			if scores[i].Total != scores[0].Total {
+				t.Fatalf("rotation %d: scores differ: %v vs %v", shift, scores[i].Total, scores[0].Total)
+			}
+		}
+	}
+}
+
+// xztAmount converts whole tokens to wei
+func xztAmount(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+}
+
+// taskIDs lists the tasks' IDs in order
+func taskIDs(tasks []OpenTask, order []int) []string {
+	ids := make([]string, len(order))
+	for i, index := range order {
+		ids[i] = tasks[index].TaskID
+	}
+	return ids
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -6,6 +6,17 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 )
 
+// AllowHeaders lists the request headers accepted by the API (CORS)
+const AllowHeaders = "Content-Type,Authorization,Idempotency-Key"
+
+// AllowMethods lists the HTTP methods the API serves (CORS)
+const AllowMethods = "GET,POST,PUT,PATCH,DELETE,OPTIONS"
+
+// RetryableHeader marks a 5xx response from a request that changed nothing,
+// e.g. one that failed before submitting a transaction. Only such responses
+// free an Idempotency-Key for another attempt.
+const RetryableHeader = "X-Retryable"
+
 // Response represents API response structure
 type Response struct {
 	Success bool        `json:"success"`
@@ -29,8 +40,8 @@ func Success(data interface{}) (events.APIGatewayProxyResponse, error) {
 		Headers: map[string]string{
 			"Content-Type":                 "application/json",
 			"Access-Control-Allow-Origin":  "*",
-			"Access-Control-Allow-Headers": "Content-Type,Authorization",
-			"Access-Control-Allow-Methods": "GET,POST,PUT,DELETE,OPTIONS",
+			"Access-Control-Allow-Headers": AllowHeaders,
+			"Access-Control-Allow-Methods": AllowMethods,
 		},
 		Body: string(body),
 	}, nil
@@ -51,8 +62,8 @@ func SuccessWithMessage(message string) (events.APIGatewayProxyResponse, error)
 		Headers: map[string]string{
 			"Content-Type":                 "application/json",
 			"Access-Control-Allow-Origin":  "*",
-			"Access-Control-Allow-Headers": "Content-Type,Authorization",
-			"Access-Control-Allow-Methods": "GET,POST,PUT,DELETE,OPTIONS",
+			"Access-Control-Allow-Headers": AllowHeaders,
+			"Access-Control-Allow-Methods": AllowMethods,
 		},
 		Body: string(body),
 	}, nil
@@ -70,9 +81,33 @@ func Error(statusCode int, message string) (events.APIGatewayProxyResponse, erro
 		Headers: map[string]string{
 			"Content-Type":                 "application/json",
 			"Access-Control-Allow-Origin":  "*",
-			"Access-Control-Allow-Headers": "Content-Type,Authorization",
-			"Access-Control-Allow-Methods": "GET,POST,PUT,DELETE,OPTIONS",
+			"Access-Control-Allow-Headers": AllowHeaders,
+			"Access-Control-Allow-Methods": AllowMethods,
 		},
 		Body: string(body),
 	}, nil
 }
+
+// RetryableError is Error for failures that happened before anything was
+// submitted on chain or committed, so the client may retry with the same
+// Idempotency-Key
+func RetryableError(statusCode int, message string) (events.APIGatewayProxyResponse, error) {
+	resp, err := Error(statusCode, message)
+	resp.Headers[RetryableHeader] = "true"
+	return resp, err
+}
+
+// CSV returns a CSV file download response
+func CSV(filename string, body string) (events.APIGatewayProxyResponse, error) {
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+		Headers: map[string]string{
+			"Content-Type":                 "text/csv; charset=utf-8",
+			"Content-Disposition":          "attachment; filename=\"" + filename + "\"",
+			"Access-Control-Allow-Origin":  "*",
+			"Access-Control-Allow-Headers": AllowHeaders,
+			"Access-Control-Allow-Methods": AllowMethods,
+		},
+		Body: body,
+	}, nil
+}
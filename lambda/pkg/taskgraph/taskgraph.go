@@ -0,0 +1,104 @@
+// Package taskgraph handles subtask and dependency relations between tasks
+package taskgraph
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/x-zero/xz-wallet/pkg/db"
+)
+
+// DependencyCycle reports whether making taskID depend on dependsOn would
+// close a loop, i.e. dependsOn already depends on taskID directly or not
+func DependencyCycle(ctx context.Context, q db.Querier, taskID, dependsOn string) (bool, error) {
+	if taskID == dependsOn {
+		return true, nil
+	}
+	var cycle bool
+	err := q.QueryRow(ctx, `
+		WITH RECURSIVE prerequisites AS (
+			SELECT depends_on_task_id FROM task_dependencies WHERE task_id = $1
+			UNION
+			SELECT d.depends_on_task_id
+			FROM task_dependencies d JOIN prerequisites p ON d.task_id = p.depends_on_task_id
+		)
+		SELECT EXISTS (SELECT 1 FROM prerequisites WHERE depends_on_task_id = $2)
+	`, dependsOn, taskID).Scan(&cycle)
+	return cycle, err
+}
+
+// ParentCycle reports whether making parentID the parent of taskID would
+// make a task its own ancestor
+func ParentCycle(ctx context.Context, q db.Querier, taskID, parentID string) (bool, error) {
+	if taskID == parentID {
+		return true, nil
+	}
+	var cycle bool
+	err := q.QueryRow(ctx, `
+		WITH RECURSIVE ancestors AS (
+			SELECT parent_task_id FROM tasks WHERE task_id = $1
+			UNION
+			SELECT t.parent_task_id FROM tasks t JOIN ancestors a ON t.task_id = a.parent_task_id
+		)
+		SELECT EXISTS (SELECT 1 FROM ancestors WHERE parent_task_id = $2)
+	`, parentID, taskID).Scan(&cycle)
+	return cycle, err
+}
+
+// UnmetDependencies counts a task's prerequisites that are not completed
+func UnmetDependencies(ctx context.Context, q db.Querier, taskID string) (int, error) {
+	var unmet int
+	err := q.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM task_dependencies d JOIN tasks t ON t.task_id = d.depends_on_task_id
+		WHERE d.task_id = $1 AND t.status != 'completed'
+	`, taskID).Scan(&unmet)
+	return unmet, err
+}
+
+// OpenDependents moves blocked tasks that depend on taskID to bidding once
+// all their prerequisites are completed. Returns the opened task IDs.
+func OpenDependents(ctx context.Context, q db.Querier, taskID string) ([]string, error) {
+	rows, err := q.Query(ctx, `
+		UPDATE tasks t
+		SET status = 'bidding', updated_at = CURRENT_TIMESTAMP
+		WHERE t.status = 'blocked'
+		  AND t.task_id IN (SELECT task_id FROM task_dependencies WHERE depends_on_task_id = $1)
+		  AND NOT EXISTS (
+		      SELECT 1 FROM task_dependencies d JOIN tasks p ON p.task_id = d.depends_on_task_id
+		      WHERE d.task_id = t.task_id AND p.status != 'completed'
+		  )
+		RETURNING t.task_id
+	`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	opened := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		opened = append(opened, id)
+	}
+	return opened, rows.Err()
+}
+
+// LockProject serializes relation changes within a project until the
+// transaction ends, so concurrent edits cannot close a cycle between them
+func LockProject(ctx context.Context, tx pgx.Tx, projectID string) error {
+	_, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext('task_graph:' || $1::text))", projectID)
+	return err
+}
+
+// Editable reports whether a task's relations may still change: no executor
+// has been assigned and the task is not closed
+func Editable(status string) bool {
+	switch status {
+	case "pending", "blocked", "bidding":
+		return true
+	}
+	return false
+}
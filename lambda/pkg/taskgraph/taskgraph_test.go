@@ -0,0 +1,213 @@
+package taskgraph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/x-zero/xz-wallet/pkg/dbtest"
+)
+
+// graph is a project in a test database with helpers to add tasks and edges
+type graph struct {
+	t       *testing.T
+	tx      pgx.Tx
+	project string
+}
+
+func newGraph(t *testing.T) *graph {
+	t.Helper()
+
+	tx := dbtest.Begin(t)
+	ctx := context.Background()
+	if _, err := tx.Exec(ctx, `INSERT INTO users (did, username) VALUES ('did:example:creator', 'creator')`); err != nil {
+		t.Fatal(err)
+	}
+	var project string
+	err := tx.QueryRow(ctx, `INSERT INTO projects (project_name, owner_did) VALUES ('graph', 'did:example:creator') RETURNING project_id`).Scan(&project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &graph{t: t, tx: tx, project: project}
+}
+
+// task adds a task with the given status and returns its ID
+func (g *graph) task(status string) string {
+	g.t.Helper()
+
+	var id string
+	err := g.tx.QueryRow(context.Background(), `
+		INSERT INTO tasks (project_id, creator_did, task_name, task_description, acceptance_criteria,
+			reward_amount, visibility, status)
+		VALUES ($1, 'did:example:creator', 'task', 'description', 'criteria', 10, 'project', $2)
+		RETURNING task_id
+	`, g.project, status).Scan(&id)
+	if err != nil {
+		g.t.Fatal(err)
+	}
+	return id
+}
+
+// dependsOn records that taskID waits for dependsOn
+func (g *graph) dependsOn(taskID, dependsOn string) {
+	g.t.Helper()
+
+	_, err := g.tx.Exec(context.Background(), `
+		INSERT INTO task_dependencies (task_id, depends_on_task_id) VALUES ($1, $2)
+	`, taskID, dependsOn)
+	if err != nil {
+		g.t.Fatal(err)
+	}
+}
+
+func (g *graph) setStatus(taskID, status string) {
+	g.t.Helper()
+
+	if _, err := g.tx.Exec(context.Background(), `UPDATE tasks SET status = $1 WHERE task_id = $2`, status, taskID); err != nil {
+		g.t.Fatal(err)
+	}
+}
+
+func (g *graph) status(taskID string) string {
+	g.t.Helper()
+
+	var status string
+	if err := g.tx.QueryRow(context.Background(), `SELECT status FROM tasks WHERE task_id = $1`, taskID).Scan(&status); err != nil {
+		g.t.Fatal(err)
+	}
+	return status
+}
+
+func TestDependencyCycleSelf(t *testing.T) {
+	// Decided without a query
+	cycle, err := DependencyCycle(context.Background(), nil, "task", "task")
+	if err != nil || !cycle {
+		t.Fatalf("cycle, err = %v, %v; want true, nil", cycle, err)
+	}
+}
+
+func TestDependencyCycle(t *testing.T) {
+	g := newGraph(t)
+	a, b, c, d := g.task("pending"), g.task("pending"), g.task("pending"), g.task("pending")
+	// a waits for b, b waits for c
+	g.dependsOn(a, b)
+	g.dependsOn(b, c)
+
+	cases := []struct {
+		name      string
+		taskID    string
+		dependsOn string
+		cycle     bool
+	}{
+		{"self", a, a, true},
+		{"direct", b, a, true},
+		{"indirect", c, a, true},
+		{"existing edge", a, b, false},
+		{"shortcut", a, c, false},
+		{"unrelated", d, a, false},
+		{"into unrelated", a, d, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cycle, err := DependencyCycle(context.Background(), g.tx, tc.taskID, tc.dependsOn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cycle != tc.cycle {
+				t.Fatalf("cycle = %v, want %v", cycle, tc.cycle)
+			}
+		})
+	}
+}
+
+func TestParentCycle(t *testing.T) {
+	g := newGraph(t)
+	root, child, grandchild := g.task("pending"), g.task("pending"), g.task("pending")
+	ctx := context.Background()
+	for _, edge := range [][2]string{{child, root}, {grandchild, child}} {
+		if _, err := g.tx.Exec(ctx, `UPDATE tasks SET parent_task_id = $1 WHERE task_id = $2`, edge[1], edge[0]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		name     string
+		taskID   string
+		parentID string
+		cycle    bool
+	}{
+		{"self", root, root, true},
+		{"child as parent", root, child, true},
+		{"grandchild as parent", root, grandchild, true},
+		{"sibling", grandchild, root, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cycle, err := ParentCycle(ctx, g.tx, tc.taskID, tc.parentID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cycle != tc.cycle {
+				t.Fatalf("cycle = %v, want %v", cycle, tc.cycle)
+			}
+		})
+	}
+}
+
+func TestOpenDependents(t *testing.T) {
+	g := newGraph(t)
+	ctx := context.Background()
+	first, second := g.task("accepted"), g.task("accepted")
+	blocked := g.task("blocked")
+	g.dependsOn(blocked, first)
+	g.dependsOn(blocked, second)
+
+	unmet, err := UnmetDependencies(ctx, g.tx, blocked)
+	if err != nil || unmet != 2 {
+		t.Fatalf("unmet, err = %d, %v; want 2, nil", unmet, err)
+	}
+
+	// One prerequisite done: still blocked
+	g.setStatus(first, "completed")
+	opened, err := OpenDependents(ctx, g.tx, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opened) != 0 || g.status(blocked) != "blocked" {
+		t.Fatalf("opened %v with one prerequisite open; status %s", opened, g.status(blocked))
+	}
+
+	// Both done: opens for bidding
+	g.setStatus(second, "completed")
+	opened, err = OpenDependents(ctx, g.tx, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opened) != 1 || opened[0] != blocked || g.status(blocked) != "bidding" {
+		t.Fatalf("opened = %v, status %s; want [%s], bidding", opened, g.status(blocked), blocked)
+	}
+	if unmet, err := UnmetDependencies(ctx, g.tx, blocked); err != nil || unmet != 0 {
+		t.Fatalf("unmet, err = %d, %v; want 0, nil", unmet, err)
+	}
+
+	// Running it again changes nothing
+	opened, err = OpenDependents(ctx, g.tx, second)
+	if err != nil || len(opened) != 0 {
+		t.Fatalf("second run opened %v, err %v; want none", opened, err)
+	}
+}
+
+func TestEditable(t *testing.T) {
+	for status, want := range map[string]bool{
+		"pending":   true,
+		"blocked":   true,
+		"bidding":   true,
+		"accepted":  false,
+		"completed": false,
+		"cancelled": false,
+	} {
+		if got := Editable(status); got != want {
+			t.Errorf("Editable(%q) = %v, want %v", status, got, want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/x-zero/xz-wallet/pkg/blockchain"
+)
+
+// Usage: go run ./scripts/escrow-admin [-chain ID -escrow ADDRESS] <command> [args]
+//
+// Manages timelocked emergency withdrawals on a v2 escrow with the admin key
+// (ADMIN_WALLET_PRIVATE_KEY). Without -chain/-escrow it uses the default
+// chain's current escrow from the chain registry.
+//
+//	limits                 show excess, outstanding and per-period figures
+//	list                   list proposed withdrawals
+//	propose <to> <amount>  propose withdrawing <amount> XZT of excess to <to>
+//	execute <id>           execute a withdrawal whose timelock has passed
+//	cancel <id>            cancel a proposed withdrawal
+func main() {
+	chainID := flag.Int64("chain", 0, "chain id (default: registry default chain)")
+	escrow := flag.String("escrow", "", "escrow address (default: chain's current escrow)")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(1)
+	}
+
+	if *chainID != 0 && *escrow == "" {
+		r, err := blockchain.LoadRegistry()
+		if err != nil {
+			fatal(err)
+		}
+		chain := r.Chain(*chainID)
+		if chain == nil {
+			fatal(fmt.Errorf("chain %d not in registry", *chainID))
+		}
+		*escrow = chain.EscrowAddress
+	}
+
+	client, err := blockchain.ClientFor(*chainID, *escrow)
+	if err != nil {
+		fatal(err)
+	}
+	if !client.IsEscrowV2() {
+		fatal(fmt.Errorf("escrow %s is not v2; timelocked withdrawals are not available", client.EscrowAddress.Hex()))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	args := flag.Args()
+	switch args[0] {
+	case "limits":
+		err = limits(ctx, client)
+	case "list":
+		err = list(ctx, client)
+	case "propose":
+		if len(args) != 3 {
+			usage()
+			os.Exit(1)
+		}
+		err = propose(ctx, client, args[1], args[2])
+	case "execute", "cancel":
+		if len(args) != 2 {
+			usage()
+			os.Exit(1)
+		}
+		var id uint64
+		id, err = strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			fatal(fmt.Errorf("invalid withdrawal id: %s", args[1]))
+		}
+		var txHash, done string
+		if args[0] == "execute" {
+			txHash, err = client.ExecuteWithdrawal(ctx, id)
+			done = "executed"
+		} else {
+			txHash, err = client.CancelWithdrawal(ctx, id)
+			done = "cancelled"
+		}
+		if err == nil {
+			fmt.Printf("Withdrawal %d %s (tx %s)\n", id, done, txHash)
+		}
+	default:
+		usage()
+		os.Exit(1)
+	}
+	if err != nil {
+		fatal(err)
+	}
+}
+
+func limits(ctx context.Context, client *blockchain.BlockchainClient) error {
+	l, err := client.WithdrawLimits(ctx)
+	if err != nil {
+		return err
+	}
+	remaining := new(big.Int).Sub(l.PeriodLimit, l.WithdrawnInPeriod)
+	if remaining.Sign() < 0 {
+		remaining.SetInt64(0)
+	}
+
+	fmt.Printf("Escrow:              %s (chain %s)\n", client.EscrowAddress.Hex(), client.ChainID)
+	fmt.Printf("Outstanding (tasks): %s XZT\n", blockchain.FromWei(l.Outstanding, 8))
+	fmt.Printf("Withdrawable excess: %s XZT\n", blockchain.FromWei(l.Excess, 8))
+	fmt.Printf("Period limit:        %s XZT per %s\n", blockchain.FromWei(l.PeriodLimit, 8), l.PeriodLength)
+	fmt.Printf("Left this period:    %s XZT\n", blockchain.FromWei(remaining, 8))
+	fmt.Printf("Timelock:            %s\n", l.Delay)
+	return nil
+}
+
+func list(ctx context.Context, client *blockchain.BlockchainClient) error {
+	withdrawals, err := client.Withdrawals(ctx)
+	if err != nil {
+		return err
+	}
+	if len(withdrawals) == 0 {
+		fmt.Println("No withdrawals proposed")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tTO\tAMOUNT (XZT)\tEXECUTABLE AT")
+	for _, wd := range withdrawals {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", wd.ID, wd.Status(), wd.To.Hex(),
+			blockchain.FromWei(wd.Amount, 8), wd.ExecutableAt.Format(time.RFC3339))
+	}
+	return w.Flush()
+}
+
+func propose(ctx context.Context, client *blockchain.BlockchainClient, to, amount string) error {
+	if !common.IsHexAddress(to) {
+		return fmt.Errorf("invalid address: %s", to)
+	}
+	amountWei, err := blockchain.ToWei(amount)
+	if err != nil {
+		return err
+	}
+	if amountWei.Sign() <= 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+
+	// Fail early rather than paying gas for a revert
+	l, err := client.WithdrawLimits(ctx)
+	if err != nil {
+		return err
+	}
+	if amountWei.Cmp(l.Excess) > 0 {
+		return fmt.Errorf("amount exceeds withdrawable excess of %s XZT", blockchain.FromWei(l.Excess, 8))
+	}
+
+	id, txHash, err := client.ProposeWithdrawal(ctx, common.HexToAddress(to), amountWei)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Withdrawal %d proposed (tx %s); executable after %s\n", id, txHash,
+		time.Now().Add(l.Delay).UTC().Format(time.RFC3339))
+	return nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: go run ./scripts/escrow-admin [-chain ID -escrow ADDRESS] <command> [args]")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  limits                 show excess, outstanding and per-period figures")
+	fmt.Fprintln(os.Stderr, "  list                   list proposed withdrawals")
+	fmt.Fprintln(os.Stderr, "  propose <to> <amount>  propose withdrawing <amount> XZT of excess to <to>")
+	fmt.Fprintln(os.Stderr, "  execute <id>           execute a withdrawal whose timelock has passed")
+	fmt.Fprintln(os.Stderr, "  cancel <id>            cancel a proposed withdrawal")
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "Error:", err)
+	os.Exit(1)
+}
@@ -3,33 +3,29 @@ package main
 import (
 	"fmt"
 	"os"
-	"time"
 
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/x-zero/xz-wallet/pkg/auth"
 )
 
-type Claims struct {
-	DID      string `json:"did"`
-	Username string `json:"username"`
-	jwt.RegisteredClaims
-}
-
+// Usage: JWT_SECRET=... go run scripts/test-token.go <did> [username]
+// Set JWT_SIGNING_KEY_FILE instead of JWT_SECRET to issue an RS256/EdDSA token.
 func main() {
-	secret := "Ia7gdt+1znW6j9I9XXLg+//MbKYIMa3HW5X7Eqd3gho="
-	did := "0x3070deb1c17432b094d30509ccbfd598fb2793435efdca9273dfbc558bc040ca"
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "Usage: go run scripts/test-token.go <did> [username]")
+		os.Exit(1)
+	}
+
+	did := os.Args[1]
 	username := "admin"
+	if len(os.Args) > 2 {
+		username = os.Args[2]
+	}
 
-	claims := Claims{
-		DID:      did,
-		Username: username,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+	if os.Getenv("JWT_EXPIRY") == "" {
+		os.Setenv("JWT_EXPIRY", "24h")
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(secret))
+	tokenString, err := auth.GenerateToken(did, username)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)